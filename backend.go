@@ -0,0 +1,118 @@
+package go_persistent_ds
+
+import (
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// Cell stores values of a single position of structure by versions: value of Map's key,
+// value of Slice's index or value and links of DoubleLinkedList's element.
+//
+// Read must return value, that was written exactly for given version, values for parent versions are found by
+// structures using VersionStore. Write is called with increasing versions.
+type Cell = internal.Cell
+
+// VisibleCell is a Cell, that finds value visible for version, that is the value written for the nearest
+// ancestor of version, itself. Structures don't walk the history of version to read VisibleCell.
+type VisibleCell = internal.VisibleCell
+
+// VersionStore stores the tree of versions without any data.
+// The root version is 0, and each created version must be greater by one than the previous created version.
+type VersionStore = internal.VersionStore
+
+// Backend creates storage for Map, Slice and DoubleLinkedList.
+// Each structure asks Backend for one VersionStore on creation and for new Cell on each new position.
+type Backend interface {
+	// NewVersionStore creates VersionStore, that contains only the root version.
+	NewVersionStore() VersionStore
+	// NewCell creates Cell with value written for given version. Store is the VersionStore of the structure,
+	// that was created by NewVersionStore.
+	NewCell(store VersionStore, version uint64, value any) Cell
+}
+
+// BackendOption configures Backend of Map, Slice or DoubleLinkedList.
+type BackendOption interface {
+	MapOption
+	SliceOption
+	ListOption
+}
+
+type backendOption struct {
+	backend Backend
+}
+
+func (o backendOption) applyToMap(cfg *mapConfig) {
+	cfg.backend = o.backend
+}
+
+func (o backendOption) applyToSlice(cfg *sliceConfig) {
+	cfg.backend = o.backend
+}
+
+func (o backendOption) applyToList(cfg *listConfig) {
+	cfg.backend = o.backend
+}
+
+// WithBackend makes structure use given Backend. By default, FatNodeBackend is used.
+func WithBackend(backend Backend) BackendOption {
+	return backendOption{
+		backend: backend,
+	}
+}
+
+type fatNodeBackend struct{}
+
+// FatNodeBackend returns Backend, that keeps the tree of versions with links to parents and children,
+// and keeps values of Cell in a slice sorted by version. Cell finds value for version using binary search.
+func FatNodeBackend() Backend {
+	return fatNodeBackend{}
+}
+
+func (fatNodeBackend) NewVersionStore() VersionStore {
+	return internal.NewTreeVersionStore()
+}
+
+func (fatNodeBackend) NewCell(_ VersionStore, version uint64, value any) Cell {
+	return internal.NewFatNode(value, version)
+}
+
+type hashCellBackend struct{}
+
+// HashCellBackend returns Backend, that keeps only parent of each version, and keeps values of Cell in hash map.
+// Cell finds value for version in O(1) at the cost of more memory.
+func HashCellBackend() Backend {
+	return hashCellBackend{}
+}
+
+func (hashCellBackend) NewVersionStore() VersionStore {
+	return internal.NewParentVersionStore()
+}
+
+func (hashCellBackend) NewCell(_ VersionStore, version uint64, value any) Cell {
+	return internal.NewHashCell(version, value)
+}
+
+type versionListBackend struct{}
+
+// VersionListBackend returns Backend, that keeps versions additionally in version list, which orders them
+// as preorder of the version tree. Cell stores modifications ordered by version list in sorted chunks
+// with bounded amount of slots.
+// Cells are VisibleCells, so reading value for version takes O(log(m)), there m - amount of modifications
+// of the Cell, regardless of the depth of version. It especially speeds up DoubleLinkedList traversal
+// and Map/Slice access for long histories.
+func VersionListBackend() Backend {
+	return versionListBackend{}
+}
+
+func (versionListBackend) NewVersionStore() VersionStore {
+	return internal.NewVersionListStore()
+}
+
+func (versionListBackend) NewCell(store VersionStore, version uint64, value any) Cell {
+	versionListStore, ok := store.(*internal.VersionListStore)
+	if !ok {
+		// Store was not created by this backend, so cell can't use the version list.
+		return internal.NewFatNode(value, version)
+	}
+
+	return internal.NewVersionListCell(versionListStore, version, value)
+}
@@ -0,0 +1,190 @@
+package go_persistent_ds
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// countingBackend counts created cells and versions to check that structures use given Backend.
+type countingBackend struct {
+	Backend
+
+	cells  int
+	stores int
+}
+
+func (b *countingBackend) NewVersionStore() VersionStore {
+	b.stores++
+	return b.Backend.NewVersionStore()
+}
+
+func (b *countingBackend) NewCell(store VersionStore, version uint64, value any) Cell {
+	b.cells++
+	return b.Backend.NewCell(store, version, value)
+}
+
+func TestWithBackend(t *testing.T) {
+	backends := map[string]Backend{
+		"FatNodeBackend":     FatNodeBackend(),
+		"HashCellBackend":    HashCellBackend(),
+		"VersionListBackend": VersionListBackend(),
+	}
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("Map", func(t *testing.T) {
+				counting := &countingBackend{Backend: backend}
+				m, version := NewMap[string, int](WithBackend(counting))
+
+				v1, err := m.Set(version, "a", 1)
+				errIsNil(t, err)
+				v2, err := m.Set(v1, "b", 2)
+				errIsNil(t, err)
+				v3, err := m.Set(v1, "a", 3)
+				errIsNil(t, err)
+				v4, err := m.Delete(v2, "a")
+				errIsNil(t, err)
+
+				for v, expected := range map[uint64]map[string]int{
+					v1: {"a": 1},
+					v2: {"a": 1, "b": 2},
+					v3: {"a": 3},
+					v4: {"b": 2},
+				} {
+					got, err := m.ToGoMap(v)
+					errIsNil(t, err)
+					isTrue(t, maps.Equal(got, expected))
+				}
+
+				isTrue(t, counting.stores == 1)
+				isTrue(t, counting.cells == 2)
+			})
+
+			t.Run("Slice", func(t *testing.T) {
+				counting := &countingBackend{Backend: backend}
+				s, version := NewSlice[int](WithBackend(counting))
+
+				v1, err := s.Append(version, 1)
+				errIsNil(t, err)
+				v2, err := s.Append(v1, 2)
+				errIsNil(t, err)
+				v3, err := s.Set(v2, 0, 3)
+				errIsNil(t, err)
+
+				sliceShouldBe(t, s, v2, []int{1, 2})
+				sliceShouldBe(t, s, v3, []int{3, 2})
+
+				isTrue(t, counting.stores == 1)
+				isTrue(t, counting.cells == 2)
+			})
+
+			t.Run("DoubleLinkedList", func(t *testing.T) {
+				counting := &countingBackend{Backend: backend}
+				l, version := NewDoubleLinkedList[int](WithBackend(counting))
+
+				v1, err := l.PushBack(version, 1)
+				errIsNil(t, err)
+				v2, err := l.PushBack(v1, 2)
+				errIsNil(t, err)
+				v3, err := l.PushFront(v2, 0)
+				errIsNil(t, err)
+				v4, err := l.Remove(v3, 1)
+				errIsNil(t, err)
+
+				listShouldBe(t, l, v2, []int{1, 2})
+				listShouldBe(t, l, v3, []int{0, 1, 2})
+				listShouldBe(t, l, v4, []int{0, 2})
+
+				isTrue(t, counting.stores == 1)
+				isTrue(t, counting.cells > 0)
+
+				// wrapping backend keeps cells visible, so list doesn't compute history for them.
+				_, visible := backend.NewCell(backend.NewVersionStore(), 0, nil).(VisibleCell)
+				isTrue(t, l.visibleCells == visible)
+				isTrue(t, l.visibleCells == (name == "VersionListBackend"))
+			})
+		})
+	}
+}
+
+func TestWithBackend_AndOtherOptions(t *testing.T) {
+	m, version := NewMap[string, int](WithBackend(HashCellBackend()), WithHAMT())
+	version, err := m.Set(version, "a", 1)
+	errIsNil(t, err)
+
+	val, err := m.Get(version, "a")
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+
+	s, version := NewSlice[int](WithRRBTree(), WithBackend(HashCellBackend()))
+	version, err = s.Append(version, 1)
+	errIsNil(t, err)
+
+	slice, err := s.ToGoSlice(version)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []int{1}))
+}
+
+// BenchmarkDoubleLinkedList_HeavilyUpdatedLinks traverses the list, which links between first elements
+// were updated in each of many previous versions.
+func BenchmarkDoubleLinkedList_HeavilyUpdatedLinks(b *testing.B) {
+	backends := map[string]Backend{
+		"FatNodeBackend":     FatNodeBackend(),
+		"HashCellBackend":    HashCellBackend(),
+		"VersionListBackend": VersionListBackend(),
+	}
+
+	for name, backend := range backends {
+		b.Run(name, func(b *testing.B) {
+			l, version := NewDoubleLinkedList[int](WithBackend(backend))
+
+			var err error
+			for i := 0; i < 64; i++ {
+				version, err = l.PushBack(version, i)
+				errIsNil(b, err)
+			}
+			for i := 0; i < 2000; i++ {
+				version, err = l.InsertAt(version, 1, i)
+				errIsNil(b, err)
+				version, err = l.Remove(version, 1)
+				errIsNil(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err = l.Get(version, 32)
+				errIsNil(b, err)
+			}
+		})
+	}
+}
+
+// BenchmarkMap_LongHistory reads key, that was set at the beginning of long history.
+func BenchmarkMap_LongHistory(b *testing.B) {
+	backends := map[string]Backend{
+		"FatNodeBackend":     FatNodeBackend(),
+		"HashCellBackend":    HashCellBackend(),
+		"VersionListBackend": VersionListBackend(),
+	}
+
+	for name, backend := range backends {
+		b.Run(name, func(b *testing.B) {
+			m, version := NewMap[int, int](WithBackend(backend))
+
+			var err error
+			for i := 0; i < 4000; i++ {
+				version, err = m.Set(version, i%100, i)
+				errIsNil(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err = m.Get(version, 0)
+				errIsNil(b, err)
+			}
+		})
+	}
+}
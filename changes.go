@@ -0,0 +1,330 @@
+package go_persistent_ds
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// ErrNotAncestor is returned by Changes if given version is not on the path from the initial version to the last one.
+var ErrNotAncestor = errors.New("version is not an ancestor of the last version")
+
+// ChangesOption configures the channel returned by Changes.
+type ChangesOption interface {
+	applyToChanges(cfg *changesConfig)
+}
+
+type changesConfig struct {
+	buffer       int
+	backPressure bool
+}
+
+type changesOptionFunc func(cfg *changesConfig)
+
+func (f changesOptionFunc) applyToChanges(cfg *changesConfig) {
+	f(cfg)
+}
+
+// JournalOption configures Map, Slice or DoubleLinkedList to keep journal of changes.
+type JournalOption interface {
+	MapOption
+	SliceOption
+	ListOption
+}
+
+type journalOption struct{}
+
+func (journalOption) applyToMap(cfg *mapConfig) {
+	cfg.journal = true
+}
+
+func (journalOption) applyToSlice(cfg *sliceConfig) {
+	cfg.journal = true
+}
+
+func (journalOption) applyToList(cfg *listConfig) {
+	cfg.journal = true
+}
+
+// WithJournal makes structure keep event of each version, so Changes, Compact with WithVersionMapping
+// and ExportOps replay modifications as they were made. Values of events are not garbage collected then.
+// Without journal events of existing versions are rebuilt from their content on demand as OpReplace events
+// with all values of the version, and events are built on modification only if there are subscribers.
+func WithJournal() JournalOption {
+	return journalOption{}
+}
+
+// WithChangesBuffer sets capacity of the channel returned by Changes. By default, the channel is unbuffered.
+func WithChangesBuffer(size int) ChangesOption {
+	return changesOptionFunc(func(cfg *changesConfig) {
+		cfg.buffer = size
+	})
+}
+
+// WithBackPressure makes each modification wait until its event is sent to the channel returned by Changes
+// or the context is done. Events are replayed before, so modifications also wait for the replay.
+// By default, events, that don't fit into the channel, are queued without limit and modifications never wait.
+func WithBackPressure() ChangesOption {
+	return changesOptionFunc(func(cfg *changesConfig) {
+		cfg.backPressure = true
+	})
+}
+
+// Changes returns channel of events of Map modifications in version order. First, events of versions on the path
+// from fromVersion to the last version are replayed, then events of new versions are delivered as they are created,
+// including versions created from other branches. The channel is closed after ctx is done.
+// By default, the channel is unbuffered and events, that are not received yet, are queued without limit,
+// see WithChangesBuffer and WithBackPressure.
+// If fromVersion is not on the path from the initial version to the last one ErrNotAncestor is returned.
+//
+// Replayed events are taken from journal of Map created with WithJournal option. Otherwise, they are rebuilt
+// from content of versions as OpReplace events with map of all keys and values as NewValue.
+//
+// Complexity: O(d), where d - depth of the last version. Without journal plus O(ToGoMap) for each replayed version.
+func (m *Map[TKey, TVal]) Changes(ctx context.Context, fromVersion uint64, opts ...ChangesOption) (<-chan ChangeEvent, error) {
+	return m.changes(ctx, fromVersion, opts, m.rebuildEvent)
+}
+
+// Changes returns channel of events of Slice modifications in version order, see Map.Changes.
+//
+// Complexity: O(d), where d - depth of the last version. Without journal plus O(ToGoSlice) for each replayed version.
+func (s *Slice[TVal]) Changes(ctx context.Context, fromVersion uint64, opts ...ChangesOption) (<-chan ChangeEvent, error) {
+	return s.changes(ctx, fromVersion, opts, s.rebuildEvent)
+}
+
+// Changes returns channel of events of DoubleLinkedList modifications in version order, see Map.Changes.
+//
+// Complexity: O(d), where d - depth of the last version. Without journal plus O(ToGoSlice) for each replayed version.
+func (l *DoubleLinkedList[T]) Changes(ctx context.Context, fromVersion uint64, opts ...ChangesOption) (<-chan ChangeEvent, error) {
+	return l.changes(ctx, fromVersion, opts, l.rebuildEvent)
+}
+
+// rebuildEvent returns OpReplace event, that creates version from its parent, with all keys and values of version.
+func (m *Map[TKey, TVal]) rebuildEvent(version uint64) (ChangeEvent, error) {
+	values, err := m.ToGoMap(version)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+
+	return m.replacement(version, values, len(values)), nil
+}
+
+// rebuildEvent returns OpReplace event, that creates version from its parent, with all values of version.
+func (s *Slice[TVal]) rebuildEvent(version uint64) (ChangeEvent, error) {
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+
+	return s.replacement(version, values, len(values)), nil
+}
+
+// rebuildEvent returns OpReplace event, that creates version from its parent, with all values of version.
+func (l *DoubleLinkedList[T]) rebuildEvent(version uint64) (ChangeEvent, error) {
+	values, err := l.values(version)
+	if err != nil {
+		return ChangeEvent{}, err
+	}
+
+	return l.replacement(version, values, len(values)), nil
+}
+
+// replacement returns OpReplace event, that creates version from its parent with given values.
+func (h versionHistory[T]) replacement(version uint64, values any, count int) ChangeEvent {
+	parent, _ := h.versionTree.Parent(version)
+
+	return ChangeEvent{
+		Parent:   parent,
+		Version:  version,
+		Op:       OpReplace,
+		Count:    count,
+		NewValue: values,
+	}
+}
+
+// event returns journaled event of version or rebuilds it, if version is not journaled.
+func (h versionHistory[T]) event(version uint64, rebuild func(version uint64) (ChangeEvent, error)) (ChangeEvent, error) {
+	h.subscribers.mu.Lock()
+	event, ok := h.subscribers.journaled(version)
+	h.subscribers.mu.Unlock()
+
+	if ok {
+		return event, nil
+	}
+
+	return rebuild(version)
+}
+
+// changes replays events and subscribes to new ones.
+func (h versionHistory[T]) changes(
+	ctx context.Context,
+	fromVersion uint64,
+	opts []ChangesOption,
+	rebuild func(version uint64) (ChangeEvent, error),
+) (<-chan ChangeEvent, error) {
+	cfg := changesConfig{}
+	for _, opt := range opts {
+		opt.applyToChanges(&cfg)
+	}
+
+	h.subscribers.mu.Lock()
+	defer h.subscribers.mu.Unlock()
+
+	lastVersion := h.versionTree.LastVersion()
+	replay, err := h.replay(fromVersion, lastVersion, rebuild)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &changeFeed{
+		ctx:          ctx,
+		out:          make(chan ChangeEvent, cfg.buffer),
+		backPressure: cfg.backPressure,
+		replayed:     lastVersion,
+		queue:        replay,
+		wake:         make(chan struct{}, 1),
+	}
+	go feed.run(h.subscribers.add(feed.push))
+
+	return feed.out, nil
+}
+
+// replay returns events of versions on the path from fromVersion to lastVersion. Events, that are not journaled,
+// are rebuilt by rebuild. Lock must be held.
+func (h versionHistory[T]) replay(
+	fromVersion, lastVersion uint64,
+	rebuild func(version uint64) (ChangeEvent, error),
+) ([]ChangeEvent, error) {
+	if _, err := h.versionTree.GetVersionInfo(fromVersion); err != nil {
+		return nil, err
+	}
+
+	var events []ChangeEvent
+	for version := lastVersion; version != fromVersion; {
+		parent, ok := h.versionTree.Parent(version)
+		if !ok {
+			return nil, ErrNotAncestor
+		}
+
+		event, ok := h.subscribers.journaled(version)
+		if !ok {
+			rebuilt, rebuildErr := rebuild(version)
+			if rebuildErr != nil {
+				return nil, rebuildErr
+			}
+			event = rebuilt
+		}
+
+		events = append(events, event)
+		version = parent
+	}
+	slices.Reverse(events)
+
+	return events, nil
+}
+
+// changeFeed sends events to the channel returned by Changes. Events, that are not sent yet, are kept in queue.
+// Without back pressure events are sent by the goroutine of run, otherwise they are sent by push,
+// that is called on modification.
+type changeFeed struct {
+	ctx          context.Context
+	out          chan ChangeEvent
+	backPressure bool
+	// replayed is the last version at the moment of replay. Versions up to it were created before Changes,
+	// so their events, that are pushed by modifications still in progress, are dropped: they are already replayed
+	// or belong to other branches.
+	replayed uint64
+
+	mu     sync.Mutex
+	queue  []ChangeEvent
+	wake   chan struct{}
+	closed bool
+}
+
+// push is called on each modification.
+func (f *changeFeed) push(ev ChangeEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed || ev.Version <= f.replayed {
+		return
+	}
+
+	f.queue = append(f.queue, ev)
+	if f.backPressure {
+		f.flush()
+		return
+	}
+
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run sends queued events until the context is done, then unsubscribes and closes the channel.
+func (f *changeFeed) run(unsubscribe func()) {
+	defer func() {
+		unsubscribe()
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		f.closed = true
+		close(f.out)
+	}()
+
+	if f.backPressure {
+		f.mu.Lock()
+		delivered := f.flush()
+		f.mu.Unlock()
+
+		if delivered {
+			<-f.ctx.Done()
+		}
+
+		return
+	}
+
+	for {
+		f.mu.Lock()
+		events := f.queue
+		f.queue = nil
+		f.mu.Unlock()
+
+		for _, ev := range events {
+			if !f.send(ev) {
+				return
+			}
+		}
+
+		select {
+		case <-f.wake:
+		case <-f.ctx.Done():
+			return
+		}
+	}
+}
+
+// flush sends all queued events. Lock must be held. If the context is done, false is returned.
+func (f *changeFeed) flush() bool {
+	for len(f.queue) > 0 {
+		if !f.send(f.queue[0]) {
+			return false
+		}
+		f.queue = f.queue[1:]
+	}
+
+	return true
+}
+
+// send sends event to the channel. If the context is done, false is returned.
+func (f *changeFeed) send(ev ChangeEvent) bool {
+	select {
+	case f.out <- ev:
+		return true
+	case <-f.ctx.Done():
+		return false
+	}
+}
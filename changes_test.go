@@ -0,0 +1,178 @@
+package go_persistent_ds
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+func receiveEvent(t *testing.T, changes <-chan ChangeEvent) ChangeEvent {
+	ev, ok := <-changes
+	isTrue(t, ok)
+
+	return ev
+}
+
+func TestMap_Changes(t *testing.T) {
+	m, v0 := NewMap[string, int](WithJournal())
+
+	v1, err := m.Set(v0, "a", 1)
+	errIsNil(t, err)
+	v2, err := m.Set(v1, "b", 2)
+	errIsNil(t, err)
+	branch, err := m.Set(v0, "c", 3)
+	errIsNil(t, err)
+	v3, err := m.Delete(v2, "a")
+	errIsNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := m.Changes(ctx, v1)
+	errIsNil(t, err)
+
+	ev := receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: v1, Version: v2, Op: OpSet, Key: "b", NewValue: 2})
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: v2, Version: v3, Op: OpDelete, Key: "a", OldValue: 1})
+
+	v4, err := m.Set(v3, "b", 4)
+	errIsNil(t, err)
+	v5, err := m.Set(branch, "c", 5)
+	errIsNil(t, err)
+
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: v3, Version: v4, Op: OpSet, Key: "b", OldValue: 2, NewValue: 4})
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: branch, Version: v5, Op: OpSet, Key: "c", OldValue: 3, NewValue: 5})
+
+	cancel()
+	for range changes {
+	}
+
+	_, err = m.Set(v5, "d", 6)
+	errIsNil(t, err)
+
+	_, err = m.Changes(context.Background(), v1)
+	errShouldBe(t, err, ErrNotAncestor)
+
+	_, err = m.Changes(context.Background(), v5+2)
+	errShouldBe(t, err, internal.ErrVersionNotFound)
+}
+
+func TestChanges_WithoutJournal(t *testing.T) {
+	s, v0 := NewSlice[int]()
+
+	v1, err := s.Append(v0, 1)
+	errIsNil(t, err)
+	v2, err := s.Set(v1, 0, 3)
+	errIsNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := s.Changes(ctx, v0)
+	errIsNil(t, err)
+
+	ev := receiveEvent(t, changes)
+	isTrue(t, ev.Parent == v0 && ev.Version == v1 && ev.Op == OpReplace && ev.Count == 1)
+	isTrue(t, slices.Equal(ev.NewValue.([]int), []int{1}))
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev.Parent == v1 && ev.Version == v2 && ev.Op == OpReplace && ev.Count == 1)
+	isTrue(t, slices.Equal(ev.NewValue.([]int), []int{3}))
+
+	v3, err := s.Append(v2, 4)
+	errIsNil(t, err)
+
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: v2, Version: v3, Op: OpAppend, Index: 1, NewValue: 4})
+}
+
+func TestChanges_FromLastVersion(t *testing.T) {
+	s, version := NewSlice[int](WithRRBTree())
+	version, err := s.Append(version, 1)
+	errIsNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := s.Changes(ctx, version, WithChangesBuffer(2))
+	errIsNil(t, err)
+
+	v1, err := s.Append(version, 2)
+	errIsNil(t, err)
+	v2, err := s.Concat(v1, s, version)
+	errIsNil(t, err)
+
+	ev := receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: version, Version: v1, Op: OpAppend, Index: 1, NewValue: 2})
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev.Op == OpConcat && ev.Version == v2 && ev.Count == 1)
+}
+
+func TestChanges_ModificationInProgress(t *testing.T) {
+	m, v0 := NewMap[string, int](WithJournal())
+	v1, err := m.Set(v0, "a", 1)
+	errIsNil(t, err)
+
+	// modification has created version, but hasn't notified subscribers yet.
+	inProgress, err := m.versionTree.Update(v1)
+	errIsNil(t, err)
+	info, err := m.versionTree.GetVersionInfo(v1)
+	errIsNil(t, err)
+	errIsNil(t, m.versionTree.SetVersionInfo(inProgress, *info))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := m.Changes(ctx, v1, WithChangesBuffer(2))
+	errIsNil(t, err)
+
+	m.notify(v1, inProgress, ChangeEvent{Op: OpSet, Key: "a", OldValue: 1, NewValue: 1})
+	v3, err := m.Set(inProgress, "b", 2)
+	errIsNil(t, err)
+
+	ev := receiveEvent(t, changes)
+	isTrue(t, ev.Version == inProgress && ev.Op == OpReplace)
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: inProgress, Version: v3, Op: OpSet, Key: "b", NewValue: 2})
+}
+
+func TestChanges_BackPressure(t *testing.T) {
+	l, version := NewDoubleLinkedList[int](WithJournal())
+	version, err := l.PushBack(version, 1)
+	errIsNil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, err := l.Changes(ctx, 0, WithBackPressure())
+	errIsNil(t, err)
+
+	done := make(chan uint64)
+	go func() {
+		newVersion, pushErr := l.PushFront(version, 0)
+		errIsNil(t, pushErr)
+		done <- newVersion
+	}()
+
+	ev := receiveEvent(t, changes)
+	isTrue(t, ev == ChangeEvent{Parent: 0, Version: version, Op: OpPushBack, NewValue: 1})
+
+	// the modification can't complete until its event is received.
+	select {
+	case <-done:
+		t.Errorf("modification completed before its event was received")
+	default:
+	}
+
+	ev = receiveEvent(t, changes)
+	isTrue(t, ev.Op == OpPushFront && ev.Parent == version && ev.NewValue == 0)
+	versionShouldBe(t, <-done, ev.Version)
+
+	cancel()
+	_, ok := <-changes
+	isTrue(t, !ok)
+}
@@ -0,0 +1,104 @@
+package go_persistent_ds
+
+import (
+	"maps"
+)
+
+// CompactOption configures Compact.
+type CompactOption interface {
+	applyToCompact(cfg *compactConfig)
+}
+
+type compactConfig struct {
+	mapping map[uint64]uint64
+}
+
+type compactOptionFunc func(cfg *compactConfig)
+
+func (f compactOptionFunc) applyToCompact(cfg *compactConfig) {
+	f(cfg)
+}
+
+// WithVersionMapping makes Compact retain descendants of the compacted version as well.
+// Mapping is filled with new versions of the compacted version and its descendants by their old versions.
+// Other versions are not retained.
+func WithVersionMapping(mapping map[uint64]uint64) CompactOption {
+	return compactOptionFunc(func(cfg *compactConfig) {
+		cfg.mapping = mapping
+	})
+}
+
+// retainDescendants copies info of descendants of version into compacted structure, whose initial version
+// has content of version, if WithVersionMapping is given. It is used by structures, that keep immutable info.
+func (h versionHistory[T]) retainDescendants(compacted versionHistory[T], version uint64, opts []CompactOption) error {
+	return h.retain(version, opts, func(newParent, oldVersion uint64) (uint64, error) {
+		info, err := h.versionTree.GetVersionInfo(oldVersion)
+		if err != nil {
+			return 0, err
+		}
+
+		newVersion, err := compacted.versionTree.Update(newParent)
+		if err != nil {
+			return 0, err
+		}
+		_ = compacted.versionTree.SetVersionInfo(newVersion, *info)
+
+		return newVersion, nil
+	})
+}
+
+// replayDescendants applies events of descendants of version to compacted structure, whose initial
+// version has content of version, if WithVersionMapping is given. Apply gets event of old version
+// and new version of its parent. Events, that are not journaled, are rebuilt by rebuild.
+func (h versionHistory[T]) replayDescendants(
+	version uint64,
+	opts []CompactOption,
+	rebuild func(version uint64) (ChangeEvent, error),
+	apply func(newParent uint64, event ChangeEvent) (uint64, error),
+) error {
+	return h.retain(version, opts, func(newParent, oldVersion uint64) (uint64, error) {
+		event, err := h.event(oldVersion, rebuild)
+		if err != nil {
+			return 0, err
+		}
+
+		return apply(newParent, event)
+	})
+}
+
+// retain creates new versions for descendants of version in order of their creation and fills mapping
+// given by WithVersionMapping. The compacted version becomes the initial one.
+func (h versionHistory[T]) retain(
+	version uint64,
+	opts []CompactOption,
+	create func(newParent, oldVersion uint64) (uint64, error),
+) error {
+	cfg := compactConfig{}
+	for _, opt := range opts {
+		opt.applyToCompact(&cfg)
+	}
+	if cfg.mapping == nil {
+		return nil
+	}
+
+	retained := map[uint64]uint64{
+		version: 0,
+	}
+	for oldVersion := version + 1; oldVersion <= h.LastVersion(); oldVersion++ {
+		parent, _ := h.Parent(oldVersion)
+		newParent, ok := retained[parent]
+		if !ok {
+			continue
+		}
+
+		newVersion, err := create(newParent, oldVersion)
+		if err != nil {
+			return err
+		}
+		retained[oldVersion] = newVersion
+	}
+
+	maps.Copy(cfg.mapping, retained)
+
+	return nil
+}
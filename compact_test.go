@@ -0,0 +1,255 @@
+package go_persistent_ds
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCompact_Map(t *testing.T) {
+	options := map[string][]MapOption{
+		"Cells":              nil,
+		"HAMT":               {WithHAMT()},
+		"VersionListBackend": {WithBackend(VersionListBackend())},
+	}
+
+	for name, opts := range options {
+		t.Run(name, func(t *testing.T) {
+			m, v0 := NewMap[string, int](opts...)
+
+			v1, err := m.Set(v0, "a", 1)
+			errIsNil(t, err)
+			v2, err := m.Set(v1, "b", 2)
+			errIsNil(t, err)
+			_, err = m.Set(v0, "c", 3)
+			errIsNil(t, err)
+			v4, err := m.Delete(v2, "a")
+			errIsNil(t, err)
+			v5, err := m.Set(v2, "b", 5)
+			errIsNil(t, err)
+
+			compacted, err := m.Compact(v2)
+			errIsNil(t, err)
+			versionShouldBe(t, compacted.LastVersion(), 0)
+
+			equal, err := EqualKeyed[string, int](m, v2, compacted, 0)
+			errIsNil(t, err)
+			isTrue(t, equal)
+
+			newVersion, err := compacted.Set(0, "a", 10)
+			errIsNil(t, err)
+			versionShouldBe(t, newVersion, 1)
+			val, err := m.Get(v2, "a")
+			errIsNil(t, err)
+			isTrue(t, val == 1)
+
+			mapping := make(map[uint64]uint64)
+			compacted, err = m.Compact(v1, WithVersionMapping(mapping))
+			errIsNil(t, err)
+			isTrue(t, maps.Equal(mapping, map[uint64]uint64{v1: 0, v2: 1, v4: 2, v5: 3}))
+
+			for oldVersion, newVersion := range mapping {
+				equal, err = EqualKeyed[string, int](m, oldVersion, compacted, newVersion)
+				errIsNil(t, err)
+				isTrue(t, equal)
+			}
+
+			parent, ok := compacted.Parent(mapping[v5])
+			isTrue(t, ok)
+			versionShouldBe(t, parent, mapping[v2])
+
+			_, err = m.Compact(v5 + 1)
+			isTrue(t, err != nil)
+		})
+	}
+}
+
+func TestCompact_Indexed(t *testing.T) {
+	for name, opts := range map[string][]SliceOption{"Cells": nil, "RRB-tree": {WithRRBTree()}} {
+		t.Run("Slice with "+name, func(t *testing.T) {
+			s, version := NewSlice[int](opts...)
+
+			var err error
+			for i := 0; i < 5; i++ {
+				version, err = s.Append(version, i)
+				errIsNil(t, err)
+			}
+
+			modifications := []func(v uint64) (uint64, error){
+				func(v uint64) (uint64, error) { return s.Set(v, 1, 10) },
+				func(v uint64) (uint64, error) { return s.Prepend(v, -1) },
+				func(v uint64) (uint64, error) { return s.Range(v, 1, 4) },
+				func(v uint64) (uint64, error) { return s.Concat(v, s, version) },
+				func(v uint64) (uint64, error) { return s.Append(v, 20) },
+				func(v uint64) (uint64, error) { return Filter[int](s, v, func(val int) bool { return val != 10 }) },
+				func(v uint64) (uint64, error) { return s.Prepend(v, -3, -2) },
+				func(v uint64) (uint64, error) { return s.Resize(v, 12, 7) },
+				func(v uint64) (uint64, error) { return s.Truncate(v, 9) },
+				func(v uint64) (uint64, error) {
+					_, newVersion, popErr := s.Pop(v)
+					return newVersion, popErr
+				},
+			}
+			indexedShouldBeCompacted(t, s, version, modifications, func(mapping map[uint64]uint64) (Indexed[int], error) {
+				return s.Compact(version, WithVersionMapping(mapping))
+			})
+		})
+	}
+
+	t.Run("DoubleLinkedList", func(t *testing.T) {
+		l, version := NewDoubleLinkedList[int]()
+
+		var err error
+		for i := 0; i < 5; i++ {
+			version, err = l.PushBack(version, i)
+			errIsNil(t, err)
+		}
+
+		modifications := []func(v uint64) (uint64, error){
+			func(v uint64) (uint64, error) { return l.Set(v, 1, 10) },
+			func(v uint64) (uint64, error) { return l.PushFront(v, -1) },
+			func(v uint64) (uint64, error) { return l.InsertAt(v, 2, 7) },
+			func(v uint64) (uint64, error) { return l.Remove(v, 3) },
+			func(v uint64) (uint64, error) { return l.Splice(v, 1, l, version) },
+			func(v uint64) (uint64, error) {
+				_, newVersion, popErr := l.PopBack(v)
+				return newVersion, popErr
+			},
+			func(v uint64) (uint64, error) {
+				c, cursorErr := l.CursorAt(v, 2)
+				if cursorErr != nil {
+					return 0, cursorErr
+				}
+				_, newVersion, insertErr := c.InsertAfter(30)
+				return newVersion, insertErr
+			},
+			func(v uint64) (uint64, error) { return SortSlice[int](l, v, cmp.Compare[int]) },
+		}
+		indexedShouldBeCompacted(t, l, version, modifications, func(mapping map[uint64]uint64) (Indexed[int], error) {
+			return l.Compact(version, WithVersionMapping(mapping))
+		})
+
+		empty, err := l.Compact(0)
+		errIsNil(t, err)
+		size, err := empty.Len(0)
+		errIsNil(t, err)
+		isTrue(t, size == 0)
+	})
+}
+
+// indexedShouldBeCompacted applies modifications to version one after another and checks, that compacted structure
+// retains all of them.
+func indexedShouldBeCompacted(
+	t *testing.T,
+	original Indexed[int],
+	version uint64,
+	modifications []func(v uint64) (uint64, error),
+	compact func(mapping map[uint64]uint64) (Indexed[int], error),
+) {
+	current := version
+	for _, modify := range modifications {
+		var err error
+		current, err = modify(current)
+		errIsNil(t, err)
+	}
+
+	mapping := make(map[uint64]uint64)
+	compacted, err := compact(mapping)
+	errIsNil(t, err)
+	isTrue(t, len(mapping) == len(modifications)+1)
+	versionShouldBe(t, mapping[version], 0)
+
+	for oldVersion, newVersion := range mapping {
+		diffs, diffErr := Diff[int](original, oldVersion, compacted, newVersion)
+		errIsNil(t, diffErr)
+		if len(diffs) != 0 {
+			t.Errorf("version %d differs from compacted version %d: %v", oldVersion, newVersion, diffs)
+		}
+	}
+}
+
+func TestCompact_Immutable(t *testing.T) {
+	d, version := NewDeque[int]()
+
+	var err error
+	for i := 0; i < 3; i++ {
+		version, err = d.PushFront(version, i)
+		errIsNil(t, err)
+	}
+	child, err := d.PushBack(version, 10)
+	errIsNil(t, err)
+	_, _, err = d.PopFront(0)
+	isTrue(t, err != nil)
+
+	mapping := make(map[uint64]uint64)
+	compacted, err := d.Compact(version, WithVersionMapping(mapping))
+	errIsNil(t, err)
+	isTrue(t, maps.Equal(mapping, map[uint64]uint64{version: 0, child: 1}))
+
+	values, err := compacted.ToGoSlice(1)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []int{2, 1, 0, 10}))
+
+	r, rv := NewRopeFromString("hello")
+	compactedRope, err := r.Compact(rv)
+	errIsNil(t, err)
+	text, err := compactedRope.String(0)
+	errIsNil(t, err)
+	isTrue(t, text == "hello")
+
+	om, omv := NewOrderedMap[int, string]()
+	omv, err = om.Set(omv, 1, "a")
+	errIsNil(t, err)
+	compactedOrdered, err := om.Compact(omv)
+	errIsNil(t, err)
+	newVersion, err := compactedOrdered.Set(0, 2, "b")
+	errIsNil(t, err)
+	key, _, err := compactedOrdered.At(newVersion, 1)
+	errIsNil(t, err)
+	isTrue(t, key == 2)
+
+	pq, pqv := NewOrderedPriorityQueue[int]()
+	pqv, err = pq.Push(pqv, 1)
+	errIsNil(t, err)
+	compactedQueue, err := pq.Compact(pqv)
+	errIsNil(t, err)
+	_, err = compactedQueue.Push(0, 0)
+	errIsNil(t, err)
+	minValue, err := compactedQueue.PeekMin(1)
+	errIsNil(t, err)
+	isTrue(t, minValue == 0)
+}
+
+// BenchmarkMap_Compact compares Compact with rebuilding Map by Set for each key.
+func BenchmarkMap_Compact(b *testing.B) {
+	m, version := NewMap[int, int]()
+
+	var err error
+	for i := 0; i < 4000; i++ {
+		version, err = m.Set(version, i%1000, i)
+		errIsNil(b, err)
+	}
+
+	b.Run("Compact", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, err = m.Compact(version)
+			errIsNil(b, err)
+		}
+	})
+
+	b.Run("Set", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			values, toGoErr := m.ToGoMap(version)
+			errIsNil(b, toGoErr)
+
+			rebuilt, rebuiltVersion := NewMap[int, int]()
+			for key, val := range values {
+				rebuiltVersion, err = rebuilt.Set(rebuiltVersion, key, val)
+				errIsNil(b, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,429 @@
+package go_persistent_ds
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// Versioned is implemented by all persistent structures of the package.
+type Versioned interface {
+	// Len returns the amount of elements in structure of given version.
+	Len(version uint64) (int, error)
+	// Parent returns the version, which given version was created from.
+	// If version is the initial one or doesn't exist, false is returned.
+	Parent(version uint64) (uint64, bool)
+	// History returns versions on the path from the initial version to given version.
+	History(version uint64) ([]uint64, error)
+	// LastVersion returns the last created version.
+	LastVersion() uint64
+}
+
+// Indexed is a persistent structure, which elements are accessed by index: Slice and DoubleLinkedList.
+type Indexed[T any] interface {
+	Versioned
+	// Get returns element by index for given version.
+	Get(version uint64, index int) (T, error)
+	// Set sets element by index for given version and returns new version.
+	Set(version uint64, index int, value T) (uint64, error)
+	// ToGoSlice returns all elements of given version in order.
+	ToGoSlice(version uint64) ([]T, error)
+}
+
+// Keyed is a persistent structure, which elements are accessed by key: Map, OrderedMap and Trie.
+type Keyed[K comparable, V any] interface {
+	Versioned
+	// Get returns value by key for given version.
+	Get(version uint64, key K) (V, error)
+	// Set sets value by key for given version and returns new version.
+	Set(version uint64, key K, value V) (uint64, error)
+	// Delete deletes key for given version and returns new version.
+	Delete(version uint64, key K) (uint64, error)
+	// ToGoMap returns all keys and values of given version.
+	ToGoMap(version uint64) (map[K]V, error)
+}
+
+// Forkable is a persistent structure, which version can be split off into independent structure of type S,
+// that is the structure itself. Fork is not a part of Versioned, because its result type depends on the structure.
+type Forkable[S any] interface {
+	Versioned
+	// Fork creates new structure, which initial version shares content of given version.
+	// The new structure and the original one can be modified concurrently.
+	Fork(version uint64) (S, error)
+}
+
+var (
+	_ Indexed[int]       = (*Slice[int])(nil)
+	_ Indexed[int]       = (*DoubleLinkedList[int])(nil)
+	_ Keyed[string, int] = (*Map[string, int])(nil)
+	_ Keyed[string, int] = (*OrderedMap[string, int])(nil)
+	_ Keyed[string, int] = (*Trie[int])(nil)
+	_ Versioned          = (*Deque[int])(nil)
+	_ Versioned          = (*Stack[int])(nil)
+	_ Versioned          = (*Queue[int])(nil)
+	_ Versioned          = (*PriorityQueue[int])(nil)
+	_ Versioned          = (*Rope)(nil)
+
+	_ Forkable[*Slice[int]]              = (*Slice[int])(nil)
+	_ Forkable[*DoubleLinkedList[int]]   = (*DoubleLinkedList[int])(nil)
+	_ Forkable[*Map[string, int]]        = (*Map[string, int])(nil)
+	_ Forkable[*OrderedMap[string, int]] = (*OrderedMap[string, int])(nil)
+	_ Forkable[*Trie[int]]               = (*Trie[int])(nil)
+	_ Forkable[*Deque[int]]              = (*Deque[int])(nil)
+	_ Forkable[*Stack[int]]              = (*Stack[int])(nil)
+	_ Forkable[*Queue[int]]              = (*Queue[int])(nil)
+	_ Forkable[*PriorityQueue[int]]      = (*PriorityQueue[int])(nil)
+	_ Forkable[*Rope]                    = (*Rope)(nil)
+)
+
+// versionHistory is embedded into each structure, answers version tree queries of Versioned
+// and notifies subscribers about modifications.
+type versionHistory[T any] struct {
+	versionTree *internal.VersionTree[T]
+	subscribers *subscribers
+}
+
+func newVersionHistory[T any](versionTree *internal.VersionTree[T]) versionHistory[T] {
+	return versionHistory[T]{
+		versionTree: versionTree,
+		subscribers: &subscribers{},
+	}
+}
+
+// newJournaledVersionHistory creates versionHistory, that keeps event of each version if keepJournal is set,
+// so changes can be replayed without rebuilding them.
+func newJournaledVersionHistory[T any](versionTree *internal.VersionTree[T], keepJournal bool) versionHistory[T] {
+	return versionHistory[T]{
+		versionTree: versionTree,
+		subscribers: &subscribers{
+			keepJournal: keepJournal,
+		},
+	}
+}
+
+// Parent returns the version, which given version was created from.
+// If version is the initial one or doesn't exist, false is returned.
+//
+// Complexity: O(1).
+func (h versionHistory[T]) Parent(version uint64) (uint64, bool) {
+	return h.versionTree.Parent(version)
+}
+
+// History returns versions on the path from the initial version to given version.
+//
+// Complexity: O(d), where d - depth of version.
+func (h versionHistory[T]) History(version uint64) ([]uint64, error) {
+	return h.versionTree.GetHistory(version)
+}
+
+// LastVersion returns the last created version.
+//
+// Complexity: O(1).
+func (h versionHistory[T]) LastVersion() uint64 {
+	return h.versionTree.LastVersion()
+}
+
+// Equal reports whether elements of a of version av are equal to elements of b of version bv.
+//
+// Complexity: same as for EqualVersions.
+func Equal[T comparable](a Indexed[T], av uint64, b Indexed[T], bv uint64) (bool, error) {
+	return EqualVersions(a, av, b, bv, nil)
+}
+
+// EqualKeyed reports whether keys and values of a of version av are equal to keys and values of b of version bv.
+//
+// Complexity: same as for EqualKeyedVersions.
+func EqualKeyed[K, V comparable](a Keyed[K, V], av uint64, b Keyed[K, V], bv uint64) (bool, error) {
+	return EqualKeyedVersions(a, av, b, bv, nil)
+}
+
+// EqualVersions reports whether elements of a of version av are equal to elements of b of version bv,
+// a and b may be different structures. Elements are compared by equal. If equal is nil, comparable values
+// are compared with ==, other values are compared with reflect.DeepEqual, and versions, which content hashes
+// differ, are not equal without comparing elements, see Slice.ContentHash.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n + k),
+// where n - sizes of structures and k - complexity of ToGoSlice.
+func EqualVersions[T any](a Indexed[T], av uint64, b Indexed[T], bv uint64, equal func(x, y T) bool) (bool, error) {
+	if differ, err := versionsDiffer(a, av, b, bv, equal == nil); err != nil || differ {
+		return false, err
+	}
+
+	aValues, err := a.ToGoSlice(av)
+	if err != nil {
+		return false, err
+	}
+
+	bValues, err := b.ToGoSlice(bv)
+	if err != nil {
+		return false, err
+	}
+
+	if equal == nil {
+		equal = valuesEqual[T]
+	}
+
+	return slices.EqualFunc(aValues, bValues, equal), nil
+}
+
+// EqualKeyedVersions reports whether keys and values of a of version av are equal to keys and values
+// of b of version bv, a and b may be different structures. Values are compared by equal. If equal is nil,
+// comparable values are compared with ==, other values are compared with reflect.DeepEqual, and versions,
+// which content hashes differ, are not equal without comparing values, see Map.ContentHash.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n + k),
+// where n - sizes of structures and k - complexity of ToGoMap.
+func EqualKeyedVersions[K comparable, V any](
+	a Keyed[K, V],
+	av uint64,
+	b Keyed[K, V],
+	bv uint64,
+	equal func(x, y V) bool,
+) (bool, error) {
+	if differ, err := versionsDiffer(a, av, b, bv, equal == nil); err != nil || differ {
+		return false, err
+	}
+
+	aValues, err := a.ToGoMap(av)
+	if err != nil {
+		return false, err
+	}
+
+	bValues, err := b.ToGoMap(bv)
+	if err != nil {
+		return false, err
+	}
+
+	if equal == nil {
+		equal = valuesEqual[V]
+	}
+
+	return maps.EqualFunc(aValues, bValues, equal), nil
+}
+
+// versionHasher is implemented by structures, that maintain content hash of each version.
+type versionHasher interface {
+	ContentHash(version uint64) (uint64, bool)
+}
+
+// versionsDiffer reports whether versions have different sizes or, if compareHashes is set, different content hashes,
+// so they are not equal.
+func versionsDiffer(a Versioned, av uint64, b Versioned, bv uint64, compareHashes bool) (bool, error) {
+	aLen, err := a.Len(av)
+	if err != nil {
+		return false, err
+	}
+
+	bLen, err := b.Len(bv)
+	if err != nil {
+		return false, err
+	}
+
+	if aLen != bLen {
+		return true, nil
+	}
+
+	aHasher, aOk := a.(versionHasher)
+	bHasher, bOk := b.(versionHasher)
+	if !compareHashes || !aOk || !bOk {
+		return false, nil
+	}
+
+	aHash, aKnown := aHasher.ContentHash(av)
+	bHash, bKnown := bHasher.ContentHash(bv)
+
+	return aKnown && bKnown && aHash != bHash, nil
+}
+
+// DiffKind is the kind of difference between two versions.
+type DiffKind int
+
+const (
+	// DiffAdded means that element is present only in the second version.
+	DiffAdded DiffKind = iota
+	// DiffRemoved means that element is present only in the first version.
+	DiffRemoved
+	// DiffChanged means that element is present in both versions with different values.
+	DiffChanged
+)
+
+// String returns name of DiffKind.
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// IndexDiff is a difference of element by index. Old is zero for DiffAdded, New is zero for DiffRemoved.
+type IndexDiff[T any] struct {
+	Kind  DiffKind
+	Index int
+	Old   T
+	New   T
+}
+
+// KeyDiff is a difference of value by key. Old is zero for DiffAdded, New is zero for DiffRemoved.
+type KeyDiff[K comparable, V any] struct {
+	Kind DiffKind
+	Key  K
+	Old  V
+	New  V
+}
+
+// Diff compares elements of a of version av with elements of b of version bv by index.
+// Differences are ordered by index. Elements after the end of the shorter version are added or removed.
+//
+// Complexity: O(n + k), where n - sizes of structures and k - complexity of ToGoSlice.
+func Diff[T comparable](a Indexed[T], av uint64, b Indexed[T], bv uint64) ([]IndexDiff[T], error) {
+	aValues, err := a.ToGoSlice(av)
+	if err != nil {
+		return nil, err
+	}
+
+	bValues, err := b.ToGoSlice(bv)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []IndexDiff[T]
+	for i := range max(len(aValues), len(bValues)) {
+		switch {
+		case i >= len(aValues):
+			diffs = append(diffs, IndexDiff[T]{Kind: DiffAdded, Index: i, New: bValues[i]})
+		case i >= len(bValues):
+			diffs = append(diffs, IndexDiff[T]{Kind: DiffRemoved, Index: i, Old: aValues[i]})
+		case aValues[i] != bValues[i]:
+			diffs = append(diffs, IndexDiff[T]{Kind: DiffChanged, Index: i, Old: aValues[i], New: bValues[i]})
+		}
+	}
+
+	return diffs, nil
+}
+
+// DiffKeyed compares keys and values of a of version av with keys and values of b of version bv.
+// Order of differences is not specified.
+//
+// Complexity: O(n + k), where n - sizes of structures and k - complexity of ToGoMap.
+func DiffKeyed[K, V comparable](a Keyed[K, V], av uint64, b Keyed[K, V], bv uint64) ([]KeyDiff[K, V], error) {
+	aValues, err := a.ToGoMap(av)
+	if err != nil {
+		return nil, err
+	}
+
+	bValues, err := b.ToGoMap(bv)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []KeyDiff[K, V]
+	for key, oldVal := range aValues {
+		newVal, ok := bValues[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, KeyDiff[K, V]{Kind: DiffRemoved, Key: key, Old: oldVal})
+		case oldVal != newVal:
+			diffs = append(diffs, KeyDiff[K, V]{Kind: DiffChanged, Key: key, Old: oldVal, New: newVal})
+		}
+	}
+	for key, newVal := range bValues {
+		if _, ok := aValues[key]; !ok {
+			diffs = append(diffs, KeyDiff[K, V]{Kind: DiffAdded, Key: key, New: newVal})
+		}
+	}
+
+	return diffs, nil
+}
+
+// IndexedSnapshot is a read-only view of a single version of Indexed structure.
+type IndexedSnapshot[T any] struct {
+	container Indexed[T]
+	version   uint64
+}
+
+// Snapshot returns read-only view of given version of Indexed structure.
+// If version doesn't exist, the error of Len is returned.
+//
+// Complexity: O(k), where k - complexity of Len.
+func Snapshot[T any](c Indexed[T], version uint64) (IndexedSnapshot[T], error) {
+	if _, err := c.Len(version); err != nil {
+		return IndexedSnapshot[T]{}, err
+	}
+
+	return IndexedSnapshot[T]{
+		container: c,
+		version:   version,
+	}, nil
+}
+
+// Version returns version of snapshot.
+func (s IndexedSnapshot[T]) Version() uint64 {
+	return s.version
+}
+
+// Len returns amount of elements in snapshot.
+func (s IndexedSnapshot[T]) Len() int {
+	size, _ := s.container.Len(s.version)
+	return size
+}
+
+// Get returns element of snapshot by index.
+func (s IndexedSnapshot[T]) Get(index int) (T, error) {
+	return s.container.Get(s.version, index)
+}
+
+// ToGoSlice returns all elements of snapshot in order.
+func (s IndexedSnapshot[T]) ToGoSlice() []T {
+	values, _ := s.container.ToGoSlice(s.version)
+	return values
+}
+
+// KeyedSnapshot is a read-only view of a single version of Keyed structure.
+type KeyedSnapshot[K comparable, V any] struct {
+	container Keyed[K, V]
+	version   uint64
+}
+
+// SnapshotKeyed returns read-only view of given version of Keyed structure.
+// If version doesn't exist, the error of Len is returned.
+//
+// Complexity: O(k), where k - complexity of Len.
+func SnapshotKeyed[K comparable, V any](c Keyed[K, V], version uint64) (KeyedSnapshot[K, V], error) {
+	if _, err := c.Len(version); err != nil {
+		return KeyedSnapshot[K, V]{}, err
+	}
+
+	return KeyedSnapshot[K, V]{
+		container: c,
+		version:   version,
+	}, nil
+}
+
+// Version returns version of snapshot.
+func (s KeyedSnapshot[K, V]) Version() uint64 {
+	return s.version
+}
+
+// Len returns amount of keys in snapshot.
+func (s KeyedSnapshot[K, V]) Len() int {
+	size, _ := s.container.Len(s.version)
+	return size
+}
+
+// Get returns value of snapshot by key.
+func (s KeyedSnapshot[K, V]) Get(key K) (V, error) {
+	return s.container.Get(s.version, key)
+}
+
+// ToGoMap returns all keys and values of snapshot.
+func (s KeyedSnapshot[K, V]) ToGoMap() map[K]V {
+	values, _ := s.container.ToGoMap(s.version)
+	return values
+}
@@ -0,0 +1,278 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVersioned_History(t *testing.T) {
+	containers := map[string]func() (Versioned, func(uint64) uint64){
+		"Slice": func() (Versioned, func(uint64) uint64) {
+			s, _ := NewSlice[int]()
+			return s, func(v uint64) uint64 {
+				newVersion, err := s.Append(v, 1)
+				errIsNil(t, err)
+				return newVersion
+			}
+		},
+		"DoubleLinkedList": func() (Versioned, func(uint64) uint64) {
+			l, _ := NewDoubleLinkedList[int]()
+			return l, func(v uint64) uint64 {
+				newVersion, err := l.PushBack(v, 1)
+				errIsNil(t, err)
+				return newVersion
+			}
+		},
+		"Map": func() (Versioned, func(uint64) uint64) {
+			m, _ := NewMap[int, int]()
+			return m, func(v uint64) uint64 {
+				newVersion, err := m.Set(v, int(v), 1)
+				errIsNil(t, err)
+				return newVersion
+			}
+		},
+		"Stack": func() (Versioned, func(uint64) uint64) {
+			s, _ := NewStack[int]()
+			return s, func(v uint64) uint64 {
+				newVersion, err := s.Push(v, 1)
+				errIsNil(t, err)
+				return newVersion
+			}
+		},
+		"Trie": func() (Versioned, func(uint64) uint64) {
+			tr, _ := NewTrie[int]()
+			return tr, func(v uint64) uint64 {
+				newVersion, err := tr.Set(v, string(rune('a'+v)), 1)
+				errIsNil(t, err)
+				return newVersion
+			}
+		},
+	}
+
+	for name, newContainer := range containers {
+		t.Run(name, func(t *testing.T) {
+			c, modify := newContainer()
+
+			v1 := modify(0)
+			v2 := modify(v1)
+			v3 := modify(v1)
+			versionShouldBe(t, c.LastVersion(), v3)
+
+			parent, ok := c.Parent(v3)
+			isTrue(t, ok)
+			versionShouldBe(t, parent, v1)
+
+			_, ok = c.Parent(0)
+			isTrue(t, !ok)
+
+			_, ok = c.Parent(v3 + 1)
+			isTrue(t, !ok)
+
+			history, err := c.History(v2)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(history, []uint64{0, v1, v2}))
+
+			_, err = c.History(v3 + 1)
+			isTrue(t, err != nil)
+
+			size, err := c.Len(v2)
+			errIsNil(t, err)
+			isTrue(t, size == 2)
+		})
+	}
+}
+
+func TestEqualDiff_Indexed(t *testing.T) {
+	s, sv := NewSlice[int]()
+	l, lv := NewDoubleLinkedList[int]()
+
+	var err error
+	for i := 1; i <= 3; i++ {
+		sv, err = s.Append(sv, i)
+		errIsNil(t, err)
+		lv, err = l.PushBack(lv, i)
+		errIsNil(t, err)
+	}
+
+	equal, err := Equal[int](s, sv, l, lv)
+	errIsNil(t, err)
+	isTrue(t, equal)
+
+	diffs, err := Diff[int](s, sv, l, lv)
+	errIsNil(t, err)
+	isTrue(t, len(diffs) == 0)
+
+	changed, err := l.Set(lv, 1, 20)
+	errIsNil(t, err)
+	changed, err = l.PushBack(changed, 4)
+	errIsNil(t, err)
+
+	equal, err = Equal[int](s, sv, l, changed)
+	errIsNil(t, err)
+	isTrue(t, !equal)
+
+	diffs, err = Diff[int](s, sv, l, changed)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(diffs, []IndexDiff[int]{
+		{Kind: DiffChanged, Index: 1, Old: 2, New: 20},
+		{Kind: DiffAdded, Index: 3, New: 4},
+	}))
+
+	diffs, err = Diff[int](l, changed, s, sv)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(diffs, []IndexDiff[int]{
+		{Kind: DiffChanged, Index: 1, Old: 20, New: 2},
+		{Kind: DiffRemoved, Index: 3, Old: 4},
+	}))
+
+	_, err = Equal[int](s, sv+1, l, lv)
+	isTrue(t, err != nil)
+
+	_, err = Diff[int](s, sv, l, changed+1)
+	isTrue(t, err != nil)
+}
+
+func TestEqualDiff_Keyed(t *testing.T) {
+	m, mv := NewMap[string, int]()
+	om, omv := NewOrderedMap[string, int]()
+
+	var err error
+	for i, key := range []string{"a", "b", "c"} {
+		mv, err = m.Set(mv, key, i)
+		errIsNil(t, err)
+		omv, err = om.Set(omv, key, i)
+		errIsNil(t, err)
+	}
+
+	equal, err := EqualKeyed[string, int](m, mv, om, omv)
+	errIsNil(t, err)
+	isTrue(t, equal)
+
+	changed, err := om.Delete(omv, "a")
+	errIsNil(t, err)
+	changed, err = om.Set(changed, "b", 10)
+	errIsNil(t, err)
+	changed, err = om.Set(changed, "d", 3)
+	errIsNil(t, err)
+
+	equal, err = EqualKeyed[string, int](m, mv, om, changed)
+	errIsNil(t, err)
+	isTrue(t, !equal)
+
+	diffs, err := DiffKeyed[string, int](m, mv, om, changed)
+	errIsNil(t, err)
+	slices.SortFunc(diffs, func(a, b KeyDiff[string, int]) int {
+		return int(a.Key[0]) - int(b.Key[0])
+	})
+	isTrue(t, slices.Equal(diffs, []KeyDiff[string, int]{
+		{Kind: DiffRemoved, Key: "a", Old: 0},
+		{Kind: DiffChanged, Key: "b", Old: 1, New: 10},
+		{Kind: DiffAdded, Key: "d", New: 3},
+	}))
+
+	_, err = DiffKeyed[string, int](m, mv+1, om, omv)
+	isTrue(t, err != nil)
+}
+
+func TestSnapshot(t *testing.T) {
+	s, version := NewSlice[int]()
+
+	var err error
+	for i := 1; i <= 3; i++ {
+		version, err = s.Append(version, i)
+		errIsNil(t, err)
+	}
+
+	snapshot, err := Snapshot[int](s, version)
+	errIsNil(t, err)
+
+	_, err = s.Set(version, 0, 10)
+	errIsNil(t, err)
+
+	versionShouldBe(t, snapshot.Version(), version)
+	isTrue(t, snapshot.Len() == 3)
+	isTrue(t, slices.Equal(snapshot.ToGoSlice(), []int{1, 2, 3}))
+
+	val, err := snapshot.Get(0)
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+
+	_, err = snapshot.Get(3)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+
+	_, err = Snapshot[int](s, version+2)
+	isTrue(t, err != nil)
+
+	tr, trv := NewTrie[int]()
+	trv, err = tr.Set(trv, "key", 1)
+	errIsNil(t, err)
+
+	keyedSnapshot, err := SnapshotKeyed[string, int](tr, trv)
+	errIsNil(t, err)
+
+	_, err = tr.Delete(trv, "key")
+	errIsNil(t, err)
+
+	versionShouldBe(t, keyedSnapshot.Version(), trv)
+	isTrue(t, keyedSnapshot.Len() == 1)
+	isTrue(t, keyedSnapshot.ToGoMap()["key"] == 1)
+
+	val, err = keyedSnapshot.Get("key")
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+
+	_, err = SnapshotKeyed[string, int](tr, trv+2)
+	isTrue(t, err != nil)
+}
+
+func TestEqualVersions(t *testing.T) {
+	s, sv := NewSlice[string]()
+	l, lv := NewDoubleLinkedList[string]()
+
+	var err error
+	for _, val := range []string{"a", "B"} {
+		sv, err = s.Append(sv, val)
+		errIsNil(t, err)
+	}
+	for _, val := range []string{"A", "b"} {
+		lv, err = l.PushBack(lv, val)
+		errIsNil(t, err)
+	}
+
+	equal, err := EqualVersions[string](s, sv, l, lv, nil)
+	errIsNil(t, err)
+	isTrue(t, !equal)
+
+	equal, err = EqualVersions[string](s, sv, l, lv, strings.EqualFold)
+	errIsNil(t, err)
+	isTrue(t, equal)
+
+	m, mv := NewMap[string, string](WithHAMT())
+	tr, trv := NewTrie[string]()
+	mv, err = m.Set(mv, "key", "value")
+	errIsNil(t, err)
+	trv, err = tr.Set(trv, "key", "VALUE")
+	errIsNil(t, err)
+
+	equal, err = EqualKeyedVersions[string, string](m, mv, tr, trv, nil)
+	errIsNil(t, err)
+	isTrue(t, !equal)
+
+	equal, err = EqualKeyedVersions[string, string](m, mv, tr, trv, strings.EqualFold)
+	errIsNil(t, err)
+	isTrue(t, equal)
+
+	trv, err = tr.Set(trv, "key", "value")
+	errIsNil(t, err)
+
+	mapHash, ok := m.ContentHash(mv)
+	isTrue(t, ok)
+	trieHash, ok := tr.ContentHash(trv)
+	isTrue(t, ok)
+	isTrue(t, mapHash == trieHash)
+
+	equal, err = EqualKeyedVersions[string, string](m, mv, tr, trv, nil)
+	errIsNil(t, err)
+	isTrue(t, equal)
+}
@@ -0,0 +1,301 @@
+package go_persistent_ds
+
+import (
+	"errors"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// ErrEmpty is returned on attempt to take value from empty structure.
+var ErrEmpty = errors.New("structure is empty")
+
+// Deque is a persistent double-ended queue.
+// While working with deque you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Deque is stored as immutable RRB tree. Each version keeps its own tree, and trees of different versions
+// share all nodes except the ones on the paths to the changed ends. There is no rebalancing deferred
+// to later operations, so every operation takes the same time on any version, however many times it is modified.
+//
+// Deque can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Deque, the good idea is to use Compact method, that creates new Deque
+// with special version as the initial one.
+//
+// Note that Deque is not thread safe.
+type Deque[T any] struct {
+	versionHistory[dequeVersionInfo[T]]
+}
+
+type dequeVersionInfo[T any] struct {
+	values internal.RRBTree[T]
+	hash   sequenceHash
+}
+
+// NewDeque creates empty Deque.
+func NewDeque[T any]() (*Deque[T], uint64) {
+	return &Deque[T]{
+		versionHistory: newVersionHistory(internal.NewVersionTree[dequeVersionInfo[T]]()),
+	}, 0
+}
+
+// NewDequeWithAnyValues creates a Deque, that can store values of any type.
+func NewDequeWithAnyValues() (*Deque[any], uint64) {
+	return NewDeque[any]()
+}
+
+// PushFront adds the value to the front of Deque of given version. Returns Deque's new version.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) PushFront(version uint64, val T) (uint64, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.commit(version, dequeVersionInfo[T]{
+		values: info.values.Prepend(val),
+		hash:   info.hash.pushFront(val),
+	}, ChangeEvent{Op: OpPushFront, NewValue: val})
+}
+
+// PushBack adds the value to the back of Deque of given version. Returns Deque's new version.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) PushBack(version uint64, val T) (uint64, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.commit(version, dequeVersionInfo[T]{
+		values: info.values.Append(val),
+		hash:   info.hash.pushBack(val),
+	}, ChangeEvent{Op: OpPushBack, Index: info.values.Len(), NewValue: val})
+}
+
+// PopFront removes the value from the front of Deque of given version.
+// Returns removed value and Deque's new version.
+// If Deque is empty ErrEmpty is returned.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) PopFront(version uint64) (T, uint64, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	val, ok := info.values.Get(0)
+	if !ok {
+		return *new(T), 0, ErrEmpty
+	}
+
+	values, _ := info.values.Slice(1, info.values.Len())
+
+	newVersion, err := d.commit(version, dequeVersionInfo[T]{
+		values: values,
+		hash:   info.hash.popFront(val),
+	}, ChangeEvent{Op: OpPopFront, OldValue: val})
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// PopBack removes the value from the back of Deque of given version.
+// Returns removed value and Deque's new version.
+// If Deque is empty ErrEmpty is returned.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) PopBack(version uint64) (T, uint64, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	last := info.values.Len() - 1
+	val, ok := info.values.Get(last)
+	if !ok {
+		return *new(T), 0, ErrEmpty
+	}
+
+	values, _ := info.values.Slice(0, last)
+
+	newVersion, err := d.commit(version, dequeVersionInfo[T]{
+		values: values,
+		hash:   info.hash.popBack(val),
+	}, ChangeEvent{Op: OpPopBack, Index: last, OldValue: val})
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// Front returns the value from the front of Deque of given version.
+// If Deque is empty ErrEmpty is returned.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) Front(version uint64) (T, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), err
+	}
+
+	val, ok := info.values.Get(0)
+	if !ok {
+		return *new(T), ErrEmpty
+	}
+
+	return val, nil
+}
+
+// Back returns the value from the back of Deque of given version.
+// If Deque is empty ErrEmpty is returned.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) Back(version uint64) (T, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), err
+	}
+
+	val, ok := info.values.Get(info.values.Len() - 1)
+	if !ok {
+		return *new(T), ErrEmpty
+	}
+
+	return val, nil
+}
+
+// Get returns the value by index for given version counting from the front of Deque.
+//
+// Complexity: O(log32(n)).
+func (d *Deque[T]) Get(version uint64, index int) (T, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), err
+	}
+
+	val, ok := info.values.Get(index)
+	if !ok {
+		return *new(T), newIndexError(version, index, info.values.Len())
+	}
+
+	return val, nil
+}
+
+// Len returns the len of Deque.
+//
+// Complexity: O(1).
+func (d *Deque[T]) Len(version uint64) (int, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.values.Len(), nil
+}
+
+// ToGoSlice converts persistent Deque for specified version into go slice. Front of Deque becomes the first element.
+//
+// Complexity: O(n).
+func (d *Deque[T]) ToGoSlice(version uint64) ([]T, error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.values.Values(), nil
+}
+
+// Equal reports whether versions v1 and v2 of Deque contain equal values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n).
+func (d *Deque[T]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := d.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := d.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.values.Len() != info2.values.Len() || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalSequences(d.ToGoSlice, v1, v2)
+}
+
+// ContentHash returns hash of values of Deque of given version, that is maintained on each modification.
+// Versions with equal values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (d *Deque[T]) ContentHash(version uint64) (uint64, bool) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new Deque, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (d *Deque[T]) Compact(version uint64, opts ...CompactOption) (*Deque[T], error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewDeque[T]()
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = d.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new Deque, which initial version shares content of given version without copying it.
+// Modifications of the new Deque don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (d *Deque[T]) Fork(version uint64) (*Deque[T], error) {
+	info, err := d.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewDeque[T]()
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+func (d *Deque[T]) commit(version uint64, info dequeVersionInfo[T], event ChangeEvent) (uint64, error) {
+	newVersion, err := d.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = d.versionTree.SetVersionInfo(newVersion, info)
+
+	d.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
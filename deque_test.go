@@ -0,0 +1,206 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+func TestDeque_PushAndGet(t *testing.T) {
+	d, initialVersion := NewDeque[int]()
+	versionShouldBe(t, initialVersion, 0)
+
+	v, err := d.PushBack(0, 2)
+	errIsNil(t, err)
+	versionShouldBe(t, v, 1)
+
+	v, err = d.PushFront(1, 1)
+	errIsNil(t, err)
+	versionShouldBe(t, v, 2)
+
+	v, err = d.PushBack(2, 3)
+	errIsNil(t, err)
+	versionShouldBe(t, v, 3)
+
+	v, err = d.PushFront(2, 0)
+	errIsNil(t, err)
+	versionShouldBe(t, v, 4)
+
+	slice, err := d.ToGoSlice(3)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []int{1, 2, 3}))
+
+	slice, err = d.ToGoSlice(4)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []int{0, 1, 2}))
+
+	for i, expected := range []int{1, 2, 3} {
+		val, err := d.Get(3, i)
+		errIsNil(t, err)
+		isTrue(t, val == expected)
+	}
+
+	_, err = d.Get(3, 3)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+	_, err = d.Get(3, -1)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+
+	front, err := d.Front(4)
+	errIsNil(t, err)
+	isTrue(t, front == 0)
+
+	back, err := d.Back(4)
+	errIsNil(t, err)
+	isTrue(t, back == 2)
+
+	size, err := d.Len(4)
+	errIsNil(t, err)
+	isTrue(t, size == 3)
+
+	_, err = d.Len(5)
+	errShouldBe(t, err, internal.ErrVersionNotFound)
+}
+
+func TestDeque_Pop(t *testing.T) {
+	t.Run("On empty deque", func(t *testing.T) {
+		t.Parallel()
+
+		d, version := NewDeque[int]()
+
+		_, _, err := d.PopFront(version)
+		errShouldBe(t, err, ErrEmpty)
+
+		_, _, err = d.PopBack(version)
+		errShouldBe(t, err, ErrEmpty)
+
+		_, err = d.Front(version)
+		errShouldBe(t, err, ErrEmpty)
+
+		_, err = d.Back(version)
+		errShouldBe(t, err, ErrEmpty)
+	})
+
+	t.Run("Pop from the opposite end", func(t *testing.T) {
+		t.Parallel()
+
+		d, version := NewDeque[int]()
+
+		var err error
+		for i := 0; i < 10; i++ {
+			version, err = d.PushBack(version, i)
+			errIsNil(t, err)
+		}
+		pushedVersion := version
+
+		for i := 0; i < 10; i++ {
+			var val int
+			val, version, err = d.PopFront(version)
+			errIsNil(t, err)
+			isTrue(t, val == i)
+		}
+
+		size, err := d.Len(version)
+		errIsNil(t, err)
+		isTrue(t, size == 0)
+
+		version = pushedVersion
+		for i := 9; i >= 5; i-- {
+			var val int
+			val, version, err = d.PopBack(version)
+			errIsNil(t, err)
+			isTrue(t, val == i)
+		}
+
+		slice, err := d.ToGoSlice(version)
+		errIsNil(t, err)
+		isTrue(t, slices.Equal(slice, []int{0, 1, 2, 3, 4}))
+
+		slice, err = d.ToGoSlice(pushedVersion)
+		errIsNil(t, err)
+		isTrue(t, slices.Equal(slice, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}))
+	})
+
+	t.Run("Mixed operations against go slice", func(t *testing.T) {
+		t.Parallel()
+
+		d, version := NewDeque[int]()
+		var expected []int
+
+		var err error
+		for i := 0; i < 200; i++ {
+			switch i % 7 {
+			case 0, 3:
+				version, err = d.PushFront(version, i)
+				expected = append([]int{i}, expected...)
+			case 1, 4, 5:
+				version, err = d.PushBack(version, i)
+				expected = append(expected, i)
+			case 2:
+				var val int
+				val, version, err = d.PopBack(version)
+				isTrue(t, val == expected[len(expected)-1])
+				expected = expected[:len(expected)-1]
+			case 6:
+				var val int
+				val, version, err = d.PopFront(version)
+				isTrue(t, val == expected[0])
+				expected = expected[1:]
+			}
+			errIsNil(t, err)
+
+			for j, expectedVal := range expected {
+				val, err := d.Get(version, j)
+				errIsNil(t, err)
+				isTrue(t, val == expectedVal)
+			}
+		}
+	})
+
+	t.Run("Pop repeatedly from the same version", func(t *testing.T) {
+		t.Parallel()
+
+		d, version := NewDeque[int]()
+
+		var err error
+		for i := 0; i < 100; i++ {
+			version, err = d.PushBack(version, i)
+			errIsNil(t, err)
+		}
+
+		for i := 0; i < 10; i++ {
+			front, frontVersion, err := d.PopFront(version)
+			errIsNil(t, err)
+			isTrue(t, front == 0)
+
+			back, backVersion, err := d.PopBack(version)
+			errIsNil(t, err)
+			isTrue(t, back == 99)
+
+			size, err := d.Len(frontVersion)
+			errIsNil(t, err)
+			isTrue(t, size == 99)
+
+			val, err := d.Front(backVersion)
+			errIsNil(t, err)
+			isTrue(t, val == 0)
+		}
+	})
+}
+
+func TestDequeWithAnyValues(t *testing.T) {
+	d, version := NewDequeWithAnyValues()
+
+	version, err := d.PushBack(version, "a")
+	errIsNil(t, err)
+	version, err = d.PushBack(version, 1)
+	errIsNil(t, err)
+
+	val, err := d.Front(version)
+	errIsNil(t, err)
+	isTrue(t, val == "a")
+
+	val, err = d.Back(version)
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+}
@@ -0,0 +1,82 @@
+package go_persistent_ds
+
+import (
+	"fmt"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// ErrVersionNotFound is returned if given version doesn't exist in structure.
+var ErrVersionNotFound = internal.ErrVersionNotFound
+
+// IndexError is returned if index is out of range of structure of given version.
+// It matches ErrIndexOutOfRange and ErrListIndexOutOfRange with errors.Is.
+type IndexError struct {
+	// Version is the version of structure, that was accessed.
+	Version uint64
+	// Index is the accessed index.
+	Index int
+	// Len is the size of structure of Version.
+	Len int
+}
+
+func newIndexError(version uint64, index, size int) *IndexError {
+	return &IndexError{
+		Version: version,
+		Index:   index,
+		Len:     size,
+	}
+}
+
+// Error returns description of IndexError.
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d out of range [0:%d] for version %d", e.Index, e.Len, e.Version)
+}
+
+// Is reports whether target is ErrIndexOutOfRange, ErrListIndexOutOfRange or IndexError with the same fields.
+func (e *IndexError) Is(target error) bool {
+	if other, ok := target.(*IndexError); ok {
+		return *e == *other
+	}
+
+	return target == ErrIndexOutOfRange || target == ErrListIndexOutOfRange
+}
+
+// newRangeError returns IndexError for the bound of range [start:end], that is out of structure of given size.
+func newRangeError(version uint64, start, end, size int) *IndexError {
+	if start < 0 || start > size {
+		return newIndexError(version, start, size)
+	}
+
+	return newIndexError(version, end, size)
+}
+
+// KeyError is returned if there is no key in structure of given version.
+// It matches ErrNotFound with errors.Is.
+type KeyError struct {
+	// Version is the version of structure, that was accessed.
+	Version uint64
+	// Key is the missing key.
+	Key any
+}
+
+func newKeyError(version uint64, key any) *KeyError {
+	return &KeyError{
+		Version: version,
+		Key:     key,
+	}
+}
+
+// Error returns description of KeyError.
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("key %v not found for version %d", e.Key, e.Version)
+}
+
+// Is reports whether target is ErrNotFound or KeyError with the same fields.
+func (e *KeyError) Is(target error) bool {
+	if other, ok := target.(*KeyError); ok {
+		return *e == *other
+	}
+
+	return target == ErrNotFound
+}
@@ -0,0 +1,60 @@
+package go_persistent_ds
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIndexError(t *testing.T) {
+	s, version := NewSlice[int](WithRRBTree())
+	version, err := s.Append(version, 1)
+	errIsNil(t, err)
+
+	_, err = s.Get(version, 3)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+	errShouldBe(t, err, ErrListIndexOutOfRange)
+
+	var indexErr *IndexError
+	isTrue(t, errors.As(err, &indexErr))
+	isTrue(t, *indexErr == IndexError{Version: version, Index: 3, Len: 1})
+
+	l, lv := NewDoubleLinkedList[int]()
+	_, _, err = l.PopFront(lv)
+	errShouldBe(t, err, &IndexError{Version: lv, Index: 0, Len: 0})
+
+	r, rv := NewRopeFromString("text")
+	_, err = r.Substring(rv, 2, 5)
+	errShouldBe(t, err, &IndexError{Version: rv, Index: 7, Len: 4})
+
+	_, err = s.Get(version+1, 0)
+	errShouldBe(t, err, ErrVersionNotFound)
+	isTrue(t, !errors.As(err, &indexErr))
+}
+
+func TestKeyError(t *testing.T) {
+	for name, opts := range map[string][]MapOption{"Cells": nil, "HAMT": {WithHAMT()}} {
+		t.Run(name, func(t *testing.T) {
+			m, v0 := NewMap[string, int](opts...)
+			v1, err := m.Set(v0, "a", 1)
+			errIsNil(t, err)
+
+			_, err = m.Delete(v1, "b")
+			errShouldBe(t, err, ErrNotFound)
+
+			var keyErr *KeyError
+			isTrue(t, errors.As(err, &keyErr))
+			isTrue(t, *keyErr == KeyError{Version: v1, Key: "b"})
+
+			_, err = m.Get(v0, "a")
+			errShouldBe(t, err, &KeyError{Version: v0, Key: "a"})
+
+			_, err = m.Delete(v1+1, "a")
+			errShouldBe(t, err, ErrVersionNotFound)
+			isTrue(t, !errors.Is(err, ErrNotFound))
+		})
+	}
+
+	tr, tv := NewTrie[int]()
+	_, err := tr.Get(tv, "key")
+	errShouldBe(t, err, &KeyError{Version: tv, Key: "key"})
+}
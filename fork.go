@@ -0,0 +1,55 @@
+package go_persistent_ds
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// forkLock guards Cells and versions of structure, which are read by its forks. Structure takes the lock
+// for modification only after it is forked, and forks take it for reading shared Cells.
+type forkLock struct {
+	forked atomic.Bool
+	mu     sync.RWMutex
+}
+
+// lock locks structure for modification, if it has forks. Returns function, that unlocks it.
+func (l *forkLock) lock() (unlock func()) {
+	if !l.forked.Load() {
+		return func() {}
+	}
+
+	l.mu.Lock()
+
+	return l.mu.Unlock
+}
+
+// readCell returns value of cell visible for version. History of version is requested only
+// if there is no value written exactly for version. If there is no visible value, false is returned.
+func readCell(cell internal.Cell, version uint64, history func() []uint64) (any, bool) {
+	if visibleCell, ok := cell.(VisibleCell); ok {
+		return visibleCell.ReadVisible(version)
+	}
+
+	if val, found := cell.Read(version); found {
+		return val, true
+	}
+
+	// the version is the last in change history, initial version has values only if structure was created by Compact.
+	changeHistory := history()
+	for i := len(changeHistory) - 2; i >= 0; i-- {
+		if val, found := cell.Read(changeHistory[i]); found {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
+
+// knownHistory returns function, that returns given history.
+func knownHistory(history []uint64) func() []uint64 {
+	return func() []uint64 {
+		return history
+	}
+}
@@ -0,0 +1,361 @@
+package go_persistent_ds
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// modifyConcurrently runs modifications of original and fork in parallel, each of them is applied times times.
+func modifyConcurrently(t *testing.T, times int, original, fork func(i int) error) {
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, modify := range []func(i int) error{original, fork} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < times && errs[i] == nil; j++ {
+				errs[i] = modify(j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	errIsNil(t, errs[0])
+	errIsNil(t, errs[1])
+}
+
+func TestFork_Map(t *testing.T) {
+	const times = 100
+
+	options := map[string][]MapOption{
+		"Cells":              nil,
+		"HAMT":               {WithHAMT()},
+		"VersionListBackend": {WithBackend(VersionListBackend())},
+	}
+
+	for name, opts := range options {
+		t.Run(name, func(t *testing.T) {
+			m, version := NewMap[int, int](opts...)
+
+			var err error
+			for i := 0; i < 10; i++ {
+				version, err = m.Set(version, i, i)
+				errIsNil(t, err)
+			}
+
+			fork, err := m.Fork(version)
+			errIsNil(t, err)
+			versionShouldBe(t, fork.LastVersion(), 0)
+
+			info, _ := m.versionTree.GetVersionInfo(version)
+			forkInfo, _ := fork.versionTree.GetVersionInfo(0)
+			if m.useHAMT {
+				isTrue(t, forkInfo.hamt == info.hamt)
+			} else {
+				isTrue(t, len(fork.mapOfCells) == 0)
+			}
+
+			original, forked := version, uint64(0)
+			modifyConcurrently(t, times, func(i int) error {
+				original, err = m.Set(original, i%10, -i)
+				return err
+			}, func(i int) error {
+				if val, getErr := fork.Get(forked, 9); getErr != nil || val != 9 {
+					return ErrNotFound
+				}
+
+				var forkErr error
+				forked, forkErr = fork.Set(forked, i%5, i*10)
+				return forkErr
+			})
+
+			versionShouldBe(t, m.LastVersion(), version+times)
+			versionShouldBe(t, fork.LastVersion(), times)
+			if !m.useHAMT {
+				isTrue(t, len(fork.mapOfCells) == 5)
+			}
+
+			equal, err := EqualKeyed[int, int](m, version, fork, 0)
+			errIsNil(t, err)
+			isTrue(t, equal)
+
+			values, err := fork.ToGoMap(forked)
+			errIsNil(t, err)
+			isTrue(t, maps.Equal(values, map[int]int{0: 950, 1: 960, 2: 970, 3: 980, 4: 990, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9}))
+			val, err := m.Get(original, 1)
+			errIsNil(t, err)
+			isTrue(t, val == -(times-9))
+		})
+	}
+}
+
+func TestFork_MapOfFork(t *testing.T) {
+	m, version := NewMap[string, int]()
+	version, err := m.Set(version, "a", 1)
+	errIsNil(t, err)
+	version, err = m.Set(version, "b", 2)
+	errIsNil(t, err)
+
+	fork, err := m.Fork(version)
+	errIsNil(t, err)
+	forked, err := fork.Delete(0, "a")
+	errIsNil(t, err)
+	forked, err = fork.Set(forked, "c", 3)
+	errIsNil(t, err)
+
+	second, err := fork.Fork(forked)
+	errIsNil(t, err)
+	secondVersion, err := second.Set(0, "b", 4)
+	errIsNil(t, err)
+
+	_, err = m.Delete(version, "b")
+	errIsNil(t, err)
+
+	_, err = second.Get(secondVersion, "a")
+	errShouldBe(t, err, ErrNotFound)
+
+	values, err := second.ToGoMap(secondVersion)
+	errIsNil(t, err)
+	isTrue(t, maps.Equal(values, map[string]int{"b": 4, "c": 3}))
+
+	values, err = fork.ToGoMap(forked)
+	errIsNil(t, err)
+	isTrue(t, maps.Equal(values, map[string]int{"b": 2, "c": 3}))
+
+	_, err = second.Delete(secondVersion, "a")
+	errShouldBe(t, err, ErrNotFound)
+}
+
+func TestFork_Indexed(t *testing.T) {
+	const times = 100
+
+	type forkable struct {
+		original Indexed[int]
+		push     func(v uint64, val int) (uint64, error)
+		fork     func(v uint64) (Indexed[int], error)
+		// shared reports whether the initial version of fork shares content of version of original.
+		shared func(fork Indexed[int], version uint64) bool
+	}
+
+	sliceWith := func(opts ...SliceOption) forkable {
+		s, _ := NewSlice[int](opts...)
+
+		return forkable{s, s.Append, func(v uint64) (Indexed[int], error) { return s.Fork(v) },
+			func(fork Indexed[int], version uint64) bool {
+				info, _ := s.versionTree.GetVersionInfo(version)
+				forkInfo, _ := fork.(*Slice[int]).versionTree.GetVersionInfo(0)
+				if s.useRRBTree {
+					return forkInfo.rrb == info.rrb
+				}
+
+				return len(forkInfo.storage.cells) == 0 && forkInfo.storage.base.info.storage == info.storage
+			}}
+	}
+	listWith := func(opts ...ListOption) forkable {
+		l, _ := NewDoubleLinkedList[int](opts...)
+
+		return forkable{l, l.PushBack, func(v uint64) (Indexed[int], error) { return l.Fork(v) },
+			func(fork Indexed[int], version uint64) bool {
+				info, _ := l.versionTree.GetVersionInfo(version)
+				forkInfo, _ := fork.(*DoubleLinkedList[int]).versionTree.GetVersionInfo(0)
+
+				return forkInfo.head == info.head && forkInfo.tail == info.tail
+			}}
+	}
+	structures := map[string]forkable{
+		"Slice with Cells":                  sliceWith(),
+		"Slice with VersionListBackend":     sliceWith(WithBackend(VersionListBackend())),
+		"Slice with RRB-tree":               sliceWith(WithRRBTree()),
+		"DoubleLinkedList":                  listWith(),
+		"DoubleLinkedList with VersionList": listWith(WithBackend(VersionListBackend())),
+	}
+
+	for name, structure := range structures {
+		t.Run(name, func(t *testing.T) {
+			original := structure.original
+
+			var (
+				version uint64
+				err     error
+			)
+			for i := 0; i < 5; i++ {
+				version, err = structure.push(version, i)
+				errIsNil(t, err)
+			}
+
+			fork, err := structure.fork(version)
+			errIsNil(t, err)
+			isTrue(t, structure.shared(fork, version))
+
+			originalVersion, forkedVersion := version, uint64(0)
+			modifyConcurrently(t, times, func(i int) error {
+				originalVersion, err = original.Set(originalVersion, i%5, -i)
+				return err
+			}, func(i int) error {
+				if val, getErr := fork.Get(forkedVersion, 4); getErr != nil || val != 4 {
+					return ErrIndexOutOfRange
+				}
+
+				var forkErr error
+				forkedVersion, forkErr = fork.Set(forkedVersion, i%3, i)
+				return forkErr
+			})
+
+			versionShouldBe(t, original.LastVersion(), version+times)
+			versionShouldBe(t, fork.LastVersion(), times)
+
+			equal, err := Equal[int](original, version, fork, 0)
+			errIsNil(t, err)
+			isTrue(t, equal)
+
+			values, err := fork.ToGoSlice(forkedVersion)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []int{99, 97, 98, 3, 4}))
+
+			values, err = original.ToGoSlice(originalVersion)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []int{-95, -96, -97, -98, -99}))
+		})
+	}
+}
+
+func TestFork_SliceOfFork(t *testing.T) {
+	s := newSliceOfValues([]int{1, 2, 3, 4})
+
+	fork, err := s.Fork(0)
+	errIsNil(t, err)
+	forked, err := fork.Range(0, 1, 3)
+	errIsNil(t, err)
+	forked, err = fork.Append(forked, 5)
+	errIsNil(t, err)
+
+	second, err := fork.Fork(forked)
+	errIsNil(t, err)
+	secondVersion, err := second.Set(0, 2, 6)
+	errIsNil(t, err)
+	secondVersion, err = second.Prepend(secondVersion, 0)
+	errIsNil(t, err)
+
+	_, err = s.Set(0, 1, -2)
+	errIsNil(t, err)
+
+	values, err := second.ToGoSlice(secondVersion)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []int{0, 2, 3, 6}))
+
+	values, err = fork.ToGoSlice(forked)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []int{2, 3, 5}))
+
+	hash, ok := second.ContentHash(secondVersion)
+	expectedHash, _ := newSliceOfValues([]int{0, 2, 3, 6}).ContentHash(0)
+	isTrue(t, ok && hash == expectedHash)
+}
+
+func TestFork_ListOfFork(t *testing.T) {
+	l, version := NewDoubleLinkedList[int]()
+
+	var err error
+	for i := 1; i <= 4; i++ {
+		version, err = l.PushBack(version, i)
+		errIsNil(t, err)
+	}
+
+	fork, err := l.Fork(version)
+	errIsNil(t, err)
+	forked, err := fork.Remove(0, 1)
+	errIsNil(t, err)
+	forked, err = fork.InsertAt(forked, 2, 5)
+	errIsNil(t, err)
+
+	second, err := fork.Fork(forked)
+	errIsNil(t, err)
+	secondVersion, err := second.PushFront(0, 0)
+	errIsNil(t, err)
+	secondVersion, err = second.Remove(secondVersion, 4)
+	errIsNil(t, err)
+
+	_, err = l.Remove(version, 2)
+	errIsNil(t, err)
+
+	values, err := second.ToGoSlice(secondVersion)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []int{0, 1, 3, 5}))
+
+	values, err = fork.ToGoSlice(forked)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []int{1, 3, 5, 4}))
+
+	values, err = l.ToGoSlice(version)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []int{1, 2, 3, 4}))
+
+	c, err := second.Back(secondVersion)
+	errIsNil(t, err)
+	isTrue(t, c.Prev() && c.Prev() && c.Value() == 1)
+}
+
+func TestFork_Immutable(t *testing.T) {
+	const times = 100
+
+	r, version := NewRopeFromString("shared text")
+	fork, err := r.Fork(version)
+	errIsNil(t, err)
+
+	info, _ := r.versionTree.GetVersionInfo(version)
+	forkInfo, _ := fork.versionTree.GetVersionInfo(0)
+	isTrue(t, *forkInfo == *info)
+
+	original, forked := version, uint64(0)
+	modifyConcurrently(t, times, func(_ int) error {
+		original, err = r.Insert(original, 0, "a")
+		return err
+	}, func(_ int) error {
+		var forkErr error
+		forked, forkErr = fork.Delete(forked, 0, 0)
+		return forkErr
+	})
+
+	versionShouldBe(t, r.LastVersion(), version+times)
+	text, err := fork.String(forked)
+	errIsNil(t, err)
+	isTrue(t, text == "shared text")
+}
+
+// forkLast forks the last version of structure through Forkable.
+func forkLast[S Forkable[S]](t *testing.T, structure S) S {
+	t.Helper()
+
+	forked, err := structure.Fork(structure.LastVersion())
+	errIsNil(t, err)
+
+	size, err := structure.Len(structure.LastVersion())
+	errIsNil(t, err)
+	forkedSize, err := forked.Len(0)
+	errIsNil(t, err)
+	isTrue(t, forkedSize == size)
+
+	return forked
+}
+
+func TestForkable(t *testing.T) {
+	st, version := NewStack[int]()
+	_, err := st.Push(version, 1)
+	errIsNil(t, err)
+
+	forkedStack := forkLast(t, st)
+	top, err := forkedStack.Peek(0)
+	errIsNil(t, err)
+	isTrue(t, top == 1)
+
+	m, version := NewMap[string, int]()
+	_, err = m.Set(version, "a", 1)
+	errIsNil(t, err)
+
+	forkedMap := forkLast(t, m)
+	val, err := forkedMap.Get(0, "a")
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+}
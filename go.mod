@@ -1,3 +1,3 @@
 module github.com/AleksandrMatsko/go-persistent-ds
 
-go 1.22
+go 1.24
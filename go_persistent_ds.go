@@ -4,9 +4,34 @@
 //   - Map
 //   - Slice
 //   - DoubleLinkedList
+//   - Deque
+//   - Stack
+//   - Queue
+//   - PriorityQueue
+//   - Trie
+//   - Rope
+//   - OrderedMap
 //
-// All structures are base on FatNodes.
+// Map, Slice and DoubleLinkedList are based on Cells, by default FatNodes, see Backend. Map can also be based on hash array mapped trie, see WithHAMT,
+// and Slice can be based on relaxed radix balanced tree, see WithRRBTree.
+// Deque, Stack, Queue, PriorityQueue, Trie, Rope and OrderedMap keep immutable lists, heaps or trees for each version, that share structure between versions.
 //
-// Note that every structure can perform total of 2^65-1 modifications, and will panic on attempt to modify it for 2^65 time.
-// If you need to continue editing structure, the good idea is to use appropriate method to dump structure for special version.
+// All structures implement Versioned interface. Slice and DoubleLinkedList also implement Indexed, while Map, OrderedMap and Trie implement Keyed,
+// so generic helpers Equal, Diff and Snapshot can work with any of them.
+//
+// Each structure keeps content hash of every version, that is updated incrementally on each modification,
+// see ContentHash methods. Slice and DoubleLinkedList compute hash on request after modifications in the middle. Equal methods and EqualVersions compare hashes before comparing values.
+//
+// Modifications of any structure can be observed with Subscribe, handlers receive ChangeEvent for each created version.
+// Map, Slice and DoubleLinkedList also stream events to channel with Changes, replaying them from any version.
+// Map can be replicated to followers with ExportOps and ApplyOps over any io.Writer and io.Reader.
+//
+// Generic transforms MapValues, Filter, FilterKeyed and SortSlice create a single new version for the whole transform,
+// SliceMap creates new Slice, Reduce and ReduceKeyed fold version into a single value.
+//
+// Note that every structure can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt
+// to modify it further. Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing structure, the good idea is to use Compact method, that creates new structure
+// with special version as the initial one. Fork method splits version off into independent structure,
+// that shares content of the version without copying and can be modified concurrently with the original one.
 package go_persistent_ds
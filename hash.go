@@ -0,0 +1,336 @@
+package go_persistent_ds
+
+import (
+	"hash/maphash"
+	"maps"
+	"math/bits"
+	"reflect"
+	"slices"
+)
+
+const (
+	// hashBase is the base of polynomial hash of sequences. It must be odd to have inverse modulo 2^64.
+	hashBase uint64 = 0x9e3779b97f4a7c15
+	// hashKeyMultiplier mixes hash of key with hash of value in hash of key-value pair.
+	hashKeyMultiplier uint64 = 0xbf58476d1ce4e5b9
+	// hashMixMultiplier is the second multiplier of mix.
+	hashMixMultiplier uint64 = 0x94d049bb133111eb
+	// inverseIterations is the amount of Newton's iterations needed to find inverse modulo 2^64:
+	// x is its own inverse modulo 2^3, and each iteration doubles the amount of correct bits.
+	inverseIterations = 5
+)
+
+var (
+	hashSeed = maphash.MakeSeed()
+	// hashBaseInverse is hashBase^-1 modulo 2^64.
+	hashBaseInverse = inverseModulo(hashBase)
+)
+
+// inverseModulo returns inverse of odd x modulo 2^64 using Newton's iterations.
+func inverseModulo(x uint64) uint64 {
+	inverse := x
+	for range inverseIterations {
+		inverse *= 2 - x*inverse
+	}
+
+	return inverse
+}
+
+// power returns x^n modulo 2^64.
+func power(x uint64, n int) uint64 {
+	result := uint64(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result *= x
+		}
+		x *= x
+	}
+
+	return result
+}
+
+// mix spreads bits of h, so that sums of mixed hashes don't collide for similar inputs.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= hashKeyMultiplier
+	h ^= h >> 27
+	h *= hashMixMultiplier
+	h ^= h >> 31
+
+	return h
+}
+
+// hashValue returns hash of value, such that equal values have equal hashes.
+// If value is not comparable, false is returned.
+func hashValue(value any) (h uint64, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	return mix(maphash.Comparable(hashSeed, value)), true
+}
+
+// valuesEqual reports whether values are equal. Comparable values are compared with ==,
+// other values are compared with reflect.DeepEqual.
+func valuesEqual[T any](a, b T) (equal bool) {
+	defer func() {
+		if recover() != nil {
+			equal = reflect.DeepEqual(a, b)
+		}
+	}()
+
+	return any(a) == any(b)
+}
+
+// sequenceHash is a polynomial hash of ordered values: sum of hash(values[i]) * hashBase^i.
+// It is updated in O(1) on adding or removing values at both ends, and in O(log(i)) on setting value by index i.
+// The zero value is the hash of empty sequence. If some value can't be hashed, the hash becomes unknown.
+// Modifications, that can't update the hash without reading values, make it stale instead,
+// and stale hash is computed from values, when it is requested.
+type sequenceHash struct {
+	sum uint64
+	// pow is hashBase^n, where n - amount of values. Powers of odd hashBase are never zero,
+	// so zero pow of the zero value stands for hashBase^0.
+	pow     uint64
+	unknown bool
+	stale   bool
+}
+
+func unknownSequenceHash() sequenceHash {
+	return sequenceHash{
+		unknown: true,
+	}
+}
+
+// invalidate returns stale hash, that is not known until it is computed from values. Unknown hash stays unknown.
+func (h sequenceHash) invalidate() sequenceHash {
+	if h.unknown {
+		return h
+	}
+
+	return sequenceHash{
+		stale: true,
+	}
+}
+
+// sequenceHashOf returns hash of given values.
+func sequenceHashOf[T any](values []T) sequenceHash {
+	var h sequenceHash
+	for _, value := range values {
+		h = h.pushBack(value)
+	}
+
+	return h
+}
+
+// contentHash returns value of hash. If hash is unknown or stale, false is returned.
+func (h sequenceHash) contentHash() (uint64, bool) {
+	return h.sum, !h.unknown && !h.stale
+}
+
+func (h sequenceHash) pushBack(value any) sequenceHash {
+	valueHash, ok := hashValue(value)
+	if !ok || h.unknown {
+		return unknownSequenceHash()
+	}
+	if h.stale {
+		return h
+	}
+
+	return sequenceHash{
+		sum: h.sum + valueHash*h.basePower(),
+		pow: h.basePower() * hashBase,
+	}
+}
+
+func (h sequenceHash) pushFront(value any) sequenceHash {
+	valueHash, ok := hashValue(value)
+	if !ok || h.unknown {
+		return unknownSequenceHash()
+	}
+	if h.stale {
+		return h
+	}
+
+	return sequenceHash{
+		sum: valueHash + h.sum*hashBase,
+		pow: h.basePower() * hashBase,
+	}
+}
+
+func (h sequenceHash) popBack(value any) sequenceHash {
+	valueHash, ok := hashValue(value)
+	if !ok || h.unknown {
+		return unknownSequenceHash()
+	}
+	if h.stale {
+		return h
+	}
+
+	pow := h.basePower() * hashBaseInverse
+
+	return sequenceHash{
+		sum: h.sum - valueHash*pow,
+		pow: pow,
+	}
+}
+
+func (h sequenceHash) popFront(value any) sequenceHash {
+	valueHash, ok := hashValue(value)
+	if !ok || h.unknown {
+		return unknownSequenceHash()
+	}
+	if h.stale {
+		return h
+	}
+
+	return sequenceHash{
+		sum: (h.sum - valueHash) * hashBaseInverse,
+		pow: h.basePower() * hashBaseInverse,
+	}
+}
+
+// set replaces oldValue by newValue at given index.
+func (h sequenceHash) set(index int, oldValue, newValue any) sequenceHash {
+	oldHash, oldOk := hashValue(oldValue)
+	newHash, newOk := hashValue(newValue)
+	if !oldOk || !newOk || h.unknown {
+		return unknownSequenceHash()
+	}
+	if h.stale {
+		return h
+	}
+
+	return sequenceHash{
+		sum: h.sum + (newHash-oldHash)*power(hashBase, index),
+		pow: h.pow,
+	}
+}
+
+// concat returns hash of values of h followed by values of other.
+func (h sequenceHash) concat(other sequenceHash) sequenceHash {
+	if h.unknown || other.unknown {
+		return unknownSequenceHash()
+	}
+	if h.stale || other.stale {
+		return h.invalidate()
+	}
+
+	return sequenceHash{
+		sum: h.sum + other.sum*h.basePower(),
+		pow: h.basePower() * other.basePower(),
+	}
+}
+
+// basePower returns hashBase^n, where n - amount of values.
+func (h sequenceHash) basePower() uint64 {
+	if h.pow == 0 {
+		return 1
+	}
+
+	return h.pow
+}
+
+// unorderedHash is a hash of unordered collection of entries: sum of hashes of entries.
+// Entry is a key-value pair for maps or a single value for collections without order.
+// The zero value is the hash of empty collection. If some entry can't be hashed, the hash becomes unknown.
+type unorderedHash struct {
+	sum     uint64
+	unknown bool
+}
+
+// contentHash returns value of hash. If hash is unknown, false is returned.
+func (h unorderedHash) contentHash() (uint64, bool) {
+	return h.sum, !h.unknown
+}
+
+func (h unorderedHash) add(entry ...any) unorderedHash {
+	entryHash, ok := hashEntry(entry)
+	if !ok || h.unknown {
+		return unorderedHash{unknown: true}
+	}
+
+	return unorderedHash{
+		sum: h.sum + entryHash,
+	}
+}
+
+func (h unorderedHash) remove(entry ...any) unorderedHash {
+	entryHash, ok := hashEntry(entry)
+	if !ok || h.unknown {
+		return unorderedHash{unknown: true}
+	}
+
+	return unorderedHash{
+		sum: h.sum - entryHash,
+	}
+}
+
+// merge returns hash of entries of both collections.
+func (h unorderedHash) merge(other unorderedHash) unorderedHash {
+	if h.unknown || other.unknown {
+		return unorderedHash{unknown: true}
+	}
+
+	return unorderedHash{
+		sum: h.sum + other.sum,
+	}
+}
+
+func hashEntry(entry []any) (uint64, bool) {
+	var entryHash uint64
+	for _, part := range entry {
+		partHash, ok := hashValue(part)
+		if !ok {
+			return 0, false
+		}
+		entryHash = bits.RotateLeft64(entryHash*hashKeyMultiplier, 1) ^ partHash
+	}
+
+	return mix(entryHash), true
+}
+
+// contentHasher is implemented by sequenceHash and unorderedHash.
+type contentHasher interface {
+	contentHash() (uint64, bool)
+}
+
+// hashesDiffer reports whether both hashes are known and differ, so contents are not equal.
+func hashesDiffer[H contentHasher](h1, h2 H) bool {
+	sum1, ok1 := h1.contentHash()
+	sum2, ok2 := h2.contentHash()
+
+	return ok1 && ok2 && sum1 != sum2
+}
+
+// equalSequences reports whether values of versions v1 and v2, that are returned by toGoSlice, are equal.
+func equalSequences[T any](toGoSlice func(version uint64) ([]T, error), v1, v2 uint64) (bool, error) {
+	values1, err := toGoSlice(v1)
+	if err != nil {
+		return false, err
+	}
+
+	values2, err := toGoSlice(v2)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.EqualFunc(values1, values2, valuesEqual[T]), nil
+}
+
+// equalMaps reports whether keys and values of versions v1 and v2, that are returned by toGoMap, are equal.
+func equalMaps[K comparable, V any](toGoMap func(version uint64) (map[K]V, error), v1, v2 uint64) (bool, error) {
+	values1, err := toGoMap(v1)
+	if err != nil {
+		return false, err
+	}
+
+	values2, err := toGoMap(v2)
+	if err != nil {
+		return false, err
+	}
+
+	return maps.EqualFunc(values1, values2, valuesEqual[V]), nil
+}
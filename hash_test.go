@@ -0,0 +1,454 @@
+package go_persistent_ds
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func hashShouldBe(t *testing.T, h sequenceHash, values []int) {
+	t.Helper()
+
+	expected, _ := sequenceHashOf(values).contentHash()
+	actual, ok := h.contentHash()
+	isTrue(t, ok)
+	if actual != expected {
+		t.Errorf("hash of %v differs from the incrementally computed one", values)
+	}
+}
+
+func TestSequenceHash(t *testing.T) {
+	var h sequenceHash
+	hashShouldBe(t, h, nil)
+
+	h = h.pushBack(2).pushBack(3).pushFront(1)
+	hashShouldBe(t, h, []int{1, 2, 3})
+
+	hashShouldBe(t, h.set(1, 2, 20), []int{1, 20, 3})
+	hashShouldBe(t, h.popFront(1), []int{2, 3})
+	hashShouldBe(t, h.popBack(3), []int{1, 2})
+	hashShouldBe(t, h.popBack(3).popBack(2).popFront(1), nil)
+	hashShouldBe(t, h.concat(h), []int{1, 2, 3, 1, 2, 3})
+	hashShouldBe(t, sequenceHash{}.concat(h), []int{1, 2, 3})
+
+	other := sequenceHashOf([]int{3, 2, 1})
+	isTrue(t, hashesDiffer(h, other))
+
+	unknown := h.pushBack([]int{4})
+	_, ok := unknown.contentHash()
+	isTrue(t, !ok)
+	_, ok = unknown.popBack(1).contentHash()
+	isTrue(t, !ok)
+	isTrue(t, !hashesDiffer(unknown, other))
+
+	stale := h.invalidate()
+	_, ok = stale.pushBack(4).popFront(1).concat(h).contentHash()
+	isTrue(t, !ok)
+	isTrue(t, !hashesDiffer(stale, other))
+	isTrue(t, unknown.invalidate().unknown)
+	isTrue(t, stale.pushBack([]int{4}).unknown)
+}
+
+func TestUnorderedHash(t *testing.T) {
+	var h unorderedHash
+	h1 := h.add("a", 1).add("b", 2)
+	h2 := h.add("b", 2).add("a", 1)
+	isTrue(t, !hashesDiffer(h1, h2))
+
+	isTrue(t, hashesDiffer(h1, h.add("a", 2).add("b", 1)))
+	isTrue(t, !hashesDiffer(h1.remove("a", 1).remove("b", 2), h))
+	isTrue(t, !hashesDiffer(h.add("a", 1).merge(h.add("b", 2)), h1))
+
+	_, ok := h1.add("c", map[int]int{}).contentHash()
+	isTrue(t, !ok)
+}
+
+func TestValuesEqual(t *testing.T) {
+	isTrue(t, valuesEqual(1, 1))
+	isTrue(t, !valuesEqual(1, 2))
+	isTrue(t, valuesEqual[any]([]int{1, 2}, []int{1, 2}))
+	isTrue(t, !valuesEqual[any]([]int{1, 2}, []int{1}))
+	isTrue(t, !valuesEqual[any](1, "1"))
+}
+
+func TestEqual_Structures(t *testing.T) {
+	t.Run("Slice", func(t *testing.T) {
+		for _, opts := range [][]SliceOption{nil, {WithRRBTree()}} {
+			s, v0 := NewSlice[int](opts...)
+			v1, err := s.Append(v0, 1)
+			errIsNil(t, err)
+			v2, err := s.Append(v1, 2)
+			errIsNil(t, err)
+			v3, err := s.Prepend(v0, 2)
+			errIsNil(t, err)
+			v4, err := s.Prepend(v3, 1)
+			errIsNil(t, err)
+			v5, err := s.Set(v2, 1, 3)
+			errIsNil(t, err)
+			v6, err := s.Range(v2, 0, 2)
+			errIsNil(t, err)
+
+			equalVersionsShouldBe(t, s, v2, v4, true)
+			equalVersionsShouldBe(t, s, v2, v6, true)
+			equalVersionsShouldBe(t, s, v2, v5, false)
+			equalVersionsShouldBe(t, s, v1, v2, false)
+
+			_, err = s.Equal(v2, v6+1)
+			isTrue(t, err != nil)
+		}
+	})
+
+	t.Run("DoubleLinkedList", func(t *testing.T) {
+		l, v0 := NewDoubleLinkedList[int]()
+		v1, err := l.PushBack(v0, 3)
+		errIsNil(t, err)
+		v2, err := l.PushFront(v1, 1)
+		errIsNil(t, err)
+		v3, err := l.InsertAt(v2, 1, 2)
+		errIsNil(t, err)
+		v4, err := l.Splice(v0, 0, l, v3)
+		errIsNil(t, err)
+		v5, err := l.Remove(v4, 1)
+		errIsNil(t, err)
+
+		cursor, err := l.Front(v3)
+		errIsNil(t, err)
+		_, v6, err := cursor.InsertAfter(2)
+		errIsNil(t, err)
+		v7, err := l.Remove(v6, 1)
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, l, v3, v4, true)
+		equalVersionsShouldBe(t, l, v2, v5, true)
+		equalVersionsShouldBe(t, l, v3, v7, true)
+		equalVersionsShouldBe(t, l, v3, v6, false)
+		equalVersionsShouldBe(t, l, v2, v3, false)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		for _, opts := range [][]MapOption{nil, {WithHAMT()}} {
+			m, v0 := NewMap[string, int](opts...)
+			v1, err := m.Set(v0, "a", 1)
+			errIsNil(t, err)
+			v2, err := m.Set(v1, "b", 2)
+			errIsNil(t, err)
+			v3, err := m.Set(v0, "b", 2)
+			errIsNil(t, err)
+			v4, err := m.Set(v3, "a", 10)
+			errIsNil(t, err)
+			v5, err := m.Set(v4, "a", 1)
+			errIsNil(t, err)
+			v6, err := m.Delete(v5, "a")
+			errIsNil(t, err)
+
+			equalVersionsShouldBe(t, m, v2, v5, true)
+			equalVersionsShouldBe(t, m, v3, v6, true)
+			equalVersionsShouldBe(t, m, v2, v4, false)
+			equalVersionsShouldBe(t, m, v0, v1, false)
+		}
+	})
+
+	t.Run("Deque", func(t *testing.T) {
+		d, v0 := NewDeque[int]()
+		v1, err := d.PushBack(v0, 1)
+		errIsNil(t, err)
+		v2, err := d.PushBack(v1, 2)
+		errIsNil(t, err)
+		v3, err := d.PushFront(v0, 2)
+		errIsNil(t, err)
+		v4, err := d.PushFront(v3, 1)
+		errIsNil(t, err)
+		_, v5, err := d.PopBack(v4)
+		errIsNil(t, err)
+		_, v6, err := d.PopFront(v2)
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, d, v2, v4, true)
+		equalVersionsShouldBe(t, d, v1, v5, true)
+		equalVersionsShouldBe(t, d, v3, v6, true)
+		equalVersionsShouldBe(t, d, v1, v6, false)
+	})
+
+	t.Run("Stack", func(t *testing.T) {
+		s, v0 := NewStack[int]()
+		v1, err := s.Push(v0, 1)
+		errIsNil(t, err)
+		v2, err := s.Push(v1, 2)
+		errIsNil(t, err)
+		_, v3, err := s.Pop(v2)
+		errIsNil(t, err)
+		v4, err := s.Push(v0, 2)
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, s, v1, v3, true)
+		equalVersionsShouldBe(t, s, v1, v4, false)
+	})
+
+	t.Run("Queue", func(t *testing.T) {
+		q, v0 := NewQueue[int]()
+		v1, err := q.Enqueue(v0, 1)
+		errIsNil(t, err)
+		v2, err := q.Enqueue(v1, 2)
+		errIsNil(t, err)
+		_, v3, err := q.Dequeue(v2)
+		errIsNil(t, err)
+		v4, err := q.Enqueue(v0, 2)
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, q, v3, v4, true)
+		equalVersionsShouldBe(t, q, v1, v4, false)
+	})
+
+	t.Run("PriorityQueue", func(t *testing.T) {
+		pq, v0 := NewPriorityQueue(func(a, b [2]int) bool {
+			return a[0] < b[0]
+		})
+		v1, err := pq.Push(v0, [2]int{1, 1})
+		errIsNil(t, err)
+		v2, err := pq.Push(v1, [2]int{1, 2})
+		errIsNil(t, err)
+		v3, err := pq.Push(v0, [2]int{1, 2})
+		errIsNil(t, err)
+		v4, err := pq.Meld(v3, v1)
+		errIsNil(t, err)
+		v5, err := pq.Push(v3, [2]int{1, 3})
+		errIsNil(t, err)
+		v6, err := pq.Push(v1, [2]int{0, 1})
+		errIsNil(t, err)
+		_, v7, err := pq.PopMin(v6)
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, pq, v2, v4, true)
+		equalVersionsShouldBe(t, pq, v1, v7, true)
+		equalVersionsShouldBe(t, pq, v2, v5, false)
+		equalVersionsShouldBe(t, pq, v2, v6, false)
+	})
+
+	t.Run("Trie", func(t *testing.T) {
+		tr, v0 := NewTrie[int]()
+		v1, err := tr.Set(v0, "ab", 1)
+		errIsNil(t, err)
+		v2, err := tr.Set(v1, "a", 2)
+		errIsNil(t, err)
+		v3, err := tr.Set(v0, "a", 2)
+		errIsNil(t, err)
+		v4, err := tr.Set(v3, "ab", 1)
+		errIsNil(t, err)
+		v5, err := tr.Delete(v4, "ab")
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, tr, v2, v4, true)
+		equalVersionsShouldBe(t, tr, v3, v5, true)
+		equalVersionsShouldBe(t, tr, v1, v3, false)
+	})
+
+	t.Run("OrderedMap", func(t *testing.T) {
+		m, v0 := NewOrderedMap[int, int]()
+		v1, err := m.Set(v0, 1, 1)
+		errIsNil(t, err)
+		v2, err := m.Set(v1, 2, 2)
+		errIsNil(t, err)
+		v3, err := m.Set(v0, 2, 2)
+		errIsNil(t, err)
+		v4, err := m.Set(v3, 1, 1)
+		errIsNil(t, err)
+		v5, err := m.Delete(v4, 1)
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, m, v2, v4, true)
+		equalVersionsShouldBe(t, m, v3, v5, true)
+		equalVersionsShouldBe(t, m, v1, v3, false)
+	})
+
+	t.Run("Rope", func(t *testing.T) {
+		r, v0 := NewRopeFromString("hello world")
+		v1, err := r.Delete(v0, 5, 6)
+		errIsNil(t, err)
+		v2, err := r.Insert(v1, 5, " world")
+		errIsNil(t, err)
+		v3, err := r.Insert(v1, 0, " world")
+		errIsNil(t, err)
+
+		equalVersionsShouldBe(t, r, v0, v2, true)
+		equalVersionsShouldBe(t, r, v2, v3, false)
+	})
+}
+
+type equalHasher interface {
+	Equal(v1, v2 uint64) (bool, error)
+	ContentHash(version uint64) (uint64, bool)
+}
+
+func equalVersionsShouldBe(t *testing.T, c equalHasher, v1, v2 uint64, expected bool) {
+	t.Helper()
+
+	equal, err := c.Equal(v1, v2)
+	errIsNil(t, err)
+	if equal != expected {
+		t.Errorf("expected versions %d and %d to be equal: %v", v1, v2, expected)
+	}
+
+	h1, ok1 := c.ContentHash(v1)
+	h2, ok2 := c.ContentHash(v2)
+	if expected && ok1 && ok2 && h1 != h2 {
+		t.Errorf("equal versions %d and %d have different hashes", v1, v2)
+	}
+}
+
+func TestContentHash_RandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, sliceVersion := NewSlice[int](WithRRBTree())
+	l, listVersion := NewDoubleLinkedList[int]()
+	sliceVersions, listVersions := []uint64{sliceVersion}, []uint64{listVersion}
+
+	var err error
+	for i := 0; i < 300; i++ {
+		from := r.Intn(len(sliceVersions))
+		size, _ := s.Len(sliceVersions[from])
+		value := r.Intn(3)
+
+		switch op := r.Intn(5); {
+		case op == 0:
+			sliceVersion, err = s.Append(sliceVersions[from], value)
+			errIsNil(t, err)
+			listVersion, err = l.PushBack(listVersions[from], value)
+		case op == 1:
+			sliceVersion, err = s.Prepend(sliceVersions[from], value)
+			errIsNil(t, err)
+			listVersion, err = l.PushFront(listVersions[from], value)
+		case op == 2 && size > 0:
+			index := r.Intn(size)
+			sliceVersion, err = s.Set(sliceVersions[from], index, value)
+			errIsNil(t, err)
+			listVersion, err = l.Update(listVersions[from], index, value)
+		case op == 3 && size > 0:
+			sliceVersion, err = s.Slice(sliceVersions[from], 1, size)
+			errIsNil(t, err)
+			_, listVersion, err = l.PopFront(listVersions[from])
+		default:
+			sliceVersion, err = s.Concat(sliceVersions[from], s, sliceVersions[from])
+			errIsNil(t, err)
+			listVersion, err = l.Splice(listVersions[from], size, l, listVersions[from])
+		}
+		errIsNil(t, err)
+
+		sliceVersions = append(sliceVersions, sliceVersion)
+		listVersions = append(listVersions, listVersion)
+	}
+
+	for i, listVersion := range listVersions {
+		values, err := l.ToGoSlice(listVersion)
+		errIsNil(t, err)
+
+		hash, ok := l.ContentHash(listVersion)
+		isTrue(t, ok)
+		expected, _ := sequenceHashOf(values).contentHash()
+		isTrue(t, hash == expected)
+
+		equal, err := EqualVersions[int](s, sliceVersions[i], l, listVersion, nil)
+		errIsNil(t, err)
+		isTrue(t, equal)
+
+		hash, ok = s.ContentHash(sliceVersions[i])
+		isTrue(t, ok && hash == expected)
+	}
+}
+
+func TestContentHash_Cursor(t *testing.T) {
+	l, version := NewDoubleLinkedList[int]()
+	var err error
+	for _, value := range []int{1, 2, 3} {
+		version, err = l.PushBack(version, value)
+		errIsNil(t, err)
+	}
+
+	hashShouldBe := func(version uint64, values []int) {
+		t.Helper()
+
+		hash, ok := l.ContentHash(version)
+		expected, _ := sequenceHashOf(values).contentHash()
+		isTrue(t, ok && hash == expected)
+	}
+
+	c, err := l.CursorAt(version, 1)
+	errIsNil(t, err)
+
+	c, version, err = c.InsertBefore(4)
+	errIsNil(t, err)
+	hashShouldBe(version, []int{1, 4, 2, 3})
+
+	c, version, err = c.InsertAfter(5)
+	errIsNil(t, err)
+	hashShouldBe(version, []int{1, 4, 2, 5, 3})
+
+	_, version, err = c.Remove()
+	errIsNil(t, err)
+	hashShouldBe(version, []int{1, 4, 5, 3})
+}
+
+func TestContentHash_Stale(t *testing.T) {
+	t.Run("Slice", func(t *testing.T) {
+		t.Parallel()
+
+		s, version := NewSlice[int]()
+		var err error
+		for i := 0; i < 10; i++ {
+			version, err = s.Append(version, i)
+			errIsNil(t, err)
+		}
+
+		version, err = s.Set(version, 5, 50)
+		errIsNil(t, err)
+		version, err = s.Range(version, 1, 9)
+		errIsNil(t, err)
+		version, err = s.Truncate(version, 6)
+		errIsNil(t, err)
+		version, err = s.Append(version, 10)
+		errIsNil(t, err)
+
+		info, _ := s.versionTree.GetVersionInfo(version)
+		isTrue(t, info.hash.stale)
+
+		hash, ok := s.ContentHash(version)
+		expected, _ := sequenceHashOf([]int{1, 2, 3, 4, 50, 6, 10}).contentHash()
+		isTrue(t, ok && hash == expected)
+		isTrue(t, !info.hash.stale)
+
+		version, err = s.Append(version, 11)
+		errIsNil(t, err)
+		info, _ = s.versionTree.GetVersionInfo(version)
+		isTrue(t, !info.hash.stale)
+	})
+
+	t.Run("DoubleLinkedList", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := NewDoubleLinkedList[int]()
+		var err error
+		for i := 0; i < 5; i++ {
+			version, err = l.PushBack(version, i)
+			errIsNil(t, err)
+		}
+
+		version, err = l.InsertAt(version, 2, 20)
+		errIsNil(t, err)
+		version, err = l.Remove(version, 4)
+		errIsNil(t, err)
+		stale, err := l.PushFront(version, -1)
+		errIsNil(t, err)
+
+		other, otherVersion := NewDoubleLinkedList[int]()
+		for _, value := range []int{-1, 0, 1, 20, 2, 4} {
+			otherVersion, err = other.PushBack(otherVersion, value)
+			errIsNil(t, err)
+		}
+
+		equal, err := l.Equal(stale, stale)
+		errIsNil(t, err)
+		isTrue(t, equal)
+
+		hash, ok := l.ContentHash(stale)
+		otherHash, otherOk := other.ContentHash(otherVersion)
+		isTrue(t, ok && otherOk && hash == otherHash)
+	})
+}
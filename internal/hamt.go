@@ -0,0 +1,277 @@
+package internal
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	hamtBitsPerLevel = 5
+	hamtLevelMask    = 1<<hamtBitsPerLevel - 1
+)
+
+// HAMT is an immutable hash array mapped trie.
+// Every operation returns new trie and never changes the old one: only nodes on the path
+// to the changed key are copied, all other nodes are shared.
+//
+// Complexity of Get, Set and Delete is O(log32(n)).
+type HAMT[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	size int
+	seed maphash.Seed
+}
+
+// hamtNode stores entries only for set bits of bitmap, entries are ordered by bit index.
+type hamtNode[K comparable, V any] struct {
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+}
+
+// hamtEntry is either a child node or a leaf with pairs, which keys have the same hash.
+type hamtEntry[K comparable, V any] struct {
+	child *hamtNode[K, V]
+	hash  uint64
+	pairs []hamtPair[K, V]
+}
+
+type hamtPair[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewHAMT creates empty HAMT. Tries derived from it hash keys the same way.
+func NewHAMT[K comparable, V any]() HAMT[K, V] {
+	return HAMT[K, V]{
+		seed: maphash.MakeSeed(),
+	}
+}
+
+// Len returns the amount of keys in HAMT.
+func (h HAMT[K, V]) Len() int {
+	return h.size
+}
+
+// Get returns value for given key. If there is no such key, false is returned.
+func (h HAMT[K, V]) Get(key K) (V, bool) {
+	hash := maphash.Comparable(h.seed, key)
+
+	node := h.root
+	for shift := 0; node != nil; shift += hamtBitsPerLevel {
+		entry, ok := node.entry(hash, shift)
+		if !ok {
+			break
+		}
+		if entry.child != nil {
+			node = entry.child
+			continue
+		}
+
+		if entry.hash == hash {
+			for _, pair := range entry.pairs {
+				if pair.key == key {
+					return pair.value, true
+				}
+			}
+		}
+		break
+	}
+
+	return *new(V), false
+}
+
+// Set returns new HAMT with value set for given key.
+func (h HAMT[K, V]) Set(key K, value V) HAMT[K, V] {
+	hash := maphash.Comparable(h.seed, key)
+
+	root := h.root
+	if root == nil {
+		root = &hamtNode[K, V]{}
+	}
+
+	newRoot, added := root.set(hash, 0, hamtPair[K, V]{key: key, value: value})
+
+	newTrie := HAMT[K, V]{root: newRoot, size: h.size, seed: h.seed}
+	if added {
+		newTrie.size++
+	}
+
+	return newTrie
+}
+
+// Delete returns new HAMT without given key. If there is no such key, false is returned.
+func (h HAMT[K, V]) Delete(key K) (HAMT[K, V], bool) {
+	if h.root == nil {
+		return h, false
+	}
+
+	hash := maphash.Comparable(h.seed, key)
+
+	newRoot, removed := h.root.delete(hash, 0, key)
+	if !removed {
+		return h, false
+	}
+
+	return HAMT[K, V]{root: newRoot, size: h.size - 1, seed: h.seed}, true
+}
+
+// All calls yield for each pair of HAMT in no particular order until yield returns false.
+//
+// Complexity: O(n).
+func (h HAMT[K, V]) All(yield func(K, V) bool) {
+	if h.root != nil {
+		h.root.all(yield)
+	}
+}
+
+func hamtBit(hash uint64, shift int) uint32 {
+	return 1 << ((hash >> shift) & hamtLevelMask)
+}
+
+// position returns index of entry for the bit in entries.
+func (n *hamtNode[K, V]) position(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[K, V]) entry(hash uint64, shift int) (hamtEntry[K, V], bool) {
+	bit := hamtBit(hash, shift)
+	if n.bitmap&bit == 0 {
+		return hamtEntry[K, V]{}, false
+	}
+
+	return n.entries[n.position(bit)], true
+}
+
+func (n *hamtNode[K, V]) withEntry(pos int, entry hamtEntry[K, V]) *hamtNode[K, V] {
+	entries := make([]hamtEntry[K, V], len(n.entries))
+	copy(entries, n.entries)
+	entries[pos] = entry
+
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode[K, V]) set(hash uint64, shift int, pair hamtPair[K, V]) (*hamtNode[K, V], bool) {
+	bit := hamtBit(hash, shift)
+	pos := n.position(bit)
+
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry[K, V], 0, len(n.entries)+1)
+		entries = append(entries, n.entries[:pos]...)
+		entries = append(entries, hamtEntry[K, V]{hash: hash, pairs: []hamtPair[K, V]{pair}})
+		entries = append(entries, n.entries[pos:]...)
+
+		return &hamtNode[K, V]{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+
+	entry := n.entries[pos]
+	switch {
+	case entry.child != nil:
+		child, added := entry.child.set(hash, shift+hamtBitsPerLevel, pair)
+		return n.withEntry(pos, hamtEntry[K, V]{child: child}), added
+	case entry.hash == hash:
+		pairs := make([]hamtPair[K, V], len(entry.pairs), len(entry.pairs)+1)
+		copy(pairs, entry.pairs)
+
+		for i := range pairs {
+			if pairs[i].key == pair.key {
+				pairs[i].value = pair.value
+				return n.withEntry(pos, hamtEntry[K, V]{hash: hash, pairs: pairs}), false
+			}
+		}
+
+		return n.withEntry(pos, hamtEntry[K, V]{hash: hash, pairs: append(pairs, pair)}), true
+	default:
+		// two different hashes share the slot, so they are moved to the next level
+		child := &hamtNode[K, V]{
+			bitmap:  hamtBit(entry.hash, shift+hamtBitsPerLevel),
+			entries: []hamtEntry[K, V]{entry},
+		}
+		child, _ = child.set(hash, shift+hamtBitsPerLevel, pair)
+
+		return n.withEntry(pos, hamtEntry[K, V]{child: child}), true
+	}
+}
+
+// delete returns node without the key. If the node becomes empty, nil is returned.
+func (n *hamtNode[K, V]) delete(hash uint64, shift int, key K) (*hamtNode[K, V], bool) {
+	bit := hamtBit(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	pos := n.position(bit)
+	entry := n.entries[pos]
+
+	var newEntry hamtEntry[K, V]
+	switch {
+	case entry.child != nil:
+		child, removed := entry.child.delete(hash, shift+hamtBitsPerLevel, key)
+		if !removed {
+			return n, false
+		}
+
+		switch {
+		case child == nil:
+			return n.without(pos, bit), true
+		case len(child.entries) == 1 && child.entries[0].child == nil:
+			// single leaf is pulled up, so the trie stays compact
+			newEntry = child.entries[0]
+		default:
+			newEntry = hamtEntry[K, V]{child: child}
+		}
+	case entry.hash == hash:
+		i := -1
+		for j, pair := range entry.pairs {
+			if pair.key == key {
+				i = j
+				break
+			}
+		}
+		if i == -1 {
+			return n, false
+		}
+		if len(entry.pairs) == 1 {
+			return n.without(pos, bit), true
+		}
+
+		pairs := make([]hamtPair[K, V], 0, len(entry.pairs)-1)
+		pairs = append(pairs, entry.pairs[:i]...)
+		pairs = append(pairs, entry.pairs[i+1:]...)
+		newEntry = hamtEntry[K, V]{hash: hash, pairs: pairs}
+	default:
+		return n, false
+	}
+
+	return n.withEntry(pos, newEntry), true
+}
+
+// without returns node without entry by given position and bit. If the node becomes empty, nil is returned.
+func (n *hamtNode[K, V]) without(pos int, bit uint32) *hamtNode[K, V] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+
+	entries := make([]hamtEntry[K, V], 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:pos]...)
+	entries = append(entries, n.entries[pos+1:]...)
+
+	return &hamtNode[K, V]{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+func (n *hamtNode[K, V]) all(yield func(K, V) bool) bool {
+	for _, entry := range n.entries {
+		if entry.child != nil {
+			if !entry.child.all(yield) {
+				return false
+			}
+			continue
+		}
+
+		for _, pair := range entry.pairs {
+			if !yield(pair.key, pair.value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"maps"
+	"testing"
+)
+
+func hamtToMap(h HAMT[int, int]) map[int]int {
+	res := make(map[int]int)
+	h.All(func(k, v int) bool {
+		res[k] = v
+		return true
+	})
+
+	return res
+}
+
+func TestHAMT_SetGetDelete(t *testing.T) {
+	h := NewHAMT[int, int]()
+	expected := make(map[int]int)
+
+	for i := 0; i < 5000; i++ {
+		h = h.Set(i, i*2)
+		expected[i] = i * 2
+	}
+	old := h
+
+	for i := 0; i < 5000; i += 3 {
+		var removed bool
+		h, removed = h.Delete(i)
+		if !removed {
+			t.Fatalf("Expected key %d to be removed", i)
+		}
+		delete(expected, i)
+	}
+
+	if _, removed := h.Delete(0); removed {
+		t.Error("Expected key 0 to be already removed")
+	}
+
+	if h.Len() != len(expected) {
+		t.Errorf("Expected len %d, got: %d", len(expected), h.Len())
+	}
+	if !maps.Equal(hamtToMap(h), expected) {
+		t.Error("HAMT differs from expected map")
+	}
+	for k, v := range expected {
+		got, ok := h.Get(k)
+		if !ok || got != v {
+			t.Errorf("Expected %d by key %d, got: %d, %v", v, k, got, ok)
+		}
+	}
+
+	if old.Len() != 5000 {
+		t.Errorf("Expected old trie to stay the same, got len: %d", old.Len())
+	}
+	if val, ok := old.Get(0); !ok || val != 0 {
+		t.Error("Expected old trie to contain key 0")
+	}
+}
+
+func TestHAMT_Collisions(t *testing.T) {
+	root := &hamtNode[string, int]{}
+
+	// keys "a" and "b" have the same hash, "c" differs only in the last level
+	const hash uint64 = 0x0123456789abcdef
+	root, _ = root.set(hash, 0, hamtPair[string, int]{key: "a", value: 1})
+	root, _ = root.set(hash, 0, hamtPair[string, int]{key: "b", value: 2})
+	root, _ = root.set(hash^(1<<63), 0, hamtPair[string, int]{key: "c", value: 3})
+
+	h := HAMT[string, int]{root: root, size: 3}
+	res := make(map[string]int)
+	h.All(func(k string, v int) bool {
+		res[k] = v
+		return true
+	})
+	if !maps.Equal(res, map[string]int{"a": 1, "b": 2, "c": 3}) {
+		t.Errorf("Unexpected content: %v", res)
+	}
+
+	root, removed := root.delete(hash, 0, "a")
+	if !removed {
+		t.Fatal("Expected key a to be removed")
+	}
+	root, removed = root.delete(hash^(1<<63), 0, "c")
+	if !removed {
+		t.Fatal("Expected key c to be removed")
+	}
+
+	if len(root.entries) != 1 || root.entries[0].child != nil || root.entries[0].pairs[0].key != "b" {
+		t.Error("Expected single leaf to be pulled up to the root")
+	}
+}
@@ -0,0 +1,25 @@
+package internal
+
+// HashCell is a Cell, that keeps values in hash map by versions.
+// Unlike FatNode it reads value in O(1), but takes more memory.
+type HashCell struct {
+	values map[uint64]any
+}
+
+// NewHashCell creates new HashCell.
+func NewHashCell(version uint64, value any) *HashCell {
+	return &HashCell{
+		values: map[uint64]any{version: value},
+	}
+}
+
+// Read returns value, that was written exactly for given version.
+func (c *HashCell) Read(version uint64) (any, bool) {
+	value, found := c.values[version]
+	return value, found
+}
+
+// Write writes value for given version.
+func (c *HashCell) Write(version uint64, value any) {
+	c.values[version] = value
+}
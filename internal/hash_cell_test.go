@@ -0,0 +1,20 @@
+package internal
+
+import "testing"
+
+func TestHashCell(t *testing.T) {
+	cell := NewHashCell(1, "a")
+	cell.Write(4, "b")
+	cell.Write(7, nil)
+
+	for version, expected := range map[uint64]any{1: "a", 4: "b", 7: nil} {
+		value, found := cell.Read(version)
+		if !found || value != expected {
+			t.Errorf("Expected %v for version %d, got: %v, %v", expected, version, value, found)
+		}
+	}
+
+	if _, found := cell.Read(2); found {
+		t.Error("Expected no value for version 2")
+	}
+}
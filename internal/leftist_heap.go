@@ -0,0 +1,123 @@
+package internal
+
+// LeftistHeap is an immutable leftist min-heap.
+// Every operation returns new heap and never changes the old one, so heaps share structure.
+//
+// Complexity of Push, PopMin and Merge is O(log(n)), complexity of Min is O(1).
+type LeftistHeap[T any] struct {
+	root *heapNode[T]
+	size int
+	less func(a, b T) bool
+}
+
+type heapNode[T any] struct {
+	value T
+	// rank is the length of the right spine of the node.
+	rank  int
+	left  *heapNode[T]
+	right *heapNode[T]
+}
+
+// NewLeftistHeap creates empty LeftistHeap ordered by less.
+func NewLeftistHeap[T any](less func(a, b T) bool) LeftistHeap[T] {
+	return LeftistHeap[T]{
+		less: less,
+	}
+}
+
+// Len returns the amount of values in LeftistHeap.
+func (h LeftistHeap[T]) Len() int {
+	return h.size
+}
+
+// Min returns the minimal value of LeftistHeap. If the heap is empty, false is returned.
+func (h LeftistHeap[T]) Min() (T, bool) {
+	if h.root == nil {
+		return *new(T), false
+	}
+
+	return h.root.value, true
+}
+
+// Push returns new LeftistHeap with value added.
+func (h LeftistHeap[T]) Push(value T) LeftistHeap[T] {
+	return LeftistHeap[T]{
+		root: h.merge(h.root, &heapNode[T]{value: value, rank: 1}),
+		size: h.size + 1,
+		less: h.less,
+	}
+}
+
+// PopMin returns new LeftistHeap without the minimal value. PopMin of empty heap is empty heap.
+func (h LeftistHeap[T]) PopMin() LeftistHeap[T] {
+	if h.root == nil {
+		return h
+	}
+
+	return LeftistHeap[T]{
+		root: h.merge(h.root.left, h.root.right),
+		size: h.size - 1,
+		less: h.less,
+	}
+}
+
+// Merge returns new LeftistHeap with values of both heaps. Other heap must use the same order.
+func (h LeftistHeap[T]) Merge(other LeftistHeap[T]) LeftistHeap[T] {
+	return LeftistHeap[T]{
+		root: h.merge(h.root, other.root),
+		size: h.size + other.size,
+		less: h.less,
+	}
+}
+
+// Values returns all values of LeftistHeap in no particular order.
+//
+// Complexity: O(n).
+func (h LeftistHeap[T]) Values() []T {
+	values := make([]T, 0, h.size)
+	stack := []*heapNode[T]{h.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == nil {
+			continue
+		}
+
+		values = append(values, node.value)
+		stack = append(stack, node.left, node.right)
+	}
+
+	return values
+}
+
+func (h LeftistHeap[T]) merge(a, b *heapNode[T]) *heapNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b.value, a.value) {
+		a, b = b, a
+	}
+
+	left, right := a.left, h.merge(a.right, b)
+	if heapRank(left) < heapRank(right) {
+		left, right = right, left
+	}
+
+	return &heapNode[T]{
+		value: a.value,
+		rank:  heapRank(right) + 1,
+		left:  left,
+		right: right,
+	}
+}
+
+func heapRank[T any](node *heapNode[T]) int {
+	if node == nil {
+		return 0
+	}
+
+	return node.rank
+}
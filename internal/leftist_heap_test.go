@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func popAll(h LeftistHeap[int]) []int {
+	var values []int
+	for h.Len() > 0 {
+		val, _ := h.Min()
+		values = append(values, val)
+		h = h.PopMin()
+	}
+
+	return values
+}
+
+func TestLeftistHeap_PushAndPopMin(t *testing.T) {
+	h := NewLeftistHeap(intLess)
+	if _, ok := h.Min(); ok {
+		t.Error("Expected empty heap")
+	}
+
+	for _, val := range []int{5, 3, 8, 1, 9, 2, 7} {
+		h = h.Push(val)
+	}
+
+	values := popAll(h)
+	if !slices.Equal(values, []int{1, 2, 3, 5, 7, 8, 9}) {
+		t.Errorf("Expected sorted values, got: %v", values)
+	}
+	if h.Len() != 7 {
+		t.Errorf("Expected old heap to stay the same, got len: %d", h.Len())
+	}
+}
+
+func TestLeftistHeap_Merge(t *testing.T) {
+	a := NewLeftistHeap(intLess).Push(4).Push(1).Push(6)
+	b := NewLeftistHeap(intLess).Push(3).Push(5).Push(2)
+
+	merged := a.Merge(b)
+	if merged.Len() != 6 {
+		t.Errorf("Expected len 6, got: %d", merged.Len())
+	}
+
+	values := popAll(merged)
+	if !slices.Equal(values, []int{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("Expected sorted values, got: %v", values)
+	}
+	if !slices.Equal(popAll(a), []int{1, 4, 6}) {
+		t.Errorf("Expected heap to stay the same, got: %v", popAll(a))
+	}
+}
@@ -1,5 +1,13 @@
 package internal
 
+// Cell stores values of a single position of structure (key, index, link) by versions.
+type Cell interface {
+	// Read returns value, that was written exactly for given version.
+	Read(version uint64) (any, bool)
+	// Write writes value for given version. Versions are written in increasing order.
+	Write(version uint64, value any)
+}
+
 // FatNode is a structure that stores values by versions.
 type FatNode struct {
 	nodes []*node
@@ -27,6 +35,17 @@ func (fn *FatNode) Update(data interface{}, newVersion uint64) {
 	fn.nodes = append(fn.nodes, newNode(data, newVersion))
 }
 
+// Read returns value, that was written exactly for given version.
+func (fn *FatNode) Read(version uint64) (any, bool) {
+	data, _, found := fn.FindByVersion(version)
+	return data, found
+}
+
+// Write writes value for given version.
+func (fn *FatNode) Write(version uint64, value any) {
+	fn.Update(value, version)
+}
+
 // FindByVersion finds needed version of object inside FatNode using binary search.
 // If the version is not found, then the pair (nil, 0, false) is returned.
 func (fn *FatNode) FindByVersion(version uint64) (interface{}, uint64, bool) {
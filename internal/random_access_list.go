@@ -0,0 +1,153 @@
+package internal
+
+// RandomAccessList is an immutable skew binary random access list.
+// Every operation returns new list and never changes the old one, so lists share structure.
+//
+// Complexity of Cons, Head and Tail is O(1), complexity of Get is O(log(n)).
+type RandomAccessList[T any] struct {
+	spine *ralSpine[T]
+	size  int
+}
+
+// ralSpine is a list of complete binary trees with non-decreasing sizes.
+type ralSpine[T any] struct {
+	treeSize int
+	tree     *ralTree[T]
+	next     *ralSpine[T]
+}
+
+type ralTree[T any] struct {
+	value T
+	left  *ralTree[T]
+	right *ralTree[T]
+}
+
+// NewRandomAccessList creates RandomAccessList with given values, first value becomes the head.
+//
+// Complexity: O(n).
+func NewRandomAccessList[T any](values []T) RandomAccessList[T] {
+	l := RandomAccessList[T]{}
+	for i := len(values) - 1; i >= 0; i-- {
+		l = l.Cons(values[i])
+	}
+
+	return l
+}
+
+// Len returns the amount of values in RandomAccessList.
+func (l RandomAccessList[T]) Len() int {
+	return l.size
+}
+
+// Cons returns new RandomAccessList with value added before the head.
+func (l RandomAccessList[T]) Cons(value T) RandomAccessList[T] {
+	if l.spine != nil && l.spine.next != nil && l.spine.treeSize == l.spine.next.treeSize {
+		return RandomAccessList[T]{
+			spine: &ralSpine[T]{
+				treeSize: 2*l.spine.treeSize + 1,
+				tree: &ralTree[T]{
+					value: value,
+					left:  l.spine.tree,
+					right: l.spine.next.tree,
+				},
+				next: l.spine.next.next,
+			},
+			size: l.size + 1,
+		}
+	}
+
+	return RandomAccessList[T]{
+		spine: &ralSpine[T]{
+			treeSize: 1,
+			tree:     &ralTree[T]{value: value},
+			next:     l.spine,
+		},
+		size: l.size + 1,
+	}
+}
+
+// Head returns the first value of RandomAccessList. If the list is empty, false is returned.
+func (l RandomAccessList[T]) Head() (T, bool) {
+	if l.spine == nil {
+		return *new(T), false
+	}
+
+	return l.spine.tree.value, true
+}
+
+// Tail returns RandomAccessList without the first value. Tail of empty list is empty list.
+func (l RandomAccessList[T]) Tail() RandomAccessList[T] {
+	if l.spine == nil {
+		return l
+	}
+
+	if l.spine.treeSize == 1 {
+		return RandomAccessList[T]{
+			spine: l.spine.next,
+			size:  l.size - 1,
+		}
+	}
+
+	halfSize := l.spine.treeSize / 2
+	return RandomAccessList[T]{
+		spine: &ralSpine[T]{
+			treeSize: halfSize,
+			tree:     l.spine.tree.left,
+			next: &ralSpine[T]{
+				treeSize: halfSize,
+				tree:     l.spine.tree.right,
+				next:     l.spine.next,
+			},
+		},
+		size: l.size - 1,
+	}
+}
+
+// Get returns value by index counting from the head. If index is out of range, false is returned.
+func (l RandomAccessList[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= l.size {
+		return *new(T), false
+	}
+
+	spine := l.spine
+	for index >= spine.treeSize {
+		index -= spine.treeSize
+		spine = spine.next
+	}
+
+	tree, treeSize := spine.tree, spine.treeSize
+	for index != 0 {
+		treeSize /= 2
+		if index <= treeSize {
+			tree = tree.left
+			index--
+		} else {
+			tree = tree.right
+			index -= treeSize + 1
+		}
+	}
+
+	return tree.value, true
+}
+
+// Values returns all values of RandomAccessList starting from the head.
+//
+// Complexity: O(n).
+func (l RandomAccessList[T]) Values() []T {
+	values := make([]T, 0, l.size)
+	for spine := l.spine; spine != nil; spine = spine.next {
+		values = appendPreorder(values, spine.tree)
+	}
+
+	return values
+}
+
+func appendPreorder[T any](values []T, tree *ralTree[T]) []T {
+	if tree == nil {
+		return values
+	}
+
+	values = append(values, tree.value)
+	values = appendPreorder(values, tree.left)
+	return appendPreorder(values, tree.right)
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRandomAccessList_ConsAndGet(t *testing.T) {
+	l := RandomAccessList[int]{}
+	for i := 99; i >= 0; i-- {
+		l = l.Cons(i)
+	}
+
+	if l.Len() != 100 {
+		t.Fatalf("Expected len 100, got: %d", l.Len())
+	}
+	for i := 0; i < 100; i++ {
+		val, ok := l.Get(i)
+		if !ok || val != i {
+			t.Errorf("Expected %d by index %d, got: %d, %v", i, i, val, ok)
+		}
+	}
+	if _, ok := l.Get(100); ok {
+		t.Error("Expected no value by index 100")
+	}
+}
+
+func TestRandomAccessList_HeadAndTail(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	l := NewRandomAccessList(values)
+
+	for i := range values {
+		head, ok := l.Head()
+		if !ok || head != values[i] {
+			t.Errorf("Expected head %d, got: %d, %v", values[i], head, ok)
+		}
+
+		tail := l.Tail()
+		if !slices.Equal(tail.Values(), values[i+1:]) {
+			t.Errorf("Expected tail %v, got: %v", values[i+1:], tail.Values())
+		}
+		l = tail
+	}
+
+	if _, ok := l.Head(); ok {
+		t.Error("Expected empty list")
+	}
+}
+
+func TestRandomAccessList_Persistence(t *testing.T) {
+	l := NewRandomAccessList([]int{1, 2, 3})
+	withZero := l.Cons(0)
+	withoutOne := l.Tail()
+
+	if !slices.Equal(l.Values(), []int{1, 2, 3}) {
+		t.Errorf("Expected old list to stay the same, got: %v", l.Values())
+	}
+	if !slices.Equal(withZero.Values(), []int{0, 1, 2, 3}) {
+		t.Errorf("Expected [0 1 2 3], got: %v", withZero.Values())
+	}
+	if !slices.Equal(withoutOne.Values(), []int{2, 3}) {
+		t.Errorf("Expected [2 3], got: %v", withoutOne.Values())
+	}
+}
@@ -0,0 +1,136 @@
+package internal
+
+import "sync"
+
+// RealTimeQueue is an immutable Okasaki's real-time FIFO queue.
+// Every operation returns new queue and never changes the old one, so queues share structure.
+//
+// Values are added to the rear list and are moved to the lazy front stream by rotation. Rotation is not
+// performed at once: each operation evaluates one more cell of the front stream, and evaluated cells
+// are memoized and shared by all queues built from them. So the work is never repeated, even if
+// the same queue is modified many times.
+//
+// Complexity of Snoc, Head and Tail is O(1) in the worst case.
+type RealTimeQueue[T any] struct {
+	front *lazyStream[T]
+	// rear stores the end of queue in reversed order, so its head is the last value of queue.
+	rear RandomAccessList[T]
+	// schedule is the part of front, that is not evaluated yet. Its length is len(front) - len(rear).
+	schedule *lazyStream[T]
+	size     int
+}
+
+// lazyStream is a list, which cells are evaluated on the first access. Nil lazyStream is empty.
+// Evaluation is guarded by sync.Once, so streams can be shared by queues used from different goroutines.
+type lazyStream[T any] struct {
+	once    sync.Once
+	suspend func() *streamCell[T]
+	// cell is nil, if the stream is empty.
+	cell *streamCell[T]
+}
+
+type streamCell[T any] struct {
+	head T
+	tail *lazyStream[T]
+}
+
+// Len returns the amount of values in RealTimeQueue.
+func (q RealTimeQueue[T]) Len() int {
+	return q.size
+}
+
+// Snoc returns new RealTimeQueue with value added to the end.
+func (q RealTimeQueue[T]) Snoc(value T) RealTimeQueue[T] {
+	return q.exec(q.front, q.rear.Cons(value), q.size+1)
+}
+
+// Head returns the first value of RealTimeQueue. If the queue is empty, false is returned.
+func (q RealTimeQueue[T]) Head() (T, bool) {
+	cell := q.front.force()
+	if cell == nil {
+		return *new(T), false
+	}
+
+	return cell.head, true
+}
+
+// Tail returns RealTimeQueue without the first value. Tail of empty queue is empty queue.
+func (q RealTimeQueue[T]) Tail() RealTimeQueue[T] {
+	cell := q.front.force()
+	if cell == nil {
+		return q
+	}
+
+	return q.exec(cell.tail, q.rear, q.size-1)
+}
+
+// Values returns all values of RealTimeQueue starting from the head.
+//
+// Complexity: O(n).
+func (q RealTimeQueue[T]) Values() []T {
+	values := make([]T, 0, q.size)
+	for cell := q.front.force(); cell != nil; cell = cell.tail.force() {
+		values = append(values, cell.head)
+	}
+
+	rearValues := q.rear.Values()
+	for i := len(rearValues) - 1; i >= 0; i-- {
+		values = append(values, rearValues[i])
+	}
+
+	return values
+}
+
+// exec evaluates one cell of the schedule. If the schedule is over, the rear list becomes as long
+// as the front stream, so the rotation of them is started.
+func (q RealTimeQueue[T]) exec(front *lazyStream[T], rear RandomAccessList[T], size int) RealTimeQueue[T] {
+	if cell := q.schedule.force(); cell != nil {
+		return RealTimeQueue[T]{front: front, rear: rear, schedule: cell.tail, size: size}
+	}
+
+	front = rotate(front, rear, nil)
+
+	return RealTimeQueue[T]{front: front, schedule: front, size: size}
+}
+
+// rotate returns stream with values of front, reversed rear and acc. Rear must be longer than front by one.
+// Each cell of returned stream is evaluated in O(1), if the same cell of front is already evaluated.
+func rotate[T any](front *lazyStream[T], rear RandomAccessList[T], acc *lazyStream[T]) *lazyStream[T] {
+	return &lazyStream[T]{
+		suspend: func() *streamCell[T] {
+			last, _ := rear.Head()
+
+			cell := front.force()
+			if cell == nil {
+				return &streamCell[T]{head: last, tail: acc}
+			}
+
+			reversed := evaluatedStream(&streamCell[T]{head: last, tail: acc})
+
+			return &streamCell[T]{head: cell.head, tail: rotate(cell.tail, rear.Tail(), reversed)}
+		},
+	}
+}
+
+func evaluatedStream[T any](cell *streamCell[T]) *lazyStream[T] {
+	s := &lazyStream[T]{}
+	s.once.Do(func() {
+		s.cell = cell
+	})
+
+	return s
+}
+
+// force evaluates the first cell of stream and returns it.
+func (s *lazyStream[T]) force() *streamCell[T] {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		s.cell = s.suspend()
+		s.suspend = nil
+	})
+
+	return s.cell
+}
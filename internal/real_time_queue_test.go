@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRealTimeQueue_SnocAndTail(t *testing.T) {
+	q := RealTimeQueue[int]{}
+	for i := 0; i < 100; i++ {
+		q = q.Snoc(i)
+	}
+
+	if q.Len() != 100 {
+		t.Fatalf("Expected len 100, got: %d", q.Len())
+	}
+	for i := 0; i < 100; i++ {
+		head, ok := q.Head()
+		if !ok || head != i {
+			t.Fatalf("Expected head %d, got: %d, %v", i, head, ok)
+		}
+		q = q.Tail()
+		if q.Len() != 99-i {
+			t.Fatalf("Expected len %d, got: %d", 99-i, q.Len())
+		}
+	}
+
+	if _, ok := q.Head(); ok {
+		t.Error("Expected empty queue")
+	}
+	if q.Tail().Len() != 0 {
+		t.Error("Expected tail of empty queue to be empty")
+	}
+}
+
+func TestRealTimeQueue_Interleaved(t *testing.T) {
+	q := RealTimeQueue[int]{}
+	expected := make([]int, 0)
+	for i := 0; i < 200; i++ {
+		q = q.Snoc(i)
+		expected = append(expected, i)
+		if i%3 == 0 {
+			q = q.Tail()
+			expected = expected[1:]
+		}
+
+		if !slices.Equal(q.Values(), expected) {
+			t.Fatalf("Expected %v, got: %v", expected, q.Values())
+		}
+	}
+}
+
+func TestRealTimeQueue_Persistence(t *testing.T) {
+	q := RealTimeQueue[int]{}
+	for i := 0; i < 8; i++ {
+		q = q.Snoc(i)
+	}
+
+	withEight := q.Snoc(8)
+	withNine := q.Snoc(9)
+	withoutZero := q.Tail()
+
+	if !slices.Equal(q.Values(), []int{0, 1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("Expected old queue to stay the same, got: %v", q.Values())
+	}
+	if !slices.Equal(withEight.Values(), []int{0, 1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("Expected [0 1 2 3 4 5 6 7 8], got: %v", withEight.Values())
+	}
+	if !slices.Equal(withNine.Values(), []int{0, 1, 2, 3, 4, 5, 6, 7, 9}) {
+		t.Errorf("Expected [0 1 2 3 4 5 6 7 9], got: %v", withNine.Values())
+	}
+	if !slices.Equal(withoutZero.Values(), []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("Expected [1 2 3 4 5 6 7], got: %v", withoutZero.Values())
+	}
+}
+
+func TestRealTimeQueue_ConcurrentTail(t *testing.T) {
+	q := RealTimeQueue[int]{}
+	for i := 0; i < 1000; i++ {
+		q = q.Snoc(i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			local := q
+			for i := 0; i < 1000; i++ {
+				if head, _ := local.Head(); head != i {
+					t.Errorf("Expected head %d, got: %d", i, head)
+					return
+				}
+				local = local.Tail()
+			}
+		}()
+	}
+	wg.Wait()
+}
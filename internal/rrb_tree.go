@@ -0,0 +1,374 @@
+package internal
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+)
+
+const (
+	rrbBitsPerLevel = 5
+	rrbBranching    = 1 << rrbBitsPerLevel
+	// rrbExtraNodes is the amount of nodes above the optimal one, that is allowed on each level after concatenation.
+	rrbExtraNodes = 2
+)
+
+// RRBTree is an immutable relaxed radix balanced tree, that represents a sequence of values.
+// Every operation returns new tree and never changes the old one: only nodes on the paths
+// to the changed positions are copied, all other nodes are shared.
+//
+// Nodes, that are built only by appending, are strict: all their children, except the last one, are full,
+// so the child is found by the radix of index. After Prepend, Concat or Slice some nodes become relaxed
+// and keep cumulative sizes of children.
+//
+// Complexity of Get, Set, Append, Prepend, Concat and Slice is O(log32(n)).
+type RRBTree[T any] struct {
+	root *rrbNode[T]
+}
+
+// rrbNode is a leaf with values if height is 0, otherwise it is an inner node with children of height-1.
+type rrbNode[T any] struct {
+	height   int
+	size     int
+	values   []T
+	children []*rrbNode[T]
+	// sizes are cumulative sizes of children. They are nil for strict nodes.
+	sizes []int
+}
+
+// NewRRBTree creates RRBTree with given values.
+//
+// Complexity: O(n).
+func NewRRBTree[T any](values []T) RRBTree[T] {
+	if len(values) == 0 {
+		return RRBTree[T]{}
+	}
+
+	values = slices.Clone(values)
+	nodes := make([]*rrbNode[T], 0, (len(values)+rrbBranching-1)/rrbBranching)
+	for start := 0; start < len(values); start += rrbBranching {
+		end := min(start+rrbBranching, len(values))
+		nodes = append(nodes, newRRBLeaf(values[start:end:end]))
+	}
+
+	for height := 1; len(nodes) > 1; height++ {
+		parents := make([]*rrbNode[T], 0, (len(nodes)+rrbBranching-1)/rrbBranching)
+		for start := 0; start < len(nodes); start += rrbBranching {
+			end := min(start+rrbBranching, len(nodes))
+			parents = append(parents, newRRBInner(height, nodes[start:end:end]))
+		}
+		nodes = parents
+	}
+
+	return RRBTree[T]{root: nodes[0]}
+}
+
+// Len returns the amount of values in RRBTree.
+func (t RRBTree[T]) Len() int {
+	if t.root == nil {
+		return 0
+	}
+
+	return t.root.size
+}
+
+// Get returns value by index. If index is out of range, false is returned.
+func (t RRBTree[T]) Get(index int) (T, bool) {
+	if index < 0 || index >= t.Len() {
+		return *new(T), false
+	}
+
+	node := t.root
+	for node.height > 0 {
+		var i int
+		i, index = node.childIndex(index)
+		node = node.children[i]
+	}
+
+	return node.values[index], true
+}
+
+// Set returns the tree with value replaced by index. If index is out of range, false is returned.
+func (t RRBTree[T]) Set(index int, val T) (RRBTree[T], bool) {
+	if index < 0 || index >= t.Len() {
+		return t, false
+	}
+
+	return RRBTree[T]{root: t.root.set(index, val)}, true
+}
+
+// Append returns the tree with value added to the end.
+func (t RRBTree[T]) Append(val T) RRBTree[T] {
+	return t.Concat(RRBTree[T]{root: newRRBLeaf([]T{val})})
+}
+
+// Prepend returns the tree with value added to the beginning.
+func (t RRBTree[T]) Prepend(val T) RRBTree[T] {
+	return RRBTree[T]{root: newRRBLeaf([]T{val})}.Concat(t)
+}
+
+// Concat returns the tree with values of t followed by values of other.
+func (t RRBTree[T]) Concat(other RRBTree[T]) RRBTree[T] {
+	switch {
+	case t.root == nil:
+		return other
+	case other.root == nil:
+		return t
+	}
+
+	return RRBTree[T]{root: collapseRRB(concatRRB(t.root, other.root))}
+}
+
+// Slice returns the tree with values with indexes from start (inclusive) to end (not inclusive).
+// If indexes are out of range, false is returned.
+func (t RRBTree[T]) Slice(start, end int) (RRBTree[T], bool) {
+	if start < 0 || start > end || end > t.Len() {
+		return t, false
+	}
+	if start == end {
+		return RRBTree[T]{}, true
+	}
+
+	return RRBTree[T]{root: collapseRRB(t.root.takeLeft(end).dropLeft(start))}, true
+}
+
+// Values returns all values of RRBTree in order.
+func (t RRBTree[T]) Values() []T {
+	values := make([]T, 0, t.Len())
+	if t.root != nil {
+		values = t.root.appendValues(values)
+	}
+
+	return values
+}
+
+func newRRBLeaf[T any](values []T) *rrbNode[T] {
+	return &rrbNode[T]{
+		size:   len(values),
+		values: values,
+	}
+}
+
+func newRRBInner[T any](height int, children []*rrbNode[T]) *rrbNode[T] {
+	node := &rrbNode[T]{
+		height:   height,
+		children: children,
+	}
+
+	fullChildSize := rrbFullSize(height)
+	strict := true
+	sizes := make([]int, len(children))
+	for i, child := range children {
+		node.size += child.size
+		sizes[i] = node.size
+
+		if !child.isStrict() || (i < len(children)-1 && child.size != fullChildSize) {
+			strict = false
+		}
+	}
+
+	if !strict {
+		node.sizes = sizes
+	}
+
+	return node
+}
+
+// rrbFullSize returns the size of full node with given height-1.
+func rrbFullSize(height int) int {
+	shift := rrbBitsPerLevel * height
+	if shift >= bits.UintSize-1 {
+		return math.MaxInt
+	}
+
+	return 1 << shift
+}
+
+func (n *rrbNode[T]) isStrict() bool {
+	return n.height == 0 || n.sizes == nil
+}
+
+// slots returns the amount of values in leaf or the amount of children in inner node.
+func (n *rrbNode[T]) slots() int {
+	if n.height == 0 {
+		return len(n.values)
+	}
+
+	return len(n.children)
+}
+
+// childIndex returns the index of child, that contains value by given index, and index of the value in that child.
+func (n *rrbNode[T]) childIndex(index int) (int, int) {
+	shift := rrbBitsPerLevel * n.height
+	// Each child has at most 32^height values, so the child can't be before the radix one.
+	i := index >> shift
+	if n.sizes == nil {
+		return i, index - i<<shift
+	}
+
+	for n.sizes[i] <= index {
+		i++
+	}
+	if i > 0 {
+		index -= n.sizes[i-1]
+	}
+
+	return i, index
+}
+
+func (n *rrbNode[T]) set(index int, val T) *rrbNode[T] {
+	copied := *n
+	if n.height == 0 {
+		copied.values = slices.Clone(n.values)
+		copied.values[index] = val
+		return &copied
+	}
+
+	i, childIndex := n.childIndex(index)
+	copied.children = slices.Clone(n.children)
+	copied.children[i] = n.children[i].set(childIndex, val)
+
+	return &copied
+}
+
+// takeLeft returns the node with first count values, count must be positive.
+func (n *rrbNode[T]) takeLeft(count int) *rrbNode[T] {
+	if count == n.size {
+		return n
+	}
+	if n.height == 0 {
+		return newRRBLeaf(n.values[:count:count])
+	}
+
+	i, childIndex := n.childIndex(count - 1)
+	children := make([]*rrbNode[T], 0, i+1)
+	children = append(children, n.children[:i]...)
+	children = append(children, n.children[i].takeLeft(childIndex+1))
+
+	return newRRBInner(n.height, children)
+}
+
+// dropLeft returns the node without first count values, count must be less than size of the node.
+func (n *rrbNode[T]) dropLeft(count int) *rrbNode[T] {
+	if count == 0 {
+		return n
+	}
+	if n.height == 0 {
+		return newRRBLeaf(n.values[count:])
+	}
+
+	i, childIndex := n.childIndex(count)
+	children := make([]*rrbNode[T], 0, len(n.children)-i)
+	children = append(children, n.children[i].dropLeft(childIndex))
+	children = append(children, n.children[i+1:]...)
+
+	return newRRBInner(n.height, children)
+}
+
+func (n *rrbNode[T]) appendValues(dst []T) []T {
+	if n.height == 0 {
+		return append(dst, n.values...)
+	}
+
+	for _, child := range n.children {
+		dst = child.appendValues(dst)
+	}
+
+	return dst
+}
+
+// collapseRRB removes inner nodes with single child from the top of the tree.
+func collapseRRB[T any](root *rrbNode[T]) *rrbNode[T] {
+	for root.height > 0 && len(root.children) == 1 {
+		root = root.children[0]
+	}
+
+	return root
+}
+
+// concatRRB concatenates two trees. Returned node is higher by one than the highest of trees.
+func concatRRB[T any](left, right *rrbNode[T]) *rrbNode[T] {
+	switch {
+	case left.height > right.height:
+		last := len(left.children) - 1
+		return rebalanceRRB(left.children[:last], concatRRB(left.children[last], right), nil)
+	case left.height < right.height:
+		return rebalanceRRB(nil, concatRRB(left, right.children[0]), right.children[1:])
+	case left.height == 0:
+		if left.size+right.size <= rrbBranching {
+			values := make([]T, 0, left.size+right.size)
+			values = append(append(values, left.values...), right.values...)
+			return newRRBInner(1, []*rrbNode[T]{newRRBLeaf(values)})
+		}
+		return newRRBInner(1, []*rrbNode[T]{left, right})
+	default:
+		last := len(left.children) - 1
+		return rebalanceRRB(left.children[:last], concatRRB(left.children[last], right.children[0]), right.children[1:])
+	}
+}
+
+// rebalanceRRB joins children of left, middle and right nodes, merges them if there are too many underfull ones
+// and returns node higher by one than the middle node.
+func rebalanceRRB[T any](left []*rrbNode[T], middle *rrbNode[T], right []*rrbNode[T]) *rrbNode[T] {
+	nodes := make([]*rrbNode[T], 0, len(left)+len(middle.children)+len(right))
+	nodes = append(nodes, left...)
+	nodes = append(nodes, middle.children...)
+	nodes = append(nodes, right...)
+	nodes = repackRRB(nodes)
+
+	height := middle.height
+	if len(nodes) <= rrbBranching {
+		return newRRBInner(height+1, []*rrbNode[T]{newRRBInner(height, nodes)})
+	}
+
+	return newRRBInner(height+1, []*rrbNode[T]{
+		newRRBInner(height, nodes[:rrbBranching:rrbBranching]),
+		newRRBInner(height, nodes[rrbBranching:]),
+	})
+}
+
+// repackRRB keeps nodes as is, if their amount is close to optimal. Otherwise, nodes starting from the first
+// underfull one are repacked into full nodes.
+func repackRRB[T any](nodes []*rrbNode[T]) []*rrbNode[T] {
+	total := 0
+	for _, node := range nodes {
+		total += node.slots()
+	}
+
+	optimal := (total + rrbBranching - 1) / rrbBranching
+	if len(nodes) <= optimal+rrbExtraNodes {
+		return nodes
+	}
+
+	// There is always underfull node, because otherwise the amount of nodes is close to optimal.
+	first := 0
+	for nodes[first].slots() >= rrbBranching-1 {
+		first++
+	}
+
+	repacked := slices.Clip(nodes[:first])
+	height := nodes[0].height
+	if height == 0 {
+		values := make([]T, 0, total)
+		for _, node := range nodes[first:] {
+			values = append(values, node.values...)
+		}
+		for start := 0; start < len(values); start += rrbBranching {
+			end := min(start+rrbBranching, len(values))
+			repacked = append(repacked, newRRBLeaf(values[start:end:end]))
+		}
+
+		return repacked
+	}
+
+	children := make([]*rrbNode[T], 0, total)
+	for _, node := range nodes[first:] {
+		children = append(children, node.children...)
+	}
+	for start := 0; start < len(children); start += rrbBranching {
+		end := min(start+rrbBranching, len(children))
+		repacked = append(repacked, newRRBInner(height, children[start:end:end]))
+	}
+
+	return repacked
+}
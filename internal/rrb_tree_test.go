@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkRRBTree checks sizes, heights and strictness of all nodes.
+func checkRRBTree[T any](t *testing.T, node *rrbNode[T]) {
+	t.Helper()
+
+	if node == nil || node.height == 0 {
+		if node != nil && (len(node.values) == 0 || len(node.values) > rrbBranching) {
+			t.Fatalf("Leaf has %d values", len(node.values))
+		}
+		return
+	}
+
+	if len(node.children) == 0 || len(node.children) > rrbBranching {
+		t.Fatalf("Inner node has %d children", len(node.children))
+	}
+
+	size := 0
+	for i, child := range node.children {
+		if child.height != node.height-1 {
+			t.Fatalf("Expected child height %d, got: %d", node.height-1, child.height)
+		}
+		checkRRBTree(t, child)
+
+		size += child.size
+		if node.sizes != nil && node.sizes[i] != size {
+			t.Fatalf("Expected cumulative size %d, got: %d", size, node.sizes[i])
+		}
+		if node.sizes == nil && (!child.isStrict() || i < len(node.children)-1 && child.size != rrbFullSize(node.height)) {
+			t.Fatal("Strict node has relaxed or not full child")
+		}
+	}
+
+	if size != node.size {
+		t.Fatalf("Expected size %d, got: %d", size, node.size)
+	}
+}
+
+func rrbShouldBe(t *testing.T, tree RRBTree[int], expected []int) {
+	t.Helper()
+
+	checkRRBTree(t, tree.root)
+	if tree.Len() != len(expected) {
+		t.Fatalf("Expected len %d, got: %d", len(expected), tree.Len())
+	}
+	if !slices.Equal(tree.Values(), expected) {
+		t.Fatalf("Expected %v, got: %v", expected, tree.Values())
+	}
+	for i, val := range expected {
+		got, ok := tree.Get(i)
+		if !ok || got != val {
+			t.Fatalf("Expected %d by index %d, got: %d, %v", val, i, got, ok)
+		}
+	}
+}
+
+func TestRRBTree_AppendAndGet(t *testing.T) {
+	var (
+		tree     RRBTree[int]
+		expected []int
+	)
+	for i := 0; i < 2000; i++ {
+		tree = tree.Append(i)
+		expected = append(expected, i)
+	}
+
+	rrbShouldBe(t, tree, expected)
+	if !tree.root.isStrict() {
+		t.Error("Expected tree built by appending to be strict")
+	}
+	if _, ok := tree.Get(2000); ok {
+		t.Error("Expected no value by index 2000")
+	}
+
+	rrbShouldBe(t, NewRRBTree(expected), expected)
+}
+
+func TestRRBTree_Persistence(t *testing.T) {
+	tree := NewRRBTree([]int{1, 2, 3})
+
+	changed, ok := tree.Set(1, 20)
+	if !ok {
+		t.Fatal("Expected Set to succeed")
+	}
+	prepended := tree.Prepend(0)
+	sliced, ok := tree.Slice(1, 3)
+	if !ok {
+		t.Fatal("Expected Slice to succeed")
+	}
+
+	rrbShouldBe(t, tree, []int{1, 2, 3})
+	rrbShouldBe(t, changed, []int{1, 20, 3})
+	rrbShouldBe(t, prepended, []int{0, 1, 2, 3})
+	rrbShouldBe(t, sliced, []int{2, 3})
+	rrbShouldBe(t, tree.Concat(sliced), []int{1, 2, 3, 2, 3})
+
+	if _, ok = tree.Slice(2, 4); ok {
+		t.Error("Expected Slice out of range to fail")
+	}
+	if _, ok = tree.Set(3, 0); ok {
+		t.Error("Expected Set out of range to fail")
+	}
+}
+
+func TestRRBTree_RandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	trees := []RRBTree[int]{{}}
+	models := [][]int{nil}
+	for i := 0; i < 3000; i++ {
+		from := r.Intn(len(trees))
+		tree, model := trees[from], slices.Clone(models[from])
+
+		switch r.Intn(6) {
+		case 0:
+			tree = tree.Append(i)
+			model = append(model, i)
+		case 1:
+			tree = tree.Prepend(i)
+			model = append([]int{i}, model...)
+		case 2:
+			other := r.Intn(len(trees))
+			tree = tree.Concat(trees[other])
+			model = append(model, models[other]...)
+		case 3:
+			start := r.Intn(len(model) + 1)
+			end := start + r.Intn(len(model)-start+1)
+			tree, _ = tree.Slice(start, end)
+			model = model[start:end]
+		case 4:
+			if len(model) == 0 {
+				continue
+			}
+			index := r.Intn(len(model))
+			tree, _ = tree.Set(index, i)
+			model[index] = i
+		case 5:
+			tree = NewRRBTree(model).Concat(tree)
+			model = append(slices.Clone(model), model...)
+		}
+
+		if len(model) > 100000 {
+			continue
+		}
+		rrbShouldBe(t, tree, model)
+		trees = append(trees, tree)
+		models = append(models, model)
+	}
+}
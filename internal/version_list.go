@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"math"
+	"slices"
+)
+
+const (
+	// versionListLabelBits is the amount of bits used by labels of versionList.
+	versionListLabelBits = 62
+	// versionListDensity is the T parameter of order maintenance: range of 2^i labels is relabeled
+	// only if it contains at most (2/T)^i items.
+	versionListDensity = 1.25
+)
+
+// versionList is an ordered list, that supports insertion and comparison of items in amortised O(log(n)).
+// Each item has a label, and order of labels is the same as order of items. If there is no free label
+// for inserted item, the smallest enclosing range of labels, that is sparse enough, is relabeled evenly
+// (Bender et al. "Two simplified algorithms for maintaining order in a list").
+type versionList struct {
+	head *versionListItem
+}
+
+type versionListItem struct {
+	label uint64
+	prev  *versionListItem
+	next  *versionListItem
+}
+
+func newVersionList() *versionList {
+	return &versionList{
+		head: &versionListItem{},
+	}
+}
+
+// less reports whether item a is before item b.
+func (a *versionListItem) less(b *versionListItem) bool {
+	return a.label < b.label
+}
+
+// insertAfter inserts new item right after given one.
+func (l *versionList) insertAfter(prev *versionListItem) *versionListItem {
+	item := &versionListItem{
+		prev: prev,
+		next: prev.next,
+	}
+	if prev.next != nil {
+		prev.next.prev = item
+	}
+	prev.next = item
+
+	upper := uint64(1) << versionListLabelBits
+	if item.next != nil {
+		upper = item.next.label
+	}
+
+	if upper-prev.label > 1 {
+		item.label = prev.label + (upper-prev.label)/2
+		return item
+	}
+
+	l.relabel(item)
+
+	return item
+}
+
+// relabel spreads labels of items around given item, which label is not set yet.
+func (l *versionList) relabel(item *versionListItem) {
+	anchor := item.prev.label
+
+	for rangeBits := 1; ; rangeBits++ {
+		low := anchor &^ (uint64(1)<<rangeBits - 1)
+		high := low + uint64(1)<<rangeBits
+
+		first, last, count := item.prev, item, 2
+		for first.prev != nil && first.prev.label >= low {
+			first = first.prev
+			count++
+		}
+		for last.next != nil && last.next.label < high {
+			last = last.next
+			count++
+		}
+
+		if float64(count) > math.Pow(2/versionListDensity, float64(rangeBits)) && rangeBits < versionListLabelBits {
+			continue
+		}
+
+		step := (high - low) / uint64(count)
+		label := low
+		for node := first; ; node = node.next {
+			node.label = label
+			label += step
+			if node == last {
+				return
+			}
+		}
+	}
+}
+
+// VersionListStore is a VersionStore, that additionally keeps versions in version list: preorder of the
+// version tree, in which each version is represented by two items, the beginning and the end of its subtree.
+// Version a is an ancestor of version b if and only if b is between the beginning and the end of a.
+// VersionListStore is used by VersionListCell to find visible values without walking the history.
+type VersionListStore struct {
+	list           *versionList
+	parents        []uint64
+	begins         []*versionListItem
+	ends           []*versionListItem
+	versionMachine *VersionMachine
+}
+
+// NewVersionListStore creates VersionListStore with only root version.
+func NewVersionListStore() *VersionListStore {
+	vm := &VersionMachine{
+		version: 0,
+	}
+
+	root, _ := vm.GetAndIncrementVersion()
+	list := newVersionList()
+
+	return &VersionListStore{
+		list:           list,
+		parents:        []uint64{root},
+		begins:         []*versionListItem{list.head},
+		ends:           []*versionListItem{list.insertAfter(list.head)},
+		versionMachine: vm,
+	}
+}
+
+// Create creates given version as a child of parent version. The subtree of new version is placed
+// right before the end of the parent subtree.
+func (s *VersionListStore) Create(parent, version uint64) error {
+	if parent >= uint64(len(s.parents)) {
+		return ErrVersionNotFound
+	}
+	if version != uint64(len(s.parents)) {
+		return ErrVersionNotSequential
+	}
+
+	if _, err := s.versionMachine.GetAndIncrementVersion(); err != nil {
+		return err
+	}
+
+	begin := s.list.insertAfter(s.ends[parent].prev)
+	s.parents = append(s.parents, parent)
+	s.begins = append(s.begins, begin)
+	s.ends = append(s.ends, s.list.insertAfter(begin))
+
+	return nil
+}
+
+// Parent returns the parent of version.
+func (s *VersionListStore) Parent(version uint64) (uint64, bool) {
+	if version == 0 || version >= uint64(len(s.parents)) {
+		return 0, false
+	}
+
+	return s.parents[version], true
+}
+
+// History returns versions on the path from the root to given version.
+func (s *VersionListStore) History(version uint64) ([]uint64, error) {
+	if version >= uint64(len(s.parents)) {
+		return nil, ErrVersionNotFound
+	}
+
+	history := []uint64{version}
+	for version != 0 {
+		version = s.parents[version]
+		history = append(history, version)
+	}
+
+	slices.Reverse(history)
+
+	return history, nil
+}
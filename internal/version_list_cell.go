@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"sort"
+)
+
+// versionListCellSlots is the maximal amount of modifications stored in a single node of VersionListCell.
+const versionListCellSlots = 16
+
+// VisibleCell is a Cell, that finds value visible for version, that is the value written for the nearest
+// ancestor of version, itself, without walking the history of version.
+type VisibleCell interface {
+	Cell
+	// ReadVisible returns value visible for version. If no ancestor of version has written value, false is returned.
+	ReadVisible(version uint64) (any, bool)
+}
+
+// VersionListCell is a VisibleCell, that keeps modifications ordered by the version list of VersionListStore.
+//
+// Writing value for version v also writes
+// the previous visible value for the end of v's subtree, so that the value of any version is the value of
+// the closest modification before it in version list. Modifications are stored in sorted nodes with bounded
+// amount of slots: when node overflows, it is split into two half-full nodes.
+//
+// Read and ReadVisible take O(log(m)), where m - amount of modifications, regardless of the depth of version.
+type VersionListCell struct {
+	store *VersionListStore
+	nodes []*versionListCellNode
+}
+
+type versionListCellNode struct {
+	slots []versionListCellSlot
+}
+
+type versionListCellSlot struct {
+	item  *versionListItem
+	value any
+	// absent marks the end of subtree of the version, that has written the first value.
+	absent bool
+}
+
+// NewVersionListCell creates new VersionListCell with value written for given version of store.
+func NewVersionListCell(store *VersionListStore, version uint64, value any) *VersionListCell {
+	c := &VersionListCell{
+		store: store,
+	}
+	c.Write(version, value)
+
+	return c
+}
+
+// Read returns value, that was written exactly for given version.
+func (c *VersionListCell) Read(version uint64) (any, bool) {
+	if version >= uint64(len(c.store.begins)) {
+		return nil, false
+	}
+
+	begin := c.store.begins[version]
+	slot, found := c.find(begin)
+	if !found || slot.item != begin {
+		return nil, false
+	}
+
+	return slot.value, true
+}
+
+// ReadVisible returns value visible for version.
+func (c *VersionListCell) ReadVisible(version uint64) (any, bool) {
+	if version >= uint64(len(c.store.begins)) {
+		return nil, false
+	}
+
+	slot, found := c.find(c.store.begins[version])
+	if !found || slot.absent {
+		return nil, false
+	}
+
+	return slot.value, true
+}
+
+// Write writes value for given version.
+func (c *VersionListCell) Write(version uint64, value any) {
+	begin, end := c.store.begins[version], c.store.ends[version]
+
+	prev, found := c.find(begin)
+	if found && prev.item == begin {
+		c.set(begin, versionListCellSlot{item: begin, value: value})
+		return
+	}
+
+	if next, ok := c.find(end); !ok || next.item != end {
+		c.set(end, versionListCellSlot{item: end, value: prev.value, absent: !found || prev.absent})
+	}
+	c.set(begin, versionListCellSlot{item: begin, value: value})
+}
+
+// find returns the last slot, which item is not after given item.
+func (c *VersionListCell) find(item *versionListItem) (versionListCellSlot, bool) {
+	nodeIndex := sort.Search(len(c.nodes), func(i int) bool {
+		return item.less(c.nodes[i].slots[0].item)
+	}) - 1
+	if nodeIndex < 0 {
+		return versionListCellSlot{}, false
+	}
+
+	slots := c.nodes[nodeIndex].slots
+	slotIndex := sort.Search(len(slots), func(i int) bool {
+		return item.less(slots[i].item)
+	}) - 1
+
+	return slots[slotIndex], true
+}
+
+// set writes slot for its item replacing existing one.
+func (c *VersionListCell) set(item *versionListItem, slot versionListCellSlot) {
+	nodeIndex := max(sort.Search(len(c.nodes), func(i int) bool {
+		return item.less(c.nodes[i].slots[0].item)
+	})-1, 0)
+	if len(c.nodes) == 0 {
+		c.nodes = append(c.nodes, &versionListCellNode{})
+	}
+
+	node := c.nodes[nodeIndex]
+	slotIndex := sort.Search(len(node.slots), func(i int) bool {
+		return !node.slots[i].item.less(item)
+	})
+	if slotIndex < len(node.slots) && node.slots[slotIndex].item == item {
+		node.slots[slotIndex] = slot
+		return
+	}
+
+	node.slots = append(node.slots, versionListCellSlot{})
+	copy(node.slots[slotIndex+1:], node.slots[slotIndex:])
+	node.slots[slotIndex] = slot
+
+	if len(node.slots) <= versionListCellSlots {
+		return
+	}
+
+	half := len(node.slots) / 2
+	left := &versionListCellNode{slots: append(make([]versionListCellSlot, 0, versionListCellSlots+1), node.slots[:half]...)}
+	right := &versionListCellNode{slots: append(make([]versionListCellSlot, 0, versionListCellSlots+1), node.slots[half:]...)}
+
+	c.nodes[nodeIndex] = left
+	c.nodes = append(c.nodes, nil)
+	copy(c.nodes[nodeIndex+2:], c.nodes[nodeIndex+1:])
+	c.nodes[nodeIndex+1] = right
+}
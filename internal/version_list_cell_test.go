@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestVersionListCell(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	store := NewVersionListStore()
+	tree := NewVersionTreeWithStore[int](store)
+	fatNode := NewFatNode(0, 1)
+
+	_, _ = tree.Update(0)
+	cell := NewVersionListCell(store, 1, 0)
+
+	for i := 2; i < 3000; i++ {
+		parent := uint64(r.Intn(i))
+		if r.Intn(3) == 0 {
+			parent = uint64(i - 1)
+		}
+
+		version, err := tree.Update(parent)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %s", err)
+		}
+		if r.Intn(3) == 0 {
+			fatNode.Update(i, version)
+			cell.Write(version, i)
+		}
+	}
+
+	for version := uint64(0); version < 3000; version++ {
+		history, _ := tree.GetHistory(version)
+
+		var (
+			expected any
+			found    bool
+		)
+		for i := len(history) - 1; i >= 1 && !found; i-- {
+			expected, _, found = fatNode.FindByVersion(history[i])
+		}
+
+		got, ok := cell.ReadVisible(version)
+		if ok != found || got != expected {
+			t.Fatalf("Expected %v, %v for version %d, got: %v, %v", expected, found, version, got, ok)
+		}
+
+		exact, exactFound := fatNode.Read(version)
+		got, ok = cell.Read(version)
+		if ok != exactFound || got != exact {
+			t.Fatalf("Expected exact %v, %v for version %d, got: %v, %v", exact, exactFound, version, got, ok)
+		}
+	}
+
+	if len(cell.nodes) < 2 {
+		t.Error("Expected cell to be copied on overflow")
+	}
+	for _, node := range cell.nodes {
+		if len(node.slots) > versionListCellSlots {
+			t.Errorf("Node has %d slots", len(node.slots))
+		}
+	}
+}
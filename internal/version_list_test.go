@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestVersionList_InsertKeepsOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	l := newVersionList()
+	items := []*versionListItem{l.head}
+	for i := 0; i < 20000; i++ {
+		// Insert mostly after the same items to force relabeling.
+		index := r.Intn(min(len(items), 3))
+		if r.Intn(10) == 0 {
+			index = r.Intn(len(items))
+		}
+
+		item := l.insertAfter(items[index])
+		items = slices.Insert(items, index+1, item)
+	}
+
+	node := l.head
+	for i, item := range items {
+		if node != item {
+			t.Fatalf("Unexpected item on position %d", i)
+		}
+		if i > 0 && !items[i-1].less(item) {
+			t.Fatalf("Labels are not increasing on position %d: %d, %d", i, items[i-1].label, item.label)
+		}
+		node = node.next
+	}
+}
+
+func TestVersionListStore(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	store := NewVersionListStore()
+	for i := 0; i < 2000; i++ {
+		parent := uint64(r.Intn(i + 1))
+		if r.Intn(2) == 0 {
+			parent = uint64(i)
+		}
+
+		if err := store.Create(parent, uint64(i+1)); err != nil {
+			t.Fatalf("Expected no error, got: %s", err)
+		}
+	}
+
+	// version a is an ancestor of version b if and only if b is inside the subtree of a in version list
+	for i := 0; i < 2000; i++ {
+		a, b := uint64(r.Intn(2001)), uint64(r.Intn(2001))
+
+		history, err := store.History(b)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %s", err)
+		}
+
+		isAncestor := false
+		for _, version := range history {
+			isAncestor = isAncestor || version == a
+		}
+
+		inSubtree := !store.begins[b].less(store.begins[a]) && store.begins[b].less(store.ends[a])
+		if isAncestor != inSubtree {
+			t.Fatalf("Version %d is ancestor of %d: %v, but in subtree: %v", a, b, isAncestor, inSubtree)
+		}
+	}
+}
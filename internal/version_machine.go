@@ -1,20 +1,28 @@
 package internal
 
+import (
+	"errors"
+)
+
+// ErrVersionsExhausted is returned if all versions of uint64 are already given out.
+var ErrVersionsExhausted = errors.New("versions are exhausted")
+
 // VersionMachine is a struct to manage data structure version change.
 type VersionMachine struct {
 	version uint64
 }
 
 // GetAndIncrementVersion returns new version.
-func (vm *VersionMachine) GetAndIncrementVersion() uint64 {
+// If there are no more versions, ErrVersionsExhausted is returned.
+func (vm *VersionMachine) GetAndIncrementVersion() (uint64, error) {
 	curVersion := vm.version
 
 	if vm.version == ^uint64(0) {
-		panic("version overflow")
+		return 0, ErrVersionsExhausted
 	}
 
 	vm.version = vm.version + 1
-	return curVersion
+	return curVersion, nil
 }
 
 // GetVersion returns current version.
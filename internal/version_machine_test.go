@@ -1,12 +1,15 @@
 package internal
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestVersionMachine_GetAndIncrementVersion(t *testing.T) {
 	vm := VersionMachine{
 		version: 0,
 	}
-	vm.GetAndIncrementVersion()
+	_, _ = vm.GetAndIncrementVersion()
 	if vm.version != 1 {
 		t.Error("Version should be 1")
 	}
@@ -16,8 +19,26 @@ func TestVersionMachine_GetVersion(t *testing.T) {
 	vm := VersionMachine{
 		version: 0,
 	}
-	vm.GetAndIncrementVersion()
+	_, _ = vm.GetAndIncrementVersion()
 	if vm.GetVersion() != 0 {
 		t.Error("Version should be 0")
 	}
 }
+
+func TestVersionMachine_Exhausted(t *testing.T) {
+	vm := VersionMachine{
+		version: ^uint64(0) - 1,
+	}
+	version, err := vm.GetAndIncrementVersion()
+	if err != nil || version != ^uint64(0)-1 {
+		t.Errorf("Version should be given out, got %d, %v", version, err)
+	}
+
+	_, err = vm.GetAndIncrementVersion()
+	if !errors.Is(err, ErrVersionsExhausted) {
+		t.Errorf("Error should be %v, got %v", ErrVersionsExhausted, err)
+	}
+	if vm.GetVersion() != ^uint64(0)-1 {
+		t.Error("Version should not change")
+	}
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrVersionNotSequential is returned if VersionStore is asked to create version, that doesn't follow the previous one.
+var ErrVersionNotSequential = errors.New("version store created not sequential version")
+
+// VersionStore stores the tree of versions without any data.
+// The root version is 0, and each created version must be greater by one than the previous created version.
+type VersionStore interface {
+	// Create creates given version as a child of parent version. If version can't be created, for example,
+	// it doesn't follow the previous created version, error is returned and the store stays unchanged.
+	Create(parent, version uint64) error
+	// Parent returns the parent of version. If version is the root or doesn't exist, false is returned.
+	Parent(version uint64) (uint64, bool)
+	// History returns versions on the path from the root to given version.
+	History(version uint64) ([]uint64, error)
+}
+
+// TreeVersionStore is a VersionStore, that keeps versions as tree nodes with links to parent and children.
+type TreeVersionStore struct {
+	tree           []*versionStoreNode
+	versionMachine *VersionMachine
+}
+
+type versionStoreNode struct {
+	version  uint64
+	parent   *versionStoreNode
+	children []*versionStoreNode
+}
+
+// NewTreeVersionStore creates TreeVersionStore with only root version.
+func NewTreeVersionStore() *TreeVersionStore {
+	vm := &VersionMachine{
+		version: 0,
+	}
+
+	root, _ := vm.GetAndIncrementVersion()
+
+	return &TreeVersionStore{
+		tree:           []*versionStoreNode{newVersionStoreNode(root, nil)},
+		versionMachine: vm,
+	}
+}
+
+// Create creates given version as a child of parent version.
+func (s *TreeVersionStore) Create(parent, version uint64) error {
+	node, success := s.findVersion(parent)
+	if !success {
+		return ErrVersionNotFound
+	}
+	if version != uint64(len(s.tree)) {
+		return ErrVersionNotSequential
+	}
+	if _, err := s.versionMachine.GetAndIncrementVersion(); err != nil {
+		return err
+	}
+	newNode := newVersionStoreNode(version, node)
+	node.children = append(node.children, newNode)
+	s.tree = append(s.tree, newNode)
+
+	return nil
+}
+
+// Parent returns the parent of version.
+func (s *TreeVersionStore) Parent(version uint64) (uint64, bool) {
+	node, success := s.findVersion(version)
+	if !success || node.parent == nil {
+		return 0, false
+	}
+
+	return node.parent.version, true
+}
+
+// History returns versions on the path from the root to given version.
+func (s *TreeVersionStore) History(version uint64) ([]uint64, error) {
+	node, success := s.findVersion(version)
+	if !success {
+		return nil, ErrVersionNotFound
+	}
+
+	var history []uint64
+	for node != nil {
+		history = append(history, node.version)
+		node = node.parent
+	}
+	slices.Reverse(history)
+
+	return history, nil
+}
+
+func newVersionStoreNode(v uint64, parent *versionStoreNode) *versionStoreNode {
+	return &versionStoreNode{
+		version:  v,
+		parent:   parent,
+		children: []*versionStoreNode{},
+	}
+}
+
+func (s *TreeVersionStore) findVersion(version uint64) (*versionStoreNode, bool) {
+	if version >= uint64(len(s.tree)) {
+		return nil, false
+	}
+	return s.tree[version], true
+}
+
+// ParentVersionStore is a compact VersionStore, that keeps only the parent of each version.
+type ParentVersionStore struct {
+	parents        []uint64
+	versionMachine *VersionMachine
+}
+
+// NewParentVersionStore creates ParentVersionStore with only root version.
+func NewParentVersionStore() *ParentVersionStore {
+	vm := &VersionMachine{
+		version: 0,
+	}
+
+	root, _ := vm.GetAndIncrementVersion()
+
+	return &ParentVersionStore{
+		parents:        []uint64{root},
+		versionMachine: vm,
+	}
+}
+
+// Create creates given version as a child of parent version.
+func (s *ParentVersionStore) Create(parent, version uint64) error {
+	if parent >= uint64(len(s.parents)) {
+		return ErrVersionNotFound
+	}
+	if version != uint64(len(s.parents)) {
+		return ErrVersionNotSequential
+	}
+	if _, err := s.versionMachine.GetAndIncrementVersion(); err != nil {
+		return err
+	}
+	s.parents = append(s.parents, parent)
+
+	return nil
+}
+
+// Parent returns the parent of version.
+func (s *ParentVersionStore) Parent(version uint64) (uint64, bool) {
+	if version == 0 || version >= uint64(len(s.parents)) {
+		return 0, false
+	}
+
+	return s.parents[version], true
+}
+
+// History returns versions on the path from the root to given version.
+func (s *ParentVersionStore) History(version uint64) ([]uint64, error) {
+	if version >= uint64(len(s.parents)) {
+		return nil, ErrVersionNotFound
+	}
+
+	history := []uint64{version}
+	for version != 0 {
+		version = s.parents[version]
+		history = append(history, version)
+	}
+	slices.Reverse(history)
+
+	return history, nil
+}
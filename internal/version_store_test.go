@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestVersionStores(t *testing.T) {
+	stores := map[string]func() VersionStore{
+		"TreeVersionStore":   func() VersionStore { return NewTreeVersionStore() },
+		"ParentVersionStore": func() VersionStore { return NewParentVersionStore() },
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+
+			for i, parent := range []uint64{0, 1, 1, 3} {
+				if err := store.Create(parent, uint64(i+1)); err != nil {
+					t.Fatalf("Expected no error, got: %s", err)
+				}
+			}
+
+			if err := store.Create(5, 5); err == nil {
+				t.Error("Expected error on unknown parent")
+			}
+			if err := store.Create(0, 6); !errors.Is(err, ErrVersionNotSequential) {
+				t.Errorf("Expected %v, got: %v", ErrVersionNotSequential, err)
+			}
+			if _, ok := store.Parent(5); ok {
+				t.Error("Expected no version 5 after failed creation")
+			}
+
+			history, err := store.History(4)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %s", err)
+			}
+			if !slices.Equal(history, []uint64{0, 1, 3, 4}) {
+				t.Errorf("Expected history [0 1 3 4], got: %v", history)
+			}
+
+			parent, ok := store.Parent(3)
+			if !ok || parent != 1 {
+				t.Errorf("Expected parent 1, got: %d, %v", parent, ok)
+			}
+			if _, ok = store.Parent(0); ok {
+				t.Error("Expected no parent for root version")
+			}
+			if _, ok = store.Parent(5); ok {
+				t.Error("Expected no parent for unknown version")
+			}
+		})
+	}
+}
@@ -2,65 +2,167 @@ package internal
 
 import (
 	"errors"
-	"slices"
 )
 
 // VersionTree is a struct to store object change history.
+// The tree of versions is kept by VersionStore, and VersionTree keeps info for each version.
 type VersionTree[T any] struct {
-	tree           []*versionTreeNode[T]
-	versionMachine *VersionMachine
+	tree   []*versionTreeNode[T]
+	store  VersionStore
+	limits Limits
 }
 
 type versionTreeNode[T any] struct {
 	version     uint64
+	depth       uint64
 	versionInfo T
-	parent      *versionTreeNode[T]
-	children    []*versionTreeNode[T]
 }
 
-// ErrVersionNotFound will be returned if searched version was not found in VersionTree.
-var ErrVersionNotFound = errors.New("version not found")
+var (
+	// ErrVersionNotFound will be returned if searched version was not found in VersionTree.
+	ErrVersionNotFound = errors.New("version not found")
+	// ErrLimitExceeded will be returned if new version exceeds Limits of VersionTree.
+	ErrLimitExceeded = errors.New("version limit exceeded")
+)
 
-// NewVersionTree creates new object change history tree.
-func NewVersionTree[T any]() *VersionTree[T] {
-	vm := &VersionMachine{
-		version: 0,
+// LimitKind is the kind of limit of VersionTree.
+type LimitKind int
+
+const (
+	// LimitVersions limits the amount of versions including the initial one.
+	LimitVersions LimitKind = iota
+	// LimitDepth limits the depth of version, that is the amount of its ancestors.
+	LimitDepth
+)
+
+// String returns name of LimitKind.
+func (k LimitKind) String() string {
+	switch k {
+	case LimitVersions:
+		return "Versions"
+	case LimitDepth:
+		return "Depth"
+	default:
+		return "Unknown"
 	}
+}
+
+// Limits restricts versions, that VersionTree creates. Zero fields mean no limit or no threshold.
+type Limits struct {
+	// MaxVersions is the maximum amount of versions including the initial one.
+	MaxVersions uint64
+	// MaxDepth is the maximum depth of version, the initial version has depth 0.
+	MaxDepth uint64
+	// VersionsThreshold is the amount of versions, on reaching which OnThreshold is called with LimitVersions.
+	VersionsThreshold uint64
+	// DepthThreshold is the depth, on creating version of which OnThreshold is called with LimitDepth.
+	// It is called for each version with such depth, so once for each branch crossing the threshold.
+	DepthThreshold uint64
+	// OnThreshold is called with the kind of crossed threshold and created version. It is called before
+	// the modification is finished, so it must not access the structure.
+	OnThreshold func(kind LimitKind, version uint64)
+}
+
+// NewVersionTree creates new object change history tree, that keeps versions in TreeVersionStore.
+func NewVersionTree[T any]() *VersionTree[T] {
+	return NewVersionTreeWithStore[T](NewTreeVersionStore())
+}
 
+// NewVersionTreeWithStore creates new object change history tree, that keeps versions in given store.
+// The store must contain only the root version.
+func NewVersionTreeWithStore[T any](store VersionStore) *VersionTree[T] {
 	return &VersionTree[T]{
-		tree:           []*versionTreeNode[T]{newVersionTreeNode[T](vm.GetAndIncrementVersion(), nil)},
-		versionMachine: vm,
+		tree:  []*versionTreeNode[T]{newVersionTreeNode[T](0)},
+		store: store,
 	}
 }
 
 // Update creates new version for specified version.
+// If new version exceeds Limits, ErrLimitExceeded is returned.
 func (vt *VersionTree[T]) Update(prevVersion uint64) (uint64, error) {
-	node, success := vt.findVersion(prevVersion)
+	prevNode, success := vt.findVersion(prevVersion)
 	if !success {
 		return 0, ErrVersionNotFound
 	}
-	newNode := newVersionTreeNode(vt.versionMachine.GetAndIncrementVersion(), node)
-	node.children = append(node.children, newNode)
-	vt.tree = append(vt.tree, newNode)
 
-	return vt.versionMachine.GetVersion(), nil
+	depth := prevNode.depth + 1
+	if vt.limits.MaxVersions != 0 && uint64(len(vt.tree)) >= vt.limits.MaxVersions {
+		return 0, ErrLimitExceeded
+	}
+	if vt.limits.MaxDepth != 0 && depth > vt.limits.MaxDepth {
+		return 0, ErrLimitExceeded
+	}
+
+	newVersion := uint64(len(vt.tree))
+	if err := vt.store.Create(prevVersion, newVersion); err != nil {
+		return 0, err
+	}
+
+	node := newVersionTreeNode[T](newVersion)
+	node.depth = depth
+	vt.tree = append(vt.tree, node)
+
+	vt.crossThresholds(newVersion, depth)
+
+	return newVersion, nil
 }
 
-// GetHistory returns change history for specified object's version.
-func (vt *VersionTree[T]) GetHistory(version uint64) ([]uint64, error) {
+func (vt *VersionTree[T]) crossThresholds(version, depth uint64) {
+	if vt.limits.OnThreshold == nil {
+		return
+	}
+
+	if vt.limits.VersionsThreshold != 0 && uint64(len(vt.tree)) == vt.limits.VersionsThreshold {
+		vt.limits.OnThreshold(LimitVersions, version)
+	}
+	if vt.limits.DepthThreshold != 0 && depth == vt.limits.DepthThreshold {
+		vt.limits.OnThreshold(LimitDepth, version)
+	}
+}
+
+// SetLimits sets limits for new versions. Existing versions are kept even if they exceed limits.
+func (vt *VersionTree[T]) SetLimits(limits Limits) {
+	vt.limits = limits
+}
+
+// Depth returns the amount of ancestors of version.
+func (vt *VersionTree[T]) Depth(version uint64) (uint64, error) {
 	node, success := vt.findVersion(version)
 	if !success {
-		return nil, ErrVersionNotFound
+		return 0, ErrVersionNotFound
+	}
+
+	return node.depth, nil
+}
+
+// Store returns VersionStore, that keeps the tree of versions.
+func (vt *VersionTree[T]) Store() VersionStore {
+	return vt.store
+}
+
+// Parent returns the version, which given version was created from.
+// If version is the root or doesn't exist, false is returned.
+func (vt *VersionTree[T]) Parent(version uint64) (uint64, bool) {
+	if _, success := vt.findVersion(version); !success {
+		return 0, false
 	}
 
-	var history []uint64
-	for node != nil {
-		history = append(history, node.version)
-		node = node.parent
+	return vt.store.Parent(version)
+}
+
+// LastVersion returns the last created version.
+func (vt *VersionTree[T]) LastVersion() uint64 {
+	return uint64(len(vt.tree) - 1)
+}
+
+// GetHistory returns change history for specified object's version.
+func (vt *VersionTree[T]) GetHistory(version uint64) ([]uint64, error) {
+	_, success := vt.findVersion(version)
+	if !success {
+		return nil, ErrVersionNotFound
 	}
-	slices.Reverse(history)
 
-	return history, nil
+	return vt.store.History(version)
 }
 
 // GetVersionInfo returns info for specified version.
@@ -82,11 +184,9 @@ func (vt *VersionTree[T]) SetVersionInfo(version uint64, info T) error {
 	return nil
 }
 
-func newVersionTreeNode[T any](v uint64, parent *versionTreeNode[T]) *versionTreeNode[T] {
+func newVersionTreeNode[T any](v uint64) *versionTreeNode[T] {
 	return &versionTreeNode[T]{
-		version:  v,
-		parent:   parent,
-		children: []*versionTreeNode[T]{},
+		version: v,
 	}
 }
 
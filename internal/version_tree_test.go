@@ -1,6 +1,9 @@
 package internal
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestNewVersionTreeCreation(t *testing.T) {
 	vt := NewVersionTree[int]()
@@ -65,6 +68,78 @@ func TestVersionTree_UpdateError(t *testing.T) {
 	}
 }
 
+func TestVersionTree_UpdateStoreError(t *testing.T) {
+	// store is shared with another tree, so it is ahead of the tree.
+	store := NewTreeVersionStore()
+	ahead := NewVersionTreeWithStore[int](store)
+	if _, err := ahead.Update(0); err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+
+	vt := NewVersionTreeWithStore[int](store)
+	if _, err := vt.Update(0); !errors.Is(err, ErrVersionNotSequential) {
+		t.Errorf("Expected %v, got: %v", ErrVersionNotSequential, err)
+	}
+
+	// failed creation doesn't break the other tree.
+	version, err := ahead.Update(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+	if version != 2 {
+		t.Errorf("Expected version 2, got: %d", version)
+	}
+	if parent, ok := store.Parent(2); !ok || parent != 1 {
+		t.Errorf("Expected parent 1, got: %d, %v", parent, ok)
+	}
+}
+
+func TestVersionTree_Limits(t *testing.T) {
+	vt := NewVersionTree[int]()
+
+	var crossed []LimitKind
+	vt.SetLimits(Limits{
+		MaxVersions:       5,
+		MaxDepth:          3,
+		VersionsThreshold: 4,
+		DepthThreshold:    2,
+		OnThreshold: func(kind LimitKind, _ uint64) {
+			crossed = append(crossed, kind)
+		},
+	})
+
+	for version := uint64(0); version < 3; version++ {
+		if _, err := vt.Update(version); err != nil {
+			t.Errorf("Expected no error, got: %s", err)
+		}
+	}
+	if _, err := vt.Update(3); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected error %s, got: %v", ErrLimitExceeded, err)
+	}
+
+	if _, err := vt.Update(1); err != nil {
+		t.Errorf("Expected no error, got: %s", err)
+	}
+	if _, err := vt.Update(0); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Expected error %s, got: %v", ErrLimitExceeded, err)
+	}
+
+	depth, err := vt.Depth(4)
+	if err != nil || depth != 2 {
+		t.Errorf("Expected depth 2, got: %d, %v", depth, err)
+	}
+
+	expected := []LimitKind{LimitDepth, LimitVersions, LimitDepth}
+	if len(crossed) != len(expected) {
+		t.Fatalf("Expected crossed thresholds: %v, got: %v", expected, crossed)
+	}
+	for i := range expected {
+		if crossed[i] != expected[i] {
+			t.Errorf("Expected crossed thresholds: %v, got: %v", expected, crossed)
+		}
+	}
+}
+
 func equalSlices(a, b []uint64) bool {
 	if len(a) != len(b) {
 		return false
@@ -0,0 +1,49 @@
+package go_persistent_ds
+
+import (
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+var (
+	// ErrVersionsExhausted is returned by modifications of structure, which created all possible versions.
+	ErrVersionsExhausted = internal.ErrVersionsExhausted
+	// ErrLimitExceeded is returned by modifications of structure, which new version would exceed Limits.
+	ErrLimitExceeded = internal.ErrLimitExceeded
+)
+
+// LimitKind is the kind of limit of versions: LimitVersions or LimitDepth.
+type LimitKind = internal.LimitKind
+
+const (
+	// LimitVersions limits the amount of versions including the initial one.
+	LimitVersions = internal.LimitVersions
+	// LimitDepth limits the depth of version, that is the amount of its ancestors.
+	LimitDepth = internal.LimitDepth
+)
+
+// Limits restricts versions, that structure creates, so quotas can be enforced well before versions are exhausted.
+// Zero fields mean no limit or no threshold.
+//
+// MaxVersions is the maximum amount of versions including the initial one, and MaxDepth is the maximum depth
+// of version, the initial version has depth 0. Modification, which new version would exceed them,
+// returns ErrLimitExceeded.
+//
+// OnThreshold is called with LimitVersions when the amount of versions reaches VersionsThreshold,
+// and with LimitDepth for each created version of depth DepthThreshold. It is called during modification
+// before it is finished, so it must not access the structure.
+type Limits = internal.Limits
+
+// SetLimits sets limits for new versions of structure. Existing versions are kept even if they exceed limits.
+// SetLimits must not be called concurrently with modifications.
+//
+// Complexity: O(1).
+func (h versionHistory[T]) SetLimits(limits Limits) {
+	h.versionTree.SetLimits(limits)
+}
+
+// Depth returns the depth of version, that is the amount of its ancestors.
+//
+// Complexity: O(1).
+func (h versionHistory[T]) Depth(version uint64) (uint64, error) {
+	return h.versionTree.Depth(version)
+}
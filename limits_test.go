@@ -0,0 +1,79 @@
+package go_persistent_ds
+
+import (
+	"testing"
+)
+
+func TestSetLimits(t *testing.T) {
+	m, v0 := NewMap[string, int]()
+
+	var crossed []LimitKind
+	m.SetLimits(Limits{
+		MaxVersions:       4,
+		MaxDepth:          2,
+		VersionsThreshold: 3,
+		OnThreshold: func(kind LimitKind, _ uint64) {
+			crossed = append(crossed, kind)
+		},
+	})
+
+	v1, err := m.Set(v0, "a", 1)
+	errIsNil(t, err)
+	v2, err := m.Set(v1, "b", 2)
+	errIsNil(t, err)
+	isTrue(t, len(crossed) == 1 && crossed[0] == LimitVersions)
+
+	_, err = m.Delete(v2, "a")
+	errShouldBe(t, err, ErrLimitExceeded)
+	versionShouldBe(t, m.LastVersion(), v2)
+
+	depth, err := m.Depth(v2)
+	errIsNil(t, err)
+	isTrue(t, depth == 2)
+
+	_, err = m.Set(v0, "c", 3)
+	errIsNil(t, err)
+	_, err = m.Set(v0, "d", 4)
+	errShouldBe(t, err, ErrLimitExceeded)
+
+	val, err := m.Get(v2, "a")
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+
+	m.SetLimits(Limits{})
+	_, err = m.Delete(v2, "a")
+	errIsNil(t, err)
+}
+
+func TestSetLimits_AllStructures(t *testing.T) {
+	s, sv := NewSlice[int]()
+	s.SetLimits(Limits{MaxDepth: 1})
+	sv, err := s.Append(sv, 1)
+	errIsNil(t, err)
+	_, err = s.Append(sv, 2)
+	errShouldBe(t, err, ErrLimitExceeded)
+
+	l, lv := NewDoubleLinkedList[int]()
+	l.SetLimits(Limits{MaxVersions: 1})
+	_, err = l.PushBack(lv, 1)
+	errShouldBe(t, err, ErrLimitExceeded)
+
+	d, dv := NewDeque[int]()
+	d.SetLimits(Limits{MaxVersions: 1})
+	_, err = d.PushFront(dv, 1)
+	errShouldBe(t, err, ErrLimitExceeded)
+
+	r, rv := NewRopeFromString("text")
+	var depthCrossed uint64
+	r.SetLimits(Limits{
+		DepthThreshold: 1,
+		OnThreshold: func(kind LimitKind, version uint64) {
+			if kind == LimitDepth {
+				depthCrossed = version
+			}
+		},
+	})
+	rv, err = r.Insert(rv, 0, "a ")
+	errIsNil(t, err)
+	versionShouldBe(t, depthCrossed, rv)
+}
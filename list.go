@@ -7,6 +7,7 @@ import (
 	"github.com/AleksandrMatsko/go-persistent-ds/internal"
 )
 
+// ErrListIndexOutOfRange is matched by IndexError as well as ErrIndexOutOfRange.
 var ErrListIndexOutOfRange = errors.New("index out of range")
 
 // DoubleLinkedList is a persistent implementation of double linked list.
@@ -14,35 +15,101 @@ var ErrListIndexOutOfRange = errors.New("index out of range")
 // Each change of the list creates new version. Versions can be retrieved by their number.
 // Also, there is an opportunity to convert this list into Go list using ToGoList.
 //
-// DoubleLinkedList can perform total of 2^65-1 modifications, and will panic on attempt to modify it for 2^65 time.
-// If you need to continue editing DoubleLinkedList, the good idea is to use ToGoList method to dump list for special version.
+// DoubleLinkedList can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing DoubleLinkedList, the good idea is to use Compact method, that creates new DoubleLinkedList
+// with special version as the initial one.
+//
+// Values and links of elements are kept in Cells. Cells and versions are stored by Backend,
+// which is FatNodeBackend unless WithBackend option is given.
 //
 // Note that DoubleLinkedList implementation is not thread safe.
 type DoubleLinkedList[T any] struct {
-	versionTree *internal.VersionTree[listInfo]
-	storage     []*internal.FatNode
+	versionHistory[listInfo]
+	storage []internal.Cell
+	backend Backend
+	// visibleCells is true if cells of backend find visible values themselves, so history is not needed.
+	visibleCells bool
+	// base reads nodes of the forked list, which fields are not written after Fork.
+	base *listBase[T]
+	// overlay keeps Cells of fields of nodes of the forked list, which are written after Fork.
+	overlay map[*infoNode]*infoNode
+	forks   forkLock
+}
+
+// listBase is the version of DoubleLinkedList, that is shared with DoubleLinkedList created by Fork.
+type listBase[T any] struct {
+	source  *DoubleLinkedList[T]
+	version uint64
+	history []uint64
 }
 
 type listInfo struct {
 	listSize int
+	hash     sequenceHash
 
 	head *infoNode
 	tail *infoNode
 }
 
 type infoNode struct {
-	prev *internal.FatNode
-	next *internal.FatNode
+	prev internal.Cell
+	next internal.Cell
+
+	value internal.Cell
+
+	// owner is the lock of the list, that created node.
+	owner *forkLock
+}
+
+// nodeField is the field of infoNode.
+type nodeField int
+
+const (
+	nodePrev nodeField = iota
+	nodeNext
+	nodeValue
+)
+
+// field returns Cell of node by its kind.
+func (n *infoNode) field(field nodeField) *internal.Cell {
+	switch field {
+	case nodePrev:
+		return &n.prev
+	case nodeNext:
+		return &n.next
+	default:
+		return &n.value
+	}
+}
+
+// ListOption configures DoubleLinkedList on creation.
+type ListOption interface {
+	applyToList(cfg *listConfig)
+}
 
-	value *internal.FatNode
+type listConfig struct {
+	backend Backend
+	journal bool
 }
 
 // NewDoubleLinkedList creates new empty DoubleLinkedList.
-func NewDoubleLinkedList[T any]() (*DoubleLinkedList[T], uint64) {
+func NewDoubleLinkedList[T any](opts ...ListOption) (*DoubleLinkedList[T], uint64) {
+	cfg := listConfig{
+		backend: FatNodeBackend(),
+	}
+	for _, opt := range opts {
+		opt.applyToList(&cfg)
+	}
+
+	store := cfg.backend.NewVersionStore()
 	newList := &DoubleLinkedList[T]{
-		versionTree: internal.NewVersionTree[listInfo](),
-		storage:     make([]*internal.FatNode, 0),
+		versionHistory: newJournaledVersionHistory(internal.NewVersionTreeWithStore[listInfo](store), cfg.journal),
+		storage:        make([]internal.Cell, 0),
+		backend:        cfg.backend,
 	}
+	// backend creates cells of the same kind for the store, so the probe cell shows, whether history is needed.
+	_, newList.visibleCells = cfg.backend.NewCell(store, 0, nil).(VisibleCell)
 	infoNode := &infoNode{
 		prev: nil,
 		next: nil,
@@ -62,16 +129,28 @@ func NewDoubleLinkedList[T any]() (*DoubleLinkedList[T], uint64) {
 }
 
 // NewDoubleLinkedListWithAnyValues creates DoubleLinkedList that can store values of any type.
-func NewDoubleLinkedListWithAnyValues() (*DoubleLinkedList[any], uint64) {
-	return NewDoubleLinkedList[any]()
+func NewDoubleLinkedListWithAnyValues(opts ...ListOption) (*DoubleLinkedList[any], uint64) {
+	return NewDoubleLinkedList[any](opts...)
 }
 
 // PushFront adds new element to the head of the DoubleLinkedList. Returns list's new version.
 // Note: head->[1][2][3]<-tail.
 //
-// Complexity: O(n).
+// Complexity: O(1).
 func (l *DoubleLinkedList[T]) PushFront(version uint64, value T) (uint64, error) {
-	return l.push(value, version, true)
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	var head *infoNode
+	if info.listSize > 0 {
+		head = info.head
+	}
+
+	event := ChangeEvent{Op: OpPushFront, NewValue: value}
+
+	return l.insertBetween(version, info, nil, head, info.hash.pushFront(value), event, value)
 }
 
 // PushBack adds new element to the tail of the DoubleLinkedList. Returns list's new version.
@@ -79,49 +158,156 @@ func (l *DoubleLinkedList[T]) PushFront(version uint64, value T) (uint64, error)
 //
 // Complexity: O(1).
 func (l *DoubleLinkedList[T]) PushBack(version uint64, value T) (uint64, error) {
-	return l.push(value, version, false)
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	var tail *infoNode
+	if info.listSize > 0 {
+		tail = info.tail
+	}
+
+	event := ChangeEvent{Op: OpPushBack, Index: info.listSize, NewValue: value}
+
+	return l.insertBetween(version, info, tail, nil, info.hash.pushBack(value), event, value)
 }
 
-// Update updates element of specified DoubleLinkedList version by index. Returns list's new version.
+// InsertAt inserts new element into specified DoubleLinkedList version, so it gets given index.
+// Index equal to the list size means adding to the tail. Returns list's new version.
 //
 // Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
-func (l *DoubleLinkedList[T]) Update(version uint64, index int, value T) (uint64, error) {
+func (l *DoubleLinkedList[T]) InsertAt(version uint64, index int, value T) (uint64, error) {
 	info, err := l.versionTree.GetVersionInfo(version)
 	if err != nil {
 		return 0, err
 	}
-	if index >= info.listSize {
-		return 0, ErrListIndexOutOfRange
+	if index < 0 || index > info.listSize {
+		return 0, newIndexError(version, index, info.listSize)
 	}
 
-	changeHistory, err := l.versionTree.GetHistory(version)
+	var newHash sequenceHash
+	switch index {
+	case 0:
+		newHash = info.hash.pushFront(value)
+	case info.listSize:
+		newHash = info.hash.pushBack(value)
+	default:
+		newHash = info.hash.invalidate()
+	}
+
+	changeHistory, err := l.history(version)
 	if err != nil {
 		return 0, err
 	}
-	head := info.head
-	iterInfo := head
-	for i := 0; i < index; i++ {
-		node := l.findNodeByChangeHistory(iterInfo.next, changeHistory, version)
-		iterInfo = node.(*infoNode)
+	prev, next := l.neighboursAt(info, index, changeHistory, version)
+
+	event := ChangeEvent{Op: OpInsert, Index: index, NewValue: value}
+
+	return l.insertBetween(version, info, prev, next, newHash, event, value)
+}
+
+// PopFront removes the head of specified DoubleLinkedList version.
+// Returns removed value and list's new version.
+// If the list is empty ErrListIndexOutOfRange is returned.
+//
+// Complexity: O(k * log(m)), where k - amount of modifications visible from current branch
+// and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) PopFront(version uint64) (T, uint64, error) {
+	return l.pop(version, true)
+}
+
+// PopBack removes the tail of specified DoubleLinkedList version.
+// Returns removed value and list's new version.
+// If the list is empty ErrListIndexOutOfRange is returned.
+//
+// Complexity: O(k * log(m)), where k - amount of modifications visible from current branch
+// and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) PopBack(version uint64) (T, uint64, error) {
+	return l.pop(version, false)
+}
+
+// Splice inserts all elements of other DoubleLinkedList of otherVersion into specified version,
+// so the first inserted element gets given index. Other list may be the same list.
+// All elements are inserted in a single new version, which is returned.
+//
+// Complexity: O((n + p) * log(m)), where n - DoubleLinkedList size, p - size of other list
+// and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) Splice(version uint64, index int, other *DoubleLinkedList[T], otherVersion uint64) (uint64, error) {
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+	if index < 0 || index > info.listSize {
+		return 0, newIndexError(version, index, info.listSize)
 	}
 
-	newVersion, err := l.versionTree.Update(version)
+	otherInfo, err := other.versionTree.GetVersionInfo(otherVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	values, err := other.values(otherVersion)
 	if err != nil {
 		return 0, err
 	}
 
-	iterInfo.value.Update(value, newVersion)
+	return l.spliceValues(version, info, index, values, otherInfo.hash)
+}
 
-	err = l.versionTree.SetVersionInfo(newVersion, listInfo{
-		listSize: info.listSize,
-		head:     info.head,
-		tail:     info.tail,
-	})
+// spliceValues creates new version, in which values with given hash are inserted into version,
+// so the first of them gets given index.
+func (l *DoubleLinkedList[T]) spliceValues(version uint64, info *listInfo, index int, values []T, valuesHash sequenceHash) (uint64, error) {
+	var newHash sequenceHash
+	switch index {
+	case 0:
+		newHash = valuesHash.concat(info.hash)
+	case info.listSize:
+		newHash = info.hash.concat(valuesHash)
+	default:
+		newHash = info.hash.invalidate()
+	}
+
+	changeHistory, err := l.history(version)
 	if err != nil {
 		return 0, err
 	}
+	prev, next := l.neighboursAt(info, index, changeHistory, version)
 
-	return newVersion, nil
+	event := ChangeEvent{Op: OpSplice, Index: index, Count: len(values), NewValue: values}
+
+	return l.insertBetween(version, info, prev, next, newHash, event, values...)
+}
+
+// Update updates element of specified DoubleLinkedList version by index. Returns list's new version.
+//
+// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) Update(version uint64, index int, value T) (uint64, error) {
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+	if index < 0 || index >= info.listSize {
+		return 0, newIndexError(version, index, info.listSize)
+	}
+
+	changeHistory, err := l.history(version)
+	if err != nil {
+		return 0, err
+	}
+	node := l.nodeAt(info, index, changeHistory, version)
+	oldValue := l.nodeValue(node, changeHistory, version)
+	newHash := info.hash.set(index, oldValue, value)
+	event := ChangeEvent{Op: OpSet, Index: index, OldValue: oldValue, NewValue: value}
+
+	return l.setValue(version, info, node, value, newHash, event)
+}
+
+// Set is the same as Update. It lets DoubleLinkedList be used as Indexed.
+//
+// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) Set(version uint64, index int, value T) (uint64, error) {
+	return l.Update(version, index, value)
 }
 
 // Len returns DoubleLinkedList size.
@@ -144,182 +330,657 @@ func (l *DoubleLinkedList[T]) Remove(version uint64, index int) (uint64, error)
 	if err != nil {
 		return 0, err
 	}
-	if index >= info.listSize {
-		return 0, ErrListIndexOutOfRange
+	if index < 0 || index >= info.listSize {
+		return 0, newIndexError(version, index, info.listSize)
 	}
 
-	changeHistory, err := l.versionTree.GetHistory(version)
+	changeHistory, err := l.history(version)
 	if err != nil {
 		return 0, err
 	}
-	head := info.head
-	iterInfo := head
-	for i := 0; i < index; i++ {
-		node := l.findNodeByChangeHistory(iterInfo.next, changeHistory, version)
-		iterInfo = node.(*infoNode)
+	node := l.nodeAt(info, index, changeHistory, version)
+	oldValue := l.nodeValue(node, changeHistory, version)
+
+	var newHash sequenceHash
+	switch index {
+	case 0:
+		newHash = info.hash.popFront(oldValue)
+	case info.listSize - 1:
+		newHash = info.hash.popBack(oldValue)
+	default:
+		newHash = info.hash.invalidate()
 	}
 
-	newVersion, err := l.versionTree.Update(version)
+	event := ChangeEvent{Op: OpRemove, Index: index, OldValue: oldValue}
+
+	return l.unlink(version, info, node, changeHistory, newHash, event)
+}
+
+// Get retrieves value from the specified DoubleLinkedList version by index.
+//
+// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) Get(version uint64, index int) (T, error) {
+	info, err := l.versionTree.GetVersionInfo(version)
 	if err != nil {
-		return 0, err
+		return *new(T), err
+	}
+	if index < 0 || index >= info.listSize {
+		return *new(T), newIndexError(version, index, info.listSize)
+	}
+
+	changeHistory, err := l.history(version)
+	if err != nil {
+		return *new(T), err
+	}
+	node := l.nodeAt(info, index, changeHistory, version)
+
+	return l.nodeValue(node, changeHistory, version), nil
+}
+
+// ToGoList converts DoubleLinkedList into Go List.
+//
+// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) ToGoList(version uint64) (*list.List, error) {
+	values, err := l.values(version)
+	if err != nil {
+		return nil, err
 	}
 
-	previousNode := l.findNodeByChangeHistory(iterInfo.prev, changeHistory, version)
-	nextNode := l.findNodeByChangeHistory(iterInfo.next, changeHistory, version)
-	prevInfo := previousNode.(*infoNode)
-	nextInfo := nextNode.(*infoNode)
+	newList := list.New()
+	for _, val := range values {
+		newList.PushBack(val)
+	}
 
-	prevInfo.next.Update(nextInfo, newVersion)
-	nextInfo.prev.Update(prevInfo, newVersion)
+	return newList, nil
+}
 
-	iterInfo.next.Update(nil, newVersion)
-	iterInfo.prev.Update(nil, newVersion)
+// Equal reports whether versions v1 and v2 of DoubleLinkedList contain equal values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise same as for ToGoSlice.
+// Stale hashes are computed first, see ContentHash.
+func (l *DoubleLinkedList[T]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := l.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
 
-	err = l.versionTree.SetVersionInfo(newVersion, listInfo{
-		listSize: info.listSize - 1,
-		head:     info.head,
-		tail:     info.tail,
-	})
+	info2, err := l.versionTree.GetVersionInfo(v2)
 	if err != nil {
-		return 0, err
+		return false, err
 	}
 
-	return newVersion, nil
+	if info1.listSize != info2.listSize {
+		return false, nil
+	}
+
+	hash1, err := l.knownHash(v1, info1)
+	if err != nil {
+		return false, err
+	}
+
+	hash2, err := l.knownHash(v2, info2)
+	if err != nil {
+		return false, err
+	}
+
+	if hashesDiffer(hash1, hash2) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalSequences(l.values, v1, v2)
 }
 
-// Get retrieves value from the specified DoubleLinkedList version by index.
+// ContentHash returns hash of values of DoubleLinkedList of given version, that is maintained on each modification.
+// Versions with equal values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
 //
-// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
-func (l *DoubleLinkedList[T]) Get(version uint64, index int) (T, error) {
+// Hash of version created by insertion or removal in the middle of the list is stale: it is computed from values
+// on the first request and saved for the version.
+//
+// Complexity: O(1), for stale hash same as for ToGoSlice.
+func (l *DoubleLinkedList[T]) ContentHash(version uint64) (uint64, bool) {
 	info, err := l.versionTree.GetVersionInfo(version)
 	if err != nil {
-		return *new(T), err
+		return 0, false
 	}
-	if index >= info.listSize {
-		return *new(T), ErrListIndexOutOfRange
+
+	hash, err := l.knownHash(version, info)
+	if err != nil {
+		return 0, false
 	}
 
-	changeHistory, err := l.versionTree.GetHistory(version)
+	return hash.contentHash()
+}
+
+// knownHash returns hash of version. Stale hash is computed from values and saved in info.
+func (l *DoubleLinkedList[T]) knownHash(version uint64, info *listInfo) (sequenceHash, error) {
+	if !info.hash.stale {
+		return info.hash, nil
+	}
+
+	values, err := l.values(version)
 	if err != nil {
-		return *new(T), err
+		return sequenceHash{}, err
 	}
-	head := info.head
-	iterInfo := head
-	for i := 0; i < index; i++ {
-		node := l.findNodeByChangeHistory(iterInfo.next, changeHistory, version)
-		iterInfo = node.(*infoNode)
+
+	unlock := l.forks.lock()
+	info.hash = sequenceHashOf(values)
+	unlock()
+
+	return info.hash, nil
+}
+
+// Compact creates new DoubleLinkedList, which initial version has content of given version
+// with one Cell for each value and link. With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode,
+// with WithVersionMapping plus O(k) modifications, where k - amount of versions created after given one.
+func (l *DoubleLinkedList[T]) Compact(version uint64, opts ...CompactOption) (*DoubleLinkedList[T], error) {
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
 	}
 
-	val := l.findNodeByChangeHistory(iterInfo.value, changeHistory, version)
-	return val.(T), nil
+	values, err := l.values(version)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := []ListOption{WithBackend(l.backend)}
+	if l.subscribers.keepJournal {
+		listOpts = append(listOpts, WithJournal())
+	}
+	compacted, _ := NewDoubleLinkedList[T](listOpts...)
+	if len(values) > 0 {
+		compacted.fill(values, info.hash)
+	}
+
+	if err = l.replayDescendants(version, opts, l.rebuildEvent, compacted.applyEvent); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
 }
 
-// ToGoList converts DoubleLinkedList into Go List.
+// Fork creates new DoubleLinkedList, which initial version shares elements of given version without copying them.
+// The new DoubleLinkedList reads Cells of the original one for fields of elements, which are not written after Fork,
+// and writes its own Cells. Modifications of the new DoubleLinkedList don't create versions or Cell values
+// of the original one, so after Fork returns both of them can be modified concurrently. Modifications
+// of the original DoubleLinkedList then lock out reads of the shared Cells by the new one.
 //
-// Complexity: O(Get) * n, where n - DoubleLinkedList size.
-func (l *DoubleLinkedList[T]) ToGoList(version uint64) (*list.List, error) {
+// Complexity: O(d), where d - depth of given version.
+func (l *DoubleLinkedList[T]) Fork(version uint64) (*DoubleLinkedList[T], error) {
 	info, err := l.versionTree.GetVersionInfo(version)
 	if err != nil {
 		return nil, err
 	}
 
-	newList := list.New()
+	history, err := l.history(version)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := []ListOption{WithBackend(l.backend)}
+	if l.subscribers.keepJournal {
+		listOpts = append(listOpts, WithJournal())
+	}
+	forked, _ := NewDoubleLinkedList[T](listOpts...)
+
+	l.forks.forked.Store(true)
+	forked.base = &listBase[T]{
+		source:  l,
+		version: version,
+		history: history,
+	}
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+// fill makes the initial version of the list have given values with given hash.
+func (l *DoubleLinkedList[T]) fill(values []T, hash sequenceHash) {
+	info := listInfo{
+		listSize: len(values),
+		hash:     hash,
+	}
+
+	for _, value := range values {
+		valueCell := l.backend.NewCell(l.versionTree.Store(), 0, value)
+		l.storage = append(l.storage, valueCell)
+
+		node := &infoNode{
+			value: valueCell,
+			owner: &l.forks,
+		}
+
+		if info.tail != nil {
+			l.setLink(node, nodePrev, info.tail, 0)
+			l.setLink(info.tail, nodeNext, node, 0)
+		} else {
+			info.head = node
+		}
+		info.tail = node
+	}
+
+	_ = l.versionTree.SetVersionInfo(0, info)
+}
+
+// applyEvent repeats modification described by event for given version.
+func (l *DoubleLinkedList[T]) applyEvent(version uint64, event ChangeEvent) (uint64, error) {
+	val, _ := event.NewValue.(T)
+
+	switch event.Op {
+	case OpSet:
+		return l.Set(version, event.Index, val)
+	case OpPushFront:
+		return l.PushFront(version, val)
+	case OpPushBack:
+		return l.PushBack(version, val)
+	case OpInsert:
+		return l.InsertAt(version, event.Index, val)
+	case OpRemove:
+		return l.Remove(version, event.Index)
+	case OpPopFront, OpPopBack:
+		_, newVersion, err := l.pop(version, event.Op == OpPopFront)
+		return newVersion, err
+	case OpSplice:
+		info, err := l.versionTree.GetVersionInfo(version)
+		if err != nil {
+			return 0, err
+		}
+
+		values, _ := event.NewValue.([]T)
+
+		return l.spliceValues(version, info, event.Index, values, sequenceHashOf(values))
+	case OpReplace:
+		values, _ := event.NewValue.([]T)
+
+		return l.replaceValues(version, values)
+	default:
+		return 0, ErrNotFound
+	}
+}
+
+// ToGoSlice converts DoubleLinkedList into Go slice with elements from head to tail.
+//
+// Complexity: O(n * log(m)), where n - DoubleLinkedList size and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) ToGoSlice(version uint64) ([]T, error) {
+	return l.values(version)
+}
+
+// values returns all values of specified DoubleLinkedList version from head to tail.
+func (l *DoubleLinkedList[T]) values(version uint64) ([]T, error) {
+	var values []T
+	err := l.walk(version, func(_ int, val T) bool {
+		values = append(values, val)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// walk calls yield for values of specified DoubleLinkedList version from head to tail, until yield returns false.
+// History of version is computed once for all values.
+func (l *DoubleLinkedList[T]) walk(version uint64, yield func(index int, val T) bool) error {
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return err
+	}
+	changeHistory, err := l.history(version)
+	if err != nil {
+		return err
+	}
+
+	node := info.head
 	for i := 0; i < info.listSize; i++ {
-		val, _ := l.Get(version, i)
-		newList.PushBack(val)
+		if !yield(i, l.nodeValue(node, changeHistory, version)) {
+			break
+		}
+		node = l.nextNode(node, changeHistory, version)
 	}
 
-	return newList, nil
+	return nil
+}
+
+func (l *DoubleLinkedList[T]) pop(version uint64, isFront bool) (T, uint64, error) {
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+	if info.listSize == 0 {
+		return *new(T), 0, newIndexError(version, 0, 0)
+	}
+
+	changeHistory, err := l.history(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	node, newHash := info.tail, info.hash.popBack
+	event := ChangeEvent{Op: OpPopBack, Index: info.listSize - 1}
+	if isFront {
+		node, newHash = info.head, info.hash.popFront
+		event = ChangeEvent{Op: OpPopFront}
+	}
+	val := l.nodeValue(node, changeHistory, version)
+	event.OldValue = val
+
+	newVersion, err := l.unlink(version, info, node, changeHistory, newHash(val), event)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// history returns change history of version. It is not computed if cells find visible values themselves.
+func (l *DoubleLinkedList[T]) history(version uint64) ([]uint64, error) {
+	if l.visibleCells {
+		return nil, nil
+	}
+
+	return l.versionTree.GetHistory(version)
+}
+
+func (l *DoubleLinkedList[T]) findNodeByChangeHistory(cell internal.Cell, changeHistory []uint64, version uint64) interface{} {
+	if cell == nil {
+		return nil
+	}
+
+	val, _ := readCell(cell, version, knownHistory(changeHistory))
+
+	return val
+}
+
+// readField returns value of field of node for version. Fields of nodes of the forked list, which are not
+// written after Fork, are read from the forked list.
+func (l *DoubleLinkedList[T]) readField(node *infoNode, field nodeField, changeHistory []uint64, version uint64) any {
+	if node.owner == &l.forks {
+		return l.findNodeByChangeHistory(*node.field(field), changeHistory, version)
+	}
+
+	if overlay, ok := l.overlay[node]; ok && *overlay.field(field) != nil {
+		if val, found := readCell(*overlay.field(field), version, knownHistory(changeHistory)); found {
+			return val
+		}
+	}
+
+	return l.base.readField(node, field)
+}
+
+// readField returns value of field of node for the forked version.
+func (b *listBase[T]) readField(node *infoNode, field nodeField) any {
+	b.source.forks.mu.RLock()
+	defer b.source.forks.mu.RUnlock()
+
+	return b.source.readField(node, field, b.history, b.version)
+}
+
+// writeField writes value of field of node for version. Fields of nodes of the forked list are written to overlay.
+func (l *DoubleLinkedList[T]) writeField(node *infoNode, field nodeField, version uint64, data any) {
+	if node.owner != &l.forks {
+		overlay, ok := l.overlay[node]
+		if !ok {
+			if l.overlay == nil {
+				l.overlay = make(map[*infoNode]*infoNode)
+			}
+
+			overlay = &infoNode{owner: &l.forks}
+			l.overlay[node] = overlay
+		}
+		node = overlay
+	}
+
+	cell := node.field(field)
+	if *cell == nil {
+		*cell = l.backend.NewCell(l.versionTree.Store(), version, data)
+		return
+	}
+
+	(*cell).Write(version, data)
+}
+
+// nodeAt walks to the element with given index from the closest end of the list.
+func (l *DoubleLinkedList[T]) nodeAt(info *listInfo, index int, changeHistory []uint64, version uint64) *infoNode {
+	if index <= info.listSize/2 {
+		node := info.head
+		for i := 0; i < index; i++ {
+			node = l.nextNode(node, changeHistory, version)
+		}
+		return node
+	}
+
+	node := info.tail
+	for i := info.listSize - 1; i > index; i-- {
+		node = l.prevNode(node, changeHistory, version)
+	}
+	return node
+}
+
+// neighboursAt returns elements between which new element with given index should be placed.
+// Nil prev means that new element becomes the head, nil next means that new element becomes the tail.
+func (l *DoubleLinkedList[T]) neighboursAt(
+	info *listInfo,
+	index int,
+	changeHistory []uint64,
+	version uint64,
+) (*infoNode, *infoNode) {
+	if info.listSize == 0 {
+		return nil, nil
+	}
+	if index == info.listSize {
+		return info.tail, nil
+	}
+
+	next := l.nodeAt(info, index, changeHistory, version)
+	if index == 0 {
+		return nil, next
+	}
+
+	return l.prevNode(next, changeHistory, version), next
+}
+
+func (l *DoubleLinkedList[T]) nextNode(node *infoNode, changeHistory []uint64, version uint64) *infoNode {
+	next, _ := l.readField(node, nodeNext, changeHistory, version).(*infoNode)
+	return next
 }
 
-func (l *DoubleLinkedList[T]) push(value T, version uint64, isFront bool) (uint64, error) {
-	oldVersionInfo, err := l.versionTree.GetVersionInfo(version)
+func (l *DoubleLinkedList[T]) prevNode(node *infoNode, changeHistory []uint64, version uint64) *infoNode {
+	prev, _ := l.readField(node, nodePrev, changeHistory, version).(*infoNode)
+	return prev
+}
+
+func (l *DoubleLinkedList[T]) nodeValue(node *infoNode, changeHistory []uint64, version uint64) T {
+	val, _ := l.readField(node, nodeValue, changeHistory, version).(T)
+	return val
+}
+
+// setValue creates new version of the list with given hash, in which node holds given value.
+// Subscribers are notified with given event.
+func (l *DoubleLinkedList[T]) setValue(
+	version uint64,
+	info *listInfo,
+	node *infoNode,
+	value T,
+	hash sequenceHash,
+	event ChangeEvent,
+) (uint64, error) {
+	unlock := l.forks.lock()
+	newVersion, err := l.versionTree.Update(version)
+	if err != nil {
+		unlock()
+		return 0, err
+	}
+
+	l.writeField(node, nodeValue, newVersion, value)
+
+	err = l.versionTree.SetVersionInfo(newVersion, listInfo{
+		listSize: info.listSize,
+		hash:     hash,
+		head:     info.head,
+		tail:     info.tail,
+	})
+	unlock()
 	if err != nil {
 		return 0, err
 	}
 
+	l.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
+
+// insertBetween creates new version of the list with given hash and new elements placed between prev and next.
+// Nil prev means that the elements become new head, nil next means that the elements become new tail.
+// Subscribers are notified with given event.
+func (l *DoubleLinkedList[T]) insertBetween(
+	version uint64,
+	info *listInfo,
+	prev, next *infoNode,
+	hash sequenceHash,
+	event ChangeEvent,
+	values ...T,
+) (uint64, error) {
+	unlock := l.forks.lock()
 	newVersion, err := l.versionTree.Update(version)
 	if err != nil {
+		unlock()
 		return 0, err
 	}
-	newFatNode := internal.NewFatNode(value, newVersion)
-	l.storage = append(l.storage, newFatNode)
 
-	prevHead := oldVersionInfo.head
-	prevTail := oldVersionInfo.tail
+	newListInfo := listInfo{
+		listSize: info.listSize + len(values),
+		hash:     hash,
+		head:     info.head,
+		tail:     info.tail,
+	}
 
-	if oldVersionInfo.listSize == 0 {
-		newInfo := &infoNode{
-			next: nil,
-			prev: nil,
+	last := prev
+	for _, value := range values {
+		valueCell := l.backend.NewCell(l.versionTree.Store(), newVersion, value)
+		l.storage = append(l.storage, valueCell)
 
-			value: newFatNode,
+		newNode := &infoNode{
+			value: valueCell,
+			owner: &l.forks,
 		}
-		err = l.versionTree.SetVersionInfo(newVersion, listInfo{
-			listSize: oldVersionInfo.listSize + 1,
-			head:     newInfo,
-			tail:     newInfo,
-		})
-	} else {
-		if isFront {
-			newHeadInfo := &infoNode{
-				next:  internal.NewFatNode(prevHead, newVersion),
-				prev:  nil,
-				value: newFatNode,
-			}
-			if prevHead.prev == nil {
-				prevHead.prev = internal.NewFatNode(newHeadInfo, newVersion)
-			} else {
-				prevHead.prev.Update(newHeadInfo, newVersion)
-			}
-			newListInfo := listInfo{
-				listSize: oldVersionInfo.listSize + 1,
-				head:     newHeadInfo,
-				tail:     prevTail,
-			}
-			err = l.versionTree.SetVersionInfo(newVersion, newListInfo)
+
+		if last != nil {
+			l.setLink(newNode, nodePrev, last, newVersion)
+			l.setLink(last, nodeNext, newNode, newVersion)
 		} else {
-			newTailInfo := &infoNode{
-				next:  nil,
-				prev:  internal.NewFatNode(prevTail, newVersion),
-				value: newFatNode,
-			}
-			if prevTail.next == nil {
-				prevTail.next = internal.NewFatNode(newTailInfo, newVersion)
-			} else {
-				prevTail.next.Update(newTailInfo, newVersion)
-			}
-			newListInfo := listInfo{
-				listSize: oldVersionInfo.listSize + 1,
-				head:     prevHead,
-				tail:     newTailInfo,
-			}
-			err = l.versionTree.SetVersionInfo(newVersion, newListInfo)
+			newListInfo.head = newNode
+		}
+
+		last = newNode
+	}
+
+	if next != nil {
+		if last != nil {
+			l.setLink(next, nodePrev, last, newVersion)
+			l.setLink(last, nodeNext, next, newVersion)
+		} else {
+			newListInfo.head = next
 		}
+	} else if last != nil {
+		newListInfo.tail = last
 	}
+
+	err = l.versionTree.SetVersionInfo(newVersion, newListInfo)
+	unlock()
 	if err != nil {
 		return 0, err
 	}
 
+	l.notify(version, newVersion, event)
+
 	return newVersion, nil
 }
 
-func (l *DoubleLinkedList[T]) findNodeByChangeHistory(fn *internal.FatNode, changeHistory []uint64, version uint64) interface{} {
-	val, _, found := fn.FindByVersion(version)
-	if found {
-		return val
+// replaceValues creates new version of the list, which consists of new elements with given values.
+// Subscribers are notified with OpReplace event.
+func (l *DoubleLinkedList[T]) replaceValues(version uint64, values []T) (uint64, error) {
+	empty := &infoNode{}
+
+	return l.insertBetween(
+		version,
+		&listInfo{head: empty, tail: empty},
+		nil, nil,
+		sequenceHashOf(values),
+		ChangeEvent{Op: OpReplace, Count: len(values), NewValue: values},
+		values...,
+	)
+}
+
+// unlink creates new version of the list with given hash and without given node.
+// Subscribers are notified with given event.
+func (l *DoubleLinkedList[T]) unlink(
+	version uint64,
+	info *listInfo,
+	node *infoNode,
+	changeHistory []uint64,
+	hash sequenceHash,
+	event ChangeEvent,
+) (uint64, error) {
+	prev := l.prevNode(node, changeHistory, version)
+	next := l.nextNode(node, changeHistory, version)
+
+	unlock := l.forks.lock()
+	newVersion, err := l.versionTree.Update(version)
+	if err != nil {
+		unlock()
+		return 0, err
 	}
 
-	if len(changeHistory) == 1 || len(changeHistory) == 2 {
-		return nil
+	newListInfo := listInfo{
+		listSize: info.listSize - 1,
+		hash:     hash,
+		head:     info.head,
+		tail:     info.tail,
 	}
 
-	for i := len(changeHistory) - 2; i >= 1; i-- {
-		val, _, found = fn.FindByVersion(changeHistory[i])
-		if found {
-			return val
-		}
+	if prev != nil {
+		l.setLink(prev, nodeNext, next, newVersion)
+	} else {
+		newListInfo.head = next
 	}
 
-	return nil
+	if next != nil {
+		l.setLink(next, nodePrev, prev, newVersion)
+	} else {
+		newListInfo.tail = prev
+	}
+
+	if newListInfo.listSize == 0 {
+		newListInfo.head = &infoNode{}
+		newListInfo.tail = newListInfo.head
+	}
+
+	err = l.versionTree.SetVersionInfo(newVersion, newListInfo)
+	unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	l.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
+
+// setLink points link of node to target starting from given version.
+func (l *DoubleLinkedList[T]) setLink(node *infoNode, link nodeField, target *infoNode, version uint64) {
+	var data interface{}
+	if target != nil {
+		data = target
+	}
+
+	l.writeField(node, link, version, data)
 }
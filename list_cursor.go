@@ -0,0 +1,245 @@
+package go_persistent_ds
+
+import (
+	"slices"
+)
+
+// Cursor points to an element of the specified DoubleLinkedList version.
+// Moving the cursor is cheap, because it remembers its position and doesn't walk the list from the head.
+//
+// Cursor is bound to a version: it never observes changes made in other versions.
+// Mutating methods don't change the cursor, instead they create new version of the list
+// and return new cursor, that is positioned in that version.
+//
+// Note that Cursor is not thread safe.
+type Cursor[T any] struct {
+	list          *DoubleLinkedList[T]
+	version       uint64
+	info          *listInfo
+	changeHistory []uint64
+
+	node  *infoNode
+	index int
+}
+
+// Front returns Cursor pointing to the head of specified DoubleLinkedList version.
+// If the list is empty ErrListIndexOutOfRange is returned.
+//
+// Complexity: O(k), where k - amount of modifications visible from current branch.
+func (l *DoubleLinkedList[T]) Front(version uint64) (*Cursor[T], error) {
+	return l.CursorAt(version, 0)
+}
+
+// Back returns Cursor pointing to the tail of specified DoubleLinkedList version.
+// If the list is empty ErrListIndexOutOfRange is returned.
+//
+// Complexity: O(k), where k - amount of modifications visible from current branch.
+func (l *DoubleLinkedList[T]) Back(version uint64) (*Cursor[T], error) {
+	size, err := l.Len(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.CursorAt(version, size-1)
+}
+
+// CursorAt returns Cursor pointing to the element of specified DoubleLinkedList version by index.
+//
+// Complexity: O(k + n * log(m)), where k - amount of modifications visible from current branch,
+// n - DoubleLinkedList size and m - is number of changes in FatNode.
+func (l *DoubleLinkedList[T]) CursorAt(version uint64, index int) (*Cursor[T], error) {
+	info, err := l.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= info.listSize {
+		return nil, newIndexError(version, index, info.listSize)
+	}
+
+	changeHistory, err := l.history(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[T]{
+		list:          l,
+		version:       version,
+		info:          info,
+		changeHistory: changeHistory,
+		node:          l.nodeAt(info, index, changeHistory, version),
+		index:         index,
+	}, nil
+}
+
+// Version returns the version of DoubleLinkedList the Cursor is bound to.
+func (c *Cursor[T]) Version() uint64 {
+	return c.version
+}
+
+// Index returns the index of the element the Cursor points to.
+func (c *Cursor[T]) Index() int {
+	return c.index
+}
+
+// Value returns the value of the element the Cursor points to.
+//
+// Complexity: O(k * log(m)), where k - amount of modifications visible from current branch
+// and m - is number of changes in FatNode.
+func (c *Cursor[T]) Value() T {
+	return c.list.nodeValue(c.node, c.changeHistory, c.version)
+}
+
+// Next moves the Cursor to the next element. If the Cursor points to the tail,
+// it stays in place and false is returned.
+//
+// Complexity: O(k * log(m)), where k - amount of modifications visible from current branch
+// and m - is number of changes in FatNode.
+func (c *Cursor[T]) Next() bool {
+	if c.index+1 >= c.info.listSize {
+		return false
+	}
+
+	c.node = c.list.nextNode(c.node, c.changeHistory, c.version)
+	c.index++
+	return true
+}
+
+// Prev moves the Cursor to the previous element. If the Cursor points to the head,
+// it stays in place and false is returned.
+//
+// Complexity: O(k * log(m)), where k - amount of modifications visible from current branch
+// and m - is number of changes in FatNode.
+func (c *Cursor[T]) Prev() bool {
+	if c.index == 0 {
+		return false
+	}
+
+	c.node = c.list.prevNode(c.node, c.changeHistory, c.version)
+	c.index--
+	return true
+}
+
+// Set updates the value of the element the Cursor points to.
+// Returns new Cursor pointing to the same element in the new version of DoubleLinkedList.
+//
+// Complexity: O(k), where k - amount of modifications visible from current branch.
+func (c *Cursor[T]) Set(value T) (*Cursor[T], uint64, error) {
+	oldValue := c.Value()
+	newHash := c.info.hash.set(c.index, oldValue, value)
+	event := ChangeEvent{Op: OpSet, Index: c.index, OldValue: oldValue, NewValue: value}
+
+	newVersion, err := c.list.setValue(c.version, c.info, c.node, value, newHash, event)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c.moveTo(newVersion, c.node, c.index)
+}
+
+// InsertBefore adds new element before the element the Cursor points to.
+// Returns new Cursor pointing to the same element in the new version of DoubleLinkedList,
+// so sequential calls insert values in the order they were passed.
+//
+// Complexity: O(k), where k - amount of modifications visible from current branch.
+func (c *Cursor[T]) InsertBefore(value T) (*Cursor[T], uint64, error) {
+	var prev *infoNode
+	newHash := c.info.hash.pushFront(value)
+	if c.index > 0 {
+		prev = c.list.prevNode(c.node, c.changeHistory, c.version)
+		newHash = c.info.hash.invalidate()
+	}
+
+	event := ChangeEvent{Op: OpInsert, Index: c.index, NewValue: value}
+
+	newVersion, err := c.list.insertBetween(c.version, c.info, prev, c.node, newHash, event, value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c.moveTo(newVersion, c.node, c.index+1)
+}
+
+// InsertAfter adds new element after the element the Cursor points to.
+// Returns new Cursor pointing to the same element in the new version of DoubleLinkedList.
+//
+// Complexity: O(k), where k - amount of modifications visible from current branch.
+func (c *Cursor[T]) InsertAfter(value T) (*Cursor[T], uint64, error) {
+	var next *infoNode
+	newHash := c.info.hash.pushBack(value)
+	if c.index+1 < c.info.listSize {
+		next = c.list.nextNode(c.node, c.changeHistory, c.version)
+		newHash = c.info.hash.invalidate()
+	}
+
+	event := ChangeEvent{Op: OpInsert, Index: c.index + 1, NewValue: value}
+
+	newVersion, err := c.list.insertBetween(c.version, c.info, c.node, next, newHash, event, value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c.moveTo(newVersion, c.node, c.index)
+}
+
+// Remove removes the element the Cursor points to.
+// Returns new Cursor pointing to the next element in the new version of DoubleLinkedList,
+// or to the previous one if the tail was removed.
+// If the removed element was the only one, then nil Cursor is returned together with the new version.
+//
+// Complexity: O(k), where k - amount of modifications visible from current branch.
+func (c *Cursor[T]) Remove() (*Cursor[T], uint64, error) {
+	var (
+		next *infoNode
+		prev *infoNode
+	)
+	if c.index+1 < c.info.listSize {
+		next = c.list.nextNode(c.node, c.changeHistory, c.version)
+	}
+	if c.index > 0 {
+		prev = c.list.prevNode(c.node, c.changeHistory, c.version)
+	}
+
+	// hash can be updated incrementally only if the removed element is at the end of the list.
+	var newHash sequenceHash
+	switch {
+	case prev == nil:
+		newHash = c.info.hash.popFront(c.Value())
+	case next == nil:
+		newHash = c.info.hash.popBack(c.Value())
+	default:
+		newHash = c.info.hash.invalidate()
+	}
+
+	event := ChangeEvent{Op: OpRemove, Index: c.index, OldValue: c.Value()}
+
+	newVersion, err := c.list.unlink(c.version, c.info, c.node, c.changeHistory, newHash, event)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case next != nil:
+		return c.moveTo(newVersion, next, c.index)
+	case prev != nil:
+		return c.moveTo(newVersion, prev, c.index-1)
+	default:
+		return nil, newVersion, nil
+	}
+}
+
+// moveTo creates Cursor pointing to node in the newVersion, that is a child of the Cursor's version.
+func (c *Cursor[T]) moveTo(newVersion uint64, node *infoNode, index int) (*Cursor[T], uint64, error) {
+	info, err := c.list.versionTree.GetVersionInfo(newVersion)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &Cursor[T]{
+		list:          c.list,
+		version:       newVersion,
+		info:          info,
+		changeHistory: append(slices.Clip(c.changeHistory), newVersion),
+		node:          node,
+		index:         index,
+	}, newVersion, nil
+}
@@ -0,0 +1,213 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"testing"
+)
+
+func getListOfInts(t *testing.T, values ...int) (*DoubleLinkedList[int], uint64) {
+	l, version := NewDoubleLinkedList[int]()
+
+	var err error
+	for _, val := range values {
+		version, err = l.PushBack(version, val)
+		errIsNil(t, err)
+	}
+
+	return l, version
+}
+
+func listShouldBe(t *testing.T, l *DoubleLinkedList[int], version uint64, expected []int) {
+	t.Helper()
+
+	goList, err := l.ToGoList(version)
+	errIsNil(t, err)
+
+	actual := make([]int, 0, goList.Len())
+	for e := goList.Front(); e != nil; e = e.Next() {
+		actual = append(actual, e.Value.(int))
+	}
+
+	if !slices.Equal(actual, expected) {
+		t.Errorf("expected list %v for version %d, got %v", expected, version, actual)
+	}
+
+	size, err := l.Len(version)
+	errIsNil(t, err)
+	if size != len(expected) {
+		t.Errorf("expected len %d for version %d, got %d", len(expected), version, size)
+	}
+}
+
+func TestCursor_Navigation(t *testing.T) {
+	t.Run("On empty list", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := NewDoubleLinkedList[int]()
+
+		_, err := l.Front(version)
+		errShouldBe(t, err, ErrListIndexOutOfRange)
+
+		_, err = l.Back(version)
+		errShouldBe(t, err, ErrListIndexOutOfRange)
+	})
+
+	t.Run("Forward and backward", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 2, 3, 4)
+
+		c, err := l.Front(version)
+		errIsNil(t, err)
+
+		var forward []int
+		for {
+			forward = append(forward, c.Value())
+			if !c.Next() {
+				break
+			}
+		}
+		isTrue(t, slices.Equal(forward, []int{1, 2, 3, 4}))
+		isTrue(t, c.Index() == 3)
+
+		var backward []int
+		for {
+			backward = append(backward, c.Value())
+			if !c.Prev() {
+				break
+			}
+		}
+		isTrue(t, slices.Equal(backward, []int{4, 3, 2, 1}))
+		isTrue(t, c.Index() == 0)
+
+		c, err = l.Back(version)
+		errIsNil(t, err)
+		isTrue(t, c.Value() == 4)
+
+		c, err = l.CursorAt(version, 2)
+		errIsNil(t, err)
+		isTrue(t, c.Value() == 3)
+		versionShouldBe(t, c.Version(), version)
+
+		_, err = l.CursorAt(version, 4)
+		errShouldBe(t, err, ErrListIndexOutOfRange)
+	})
+}
+
+func TestCursor_Modifications(t *testing.T) {
+	t.Run("Set", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 2, 3)
+
+		c, err := l.CursorAt(version, 1)
+		errIsNil(t, err)
+
+		c, newVersion, err := c.Set(20)
+		errIsNil(t, err)
+		versionShouldBe(t, c.Version(), newVersion)
+		isTrue(t, c.Value() == 20)
+
+		isTrue(t, c.Next())
+		c, newVersion, err = c.Set(30)
+		errIsNil(t, err)
+
+		listShouldBe(t, l, newVersion, []int{1, 20, 30})
+		listShouldBe(t, l, version, []int{1, 2, 3})
+	})
+
+	t.Run("Insert like in editor buffer", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 5)
+
+		c, err := l.Back(version)
+		errIsNil(t, err)
+
+		for _, val := range []int{2, 3, 4} {
+			c, _, err = c.InsertBefore(val)
+			errIsNil(t, err)
+			isTrue(t, c.Value() == 5)
+		}
+		listShouldBe(t, l, c.Version(), []int{1, 2, 3, 4, 5})
+		isTrue(t, c.Index() == 4)
+
+		c, _, err = c.InsertAfter(6)
+		errIsNil(t, err)
+		isTrue(t, c.Next())
+		isTrue(t, c.Value() == 6)
+		isTrue(t, !c.Next())
+
+		front, err := l.Front(c.Version())
+		errIsNil(t, err)
+		front, newVersion, err := front.InsertBefore(0)
+		errIsNil(t, err)
+		isTrue(t, front.Prev())
+		isTrue(t, front.Value() == 0)
+
+		listShouldBe(t, l, newVersion, []int{0, 1, 2, 3, 4, 5, 6})
+		listShouldBe(t, l, version, []int{1, 5})
+
+		val, err := l.Get(newVersion, 6)
+		errIsNil(t, err)
+		isTrue(t, val == 6)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 2, 3)
+
+		c, err := l.CursorAt(version, 1)
+		errIsNil(t, err)
+
+		c, removeMiddle, err := c.Remove()
+		errIsNil(t, err)
+		isTrue(t, c.Value() == 3)
+		listShouldBe(t, l, removeMiddle, []int{1, 3})
+
+		c, removeTail, err := c.Remove()
+		errIsNil(t, err)
+		isTrue(t, c.Value() == 1)
+		listShouldBe(t, l, removeTail, []int{1})
+
+		c, removeLast, err := c.Remove()
+		errIsNil(t, err)
+		isTrue(t, c == nil)
+		listShouldBe(t, l, removeLast, []int{})
+
+		newVersion, err := l.PushBack(removeLast, 7)
+		errIsNil(t, err)
+		listShouldBe(t, l, newVersion, []int{7})
+
+		head, err := l.Front(version)
+		errIsNil(t, err)
+		_, removeHead, err := head.Remove()
+		errIsNil(t, err)
+		listShouldBe(t, l, removeHead, []int{2, 3})
+
+		newVersion, err = l.PushFront(removeHead, 0)
+		errIsNil(t, err)
+		listShouldBe(t, l, newVersion, []int{0, 2, 3})
+		listShouldBe(t, l, version, []int{1, 2, 3})
+	})
+}
+
+func TestDoubleLinkedList_RemoveEnds(t *testing.T) {
+	l, version := getListOfInts(t, 1, 2, 3)
+
+	removeHead, err := l.Remove(version, 0)
+	errIsNil(t, err)
+	listShouldBe(t, l, removeHead, []int{2, 3})
+
+	removeTail, err := l.Remove(version, 2)
+	errIsNil(t, err)
+	listShouldBe(t, l, removeTail, []int{1, 2})
+
+	newVersion, err := l.PushBack(removeTail, 4)
+	errIsNil(t, err)
+	listShouldBe(t, l, newVersion, []int{1, 2, 4})
+
+	_, err = l.Remove(version, -1)
+	errShouldBe(t, err, ErrListIndexOutOfRange)
+}
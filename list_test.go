@@ -482,3 +482,140 @@ func TestNewDoubleLinkedListWithAnyValues(t *testing.T) {
 		}())
 	})
 }
+
+func TestDoubleLinkedList_InsertAt(t *testing.T) {
+	l, version := getListOfInts(t, 1, 3)
+
+	_, err := l.InsertAt(version, 3, 4)
+	errShouldBe(t, err, ErrListIndexOutOfRange)
+	_, err = l.InsertAt(version, -1, 4)
+	errShouldBe(t, err, ErrListIndexOutOfRange)
+
+	middle, err := l.InsertAt(version, 1, 2)
+	errIsNil(t, err)
+	listShouldBe(t, l, middle, []int{1, 2, 3})
+
+	front, err := l.InsertAt(middle, 0, 0)
+	errIsNil(t, err)
+	listShouldBe(t, l, front, []int{0, 1, 2, 3})
+
+	back, err := l.InsertAt(front, 4, 4)
+	errIsNil(t, err)
+	listShouldBe(t, l, back, []int{0, 1, 2, 3, 4})
+
+	val, err := l.Get(back, 4)
+	errIsNil(t, err)
+	isTrue(t, val == 4)
+
+	empty, emptyVersion := NewDoubleLinkedList[int]()
+	emptyVersion, err = empty.InsertAt(emptyVersion, 0, 1)
+	errIsNil(t, err)
+	listShouldBe(t, empty, emptyVersion, []int{1})
+
+	listShouldBe(t, l, version, []int{1, 3})
+}
+
+func TestDoubleLinkedList_Pop(t *testing.T) {
+	t.Run("On empty list", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := NewDoubleLinkedList[int]()
+
+		_, _, err := l.PopFront(version)
+		errShouldBe(t, err, ErrListIndexOutOfRange)
+
+		_, _, err = l.PopBack(version)
+		errShouldBe(t, err, ErrListIndexOutOfRange)
+	})
+
+	t.Run("From both ends", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 2, 3)
+
+		val, popFront, err := l.PopFront(version)
+		errIsNil(t, err)
+		isTrue(t, val == 1)
+		listShouldBe(t, l, popFront, []int{2, 3})
+
+		val, popBack, err := l.PopBack(popFront)
+		errIsNil(t, err)
+		isTrue(t, val == 3)
+		listShouldBe(t, l, popBack, []int{2})
+
+		val, popLast, err := l.PopBack(popBack)
+		errIsNil(t, err)
+		isTrue(t, val == 2)
+		listShouldBe(t, l, popLast, []int{})
+
+		pushFront, err := l.PushFront(popLast, 5)
+		errIsNil(t, err)
+		pushBack, err := l.PushBack(pushFront, 6)
+		errIsNil(t, err)
+		listShouldBe(t, l, pushBack, []int{5, 6})
+
+		pushBack, err = l.PushBack(popBack, 4)
+		errIsNil(t, err)
+		listShouldBe(t, l, pushBack, []int{2, 4})
+
+		listShouldBe(t, l, version, []int{1, 2, 3})
+	})
+}
+
+func TestDoubleLinkedList_Splice(t *testing.T) {
+	t.Run("Bad index", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 2)
+		other, otherVersion := getListOfInts(t, 3)
+
+		_, err := l.Splice(version, 3, other, otherVersion)
+		errShouldBe(t, err, ErrListIndexOutOfRange)
+	})
+
+	t.Run("Into different positions", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 5)
+		other, otherVersion := getListOfInts(t, 2, 3, 4)
+
+		middle, err := l.Splice(version, 1, other, otherVersion)
+		errIsNil(t, err)
+		versionShouldBe(t, middle, version+1)
+		listShouldBe(t, l, middle, []int{1, 2, 3, 4, 5})
+
+		front, err := l.Splice(version, 0, other, otherVersion)
+		errIsNil(t, err)
+		listShouldBe(t, l, front, []int{2, 3, 4, 1, 5})
+
+		back, err := l.Splice(version, 2, other, otherVersion)
+		errIsNil(t, err)
+		listShouldBe(t, l, back, []int{1, 5, 2, 3, 4})
+
+		back, err = l.PushBack(back, 6)
+		errIsNil(t, err)
+		listShouldBe(t, l, back, []int{1, 5, 2, 3, 4, 6})
+
+		listShouldBe(t, l, version, []int{1, 5})
+		listShouldBe(t, other, otherVersion, []int{2, 3, 4})
+	})
+
+	t.Run("Same list and empty list", func(t *testing.T) {
+		t.Parallel()
+
+		l, version := getListOfInts(t, 1, 2)
+
+		doubled, err := l.Splice(version, 1, l, version)
+		errIsNil(t, err)
+		listShouldBe(t, l, doubled, []int{1, 1, 2, 2})
+
+		empty, emptyVersion := NewDoubleLinkedList[int]()
+		same, err := l.Splice(doubled, 2, empty, emptyVersion)
+		errIsNil(t, err)
+		listShouldBe(t, l, same, []int{1, 1, 2, 2})
+
+		filled, err := empty.Splice(emptyVersion, 0, l, doubled)
+		errIsNil(t, err)
+		listShouldBe(t, empty, filled, []int{1, 1, 2, 2})
+	})
+}
@@ -9,7 +9,7 @@ import (
 var (
 	// ErrMapInitialize is returned then there is a problem in creating new tree.
 	ErrMapInitialize = errors.New("failed to init Map because version tree is damaged")
-	// ErrNotFound is returned then value by key or for version is not found.
+	// ErrNotFound is matched by KeyError, that is returned then value by key is not found for the version.
 	ErrNotFound = errors.New("not found")
 )
 
@@ -17,40 +17,106 @@ var (
 // While working with map you can access and/or modify each previous version.
 // Note that modifying version creates new one.
 //
-// Map can perform total of 2^65-1 modifications, and will panic on attempt to modify it for 2^65 time.
-// If you need to continue editing Map, the good idea is to use ToGoMap method to dump Map for special version.
+// Map can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Map, the good idea is to use Compact method, that creates new Map
+// with special version as the initial one.
+//
+// By default, Map is based on Cells: one Cell for each key ever set. Cells and versions are stored by Backend,
+// which is FatNodeBackend unless WithBackend option is given.
+// Map created with WithHAMT option keeps hash array mapped trie for each version instead.
 //
 // Note that Map is not thread safe.
 type Map[TKey comparable, TVal any] struct {
-	versionTree   *internal.VersionTree[mapVersionInfo]
-	mapOfFatNodes map[TKey]*internal.FatNode
+	versionHistory[mapVersionInfo[TKey, TVal]]
+	mapOfCells map[TKey]internal.Cell
+	backend    Backend
+	useHAMT    bool
+	// base reads keys, which are not set after Fork, from the forked Map.
+	base  *mapBase[TKey, TVal]
+	forks forkLock
+}
+
+// mapBase is the version of Map with Cells, that is shared with Map created by Fork.
+type mapBase[TKey comparable, TVal any] struct {
+	source  *Map[TKey, TVal]
+	version uint64
+	history []uint64
 }
 
-type mapVersionInfo struct {
+type mapVersionInfo[TKey comparable, TVal any] struct {
 	size int
+	hash unorderedHash
+	// hamt is used only if Map is created with WithHAMT option.
+	hamt internal.HAMT[TKey, TVal]
+}
+
+// MapOption configures Map on creation.
+type MapOption interface {
+	applyToMap(cfg *mapConfig)
+}
+
+type mapConfig struct {
+	backend Backend
+	useHAMT bool
+	journal bool
+}
+
+type mapOptionFunc func(cfg *mapConfig)
+
+func (f mapOptionFunc) applyToMap(cfg *mapConfig) {
+	f(cfg)
+}
+
+// WithHAMT makes Map keep hash array mapped trie with path copying for each version instead of Cells.
+// Then Get takes O(log32(n)) regardless of the amount of versions, ToGoMap visits only keys of the version
+// and keys, that are not visible from any version, are not stored.
+func WithHAMT() MapOption {
+	return mapOptionFunc(func(cfg *mapConfig) {
+		cfg.useHAMT = true
+	})
 }
 
 // NewMap creates empty Map.
-func NewMap[TKey comparable, TVal any]() (*Map[TKey, TVal], uint64) {
-	return NewMapWithCapacity[TKey, TVal](0)
+func NewMap[TKey comparable, TVal any](opts ...MapOption) (*Map[TKey, TVal], uint64) {
+	return NewMapWithCapacity[TKey, TVal](0, opts...)
 }
 
 // NewMapWithCapacity creates empty Map with given capacity.
-func NewMapWithCapacity[TKey comparable, TVal any](capacity int) (*Map[TKey, TVal], uint64) {
+func NewMapWithCapacity[TKey comparable, TVal any](capacity int, opts ...MapOption) (*Map[TKey, TVal], uint64) {
+	cfg := mapConfig{
+		backend: FatNodeBackend(),
+	}
+	for _, opt := range opts {
+		opt.applyToMap(&cfg)
+	}
+
 	m := &Map[TKey, TVal]{
-		versionTree:   internal.NewVersionTree[mapVersionInfo](),
-		mapOfFatNodes: make(map[TKey]*internal.FatNode, capacity),
+		versionHistory: newJournaledVersionHistory(
+			internal.NewVersionTreeWithStore[mapVersionInfo[TKey, TVal]](cfg.backend.NewVersionStore()),
+			cfg.journal,
+		),
+		backend: cfg.backend,
+		useHAMT: cfg.useHAMT,
 	}
 
 	var (
 		initialVersion uint64 = 0
 		initialMapSize        = 0
+		initialHAMT    internal.HAMT[TKey, TVal]
 	)
 
+	if cfg.useHAMT {
+		initialHAMT = internal.NewHAMT[TKey, TVal]()
+	} else {
+		m.mapOfCells = make(map[TKey]internal.Cell, capacity)
+	}
+
 	err := m.versionTree.SetVersionInfo(
 		initialVersion,
-		mapVersionInfo{
+		mapVersionInfo[TKey, TVal]{
 			size: initialMapSize,
+			hamt: initialHAMT,
 		})
 	if err != nil {
 		panic(ErrMapInitialize)
@@ -60,50 +126,53 @@ func NewMapWithCapacity[TKey comparable, TVal any](capacity int) (*Map[TKey, TVa
 }
 
 // NewMapWithAnyValues creates a Map, that can store values of any type.
-func NewMapWithAnyValues[TKey comparable]() (*Map[TKey, any], uint64) {
-	return NewMapWithCapacity[TKey, any](0)
+func NewMapWithAnyValues[TKey comparable](opts ...MapOption) (*Map[TKey, any], uint64) {
+	return NewMapWithCapacity[TKey, any](0, opts...)
 }
 
 // Set value for given key and version in Map.
 //
 // Complexity: same as for Get.
 func (m *Map[TKey, TVal]) Set(forVersion uint64, key TKey, val TVal) (uint64, error) {
-	newVersion, err := m.versionTree.Update(forVersion)
+	if m.useHAMT {
+		return m.setToHAMT(forVersion, key, val)
+	}
+
+	oldVersionInfo, err := m.versionTree.GetVersionInfo(forVersion)
 	if err != nil {
 		return 0, err
 	}
 
-	oldVersionInfo, _ := m.versionTree.GetVersionInfo(forVersion)
-	newVersionInfo := mapVersionInfo{
+	newVersionInfo := mapVersionInfo[TKey, TVal]{
 		size: oldVersionInfo.size,
+		hash: oldVersionInfo.hash.add(key, val),
 	}
+	event := ChangeEvent{Op: OpSet, Key: key, NewValue: val}
 
-	fatNode, exists := m.mapOfFatNodes[key]
-	if !exists {
-		// adding new key
-		newFatNode := internal.NewFatNode(val, newVersion)
-		m.mapOfFatNodes[key] = newFatNode
-
+	oldVal, err := m.Get(forVersion, key)
+	if err != nil {
+		// adding new key or the key exists in other versions but not visible for the current version
 		newVersionInfo.size += 1
-
-		_ = m.versionTree.SetVersionInfo(
-			newVersion,
-			newVersionInfo)
-
-		return newVersion, nil
+	} else {
+		newVersionInfo.hash = newVersionInfo.hash.remove(key, oldVal)
+		event.OldValue = oldVal
 	}
 
-	fatNode.Update(val, newVersion)
-
-	_, err = m.Get(forVersion, key)
+	unlock := m.forks.lock()
+	newVersion, err := m.versionTree.Update(forVersion)
 	if err != nil {
-		// the key exists in other versions but not visible for the current version
-		newVersionInfo.size += 1
+		unlock()
+		return 0, err
 	}
 
+	m.writeCell(newVersion, key, val)
+
 	_ = m.versionTree.SetVersionInfo(
 		newVersion,
 		newVersionInfo)
+	unlock()
+
+	m.notify(forVersion, newVersion, event)
 
 	return newVersion, nil
 }
@@ -114,51 +183,111 @@ func (m *Map[TKey, TVal]) Set(forVersion uint64, key TKey, val TVal) (uint64, er
 // Complexity: O(log(m) * k) there:
 //   - m - amount of modifications for current key from map creation.
 //   - k - amount of modifications visible from current branch.
+//
+// For Map created with WithHAMT option complexity is O(log32(n)), there n - size of Map.
 func (m *Map[TKey, TVal]) Get(version uint64, key TKey) (TVal, error) {
-	fatNode, exists := m.mapOfFatNodes[key]
-	if !exists {
-		return *new(TVal), ErrNotFound
+	if m.useHAMT {
+		return m.getFromHAMT(version, key)
+	}
+
+	if _, err := m.versionTree.GetVersionInfo(version); err != nil {
+		return *new(TVal), err
 	}
 
-	// on version = 0 Map is empty
-	if version == 0 {
-		return *new(TVal), ErrNotFound
+	val, found := m.lookup(version, key, func() []uint64 {
+		changeHistory, _ := m.versionTree.GetHistory(version)
+		return changeHistory
+	})
+	if !found || val == nil {
+		return *new(TVal), newKeyError(version, key)
 	}
 
-	val, _, found := fatNode.FindByVersion(version)
-	if found {
-		// found value exactly for the version
-		if val == nil {
-			return *new(TVal), ErrNotFound
+	typed, _ := val.(TVal)
+
+	return typed, nil
+}
+
+// lookup returns value of key visible for version, that is nil for deleted key. Keys, which are not set
+// after Fork, are looked up in the forked Map. If key has no value, false is returned.
+func (m *Map[TKey, TVal]) lookup(version uint64, key TKey, history func() []uint64) (any, bool) {
+	if cell, exists := m.mapOfCells[key]; exists {
+		if val, found := readCell(cell, version, history); found {
+			return val, true
 		}
+	}
 
-		return val.(TVal), nil
+	if m.base == nil {
+		return nil, false
 	}
 
-	changeHistory, err := m.versionTree.GetHistory(version)
-	if err != nil {
-		return *new(TVal), ErrNotFound
+	return m.base.lookup(key)
+}
+
+// entries calls yield for keys visible for version with their values, until yield returns false.
+// Keys of the forked Map, which are not set after Fork, are yielded as well.
+func (m *Map[TKey, TVal]) entries(version uint64, history func() []uint64, yield func(key TKey, val any) bool) bool {
+	for key := range m.mapOfCells {
+		val, found := m.lookup(version, key, history)
+		if found && val != nil && !yield(key, val) {
+			return false
+		}
 	}
 
-	// zero version is always inside change history and has no values,
-	// and we already checked val existence for given version,
-	// so skip iterations if there are only 2 version: zero and given version
-	if len(changeHistory) == 1 || len(changeHistory) == 2 {
-		return *new(TVal), ErrNotFound
+	if m.base == nil {
+		return true
 	}
 
-	for i := uint64(len(changeHistory) - 2); i >= 1; i-- {
-		val, _, found = fatNode.FindByVersion(changeHistory[i])
-		if found {
-			if val == nil {
-				return *new(TVal), ErrNotFound
-			}
+	return m.base.entries(func(key TKey, val any) bool {
+		if _, own := m.mapOfCells[key]; own {
+			return true
+		}
+
+		return yield(key, val)
+	})
+}
+
+// writeCell writes value of key for version into Cell of the key, creating it if needed.
+func (m *Map[TKey, TVal]) writeCell(version uint64, key TKey, val any) {
+	if cell, ok := m.mapOfCells[key]; ok {
+		cell.Write(version, val)
+		return
+	}
+
+	m.mapOfCells[key] = m.backend.NewCell(m.versionTree.Store(), version, val)
+}
+
+// lookup returns value of key for the forked version, see Map.lookup.
+func (b *mapBase[TKey, TVal]) lookup(key TKey) (any, bool) {
+	b.source.forks.mu.RLock()
+	defer b.source.forks.mu.RUnlock()
 
-			return val.(TVal), nil
+	return b.source.lookup(b.version, key, knownHistory(b.history))
+}
+
+// entries calls yield for keys of the forked version, see Map.entries. Keys are collected under the lock,
+// so yield may use the forked Map.
+func (b *mapBase[TKey, TVal]) entries(yield func(key TKey, val any) bool) bool {
+	type entry struct {
+		key TKey
+		val any
+	}
+
+	var collected []entry
+
+	b.source.forks.mu.RLock()
+	b.source.entries(b.version, knownHistory(b.history), func(key TKey, val any) bool {
+		collected = append(collected, entry{key, val})
+		return true
+	})
+	b.source.forks.mu.RUnlock()
+
+	for _, e := range collected {
+		if !yield(e.key, e.val) {
+			return false
 		}
 	}
 
-	return *new(TVal), ErrNotFound
+	return true
 }
 
 // Len returns the len of Map.
@@ -177,40 +306,240 @@ func (m *Map[TKey, TVal]) Len(forVersion uint64) (int, error) {
 //
 // Complexity: same as for Get.
 func (m *Map[TKey, TVal]) Delete(forVersion uint64, key TKey) (uint64, error) {
-	existedFatNode, keyExists := m.mapOfFatNodes[key]
-	if !keyExists {
-		// no key to delete
-		return 0, ErrNotFound
+	if m.useHAMT {
+		return m.deleteFromHAMT(forVersion, key)
 	}
 
-	_, err := m.Get(forVersion, key)
+	oldVal, err := m.Get(forVersion, key)
 	if err != nil {
-		// key exists but no value visible for this version
+		// no value visible for this version
 		return 0, err
 	}
 
+	unlock := m.forks.lock()
 	newVersion, err := m.versionTree.Update(forVersion)
 	if err != nil {
-		return 0, ErrNotFound
+		unlock()
+		return 0, err
 	}
 
 	oldVersionInfo, _ := m.versionTree.GetVersionInfo(forVersion)
-	newVersionInfo := mapVersionInfo{
+	newVersionInfo := mapVersionInfo[TKey, TVal]{
 		size: oldVersionInfo.size - 1,
+		hash: oldVersionInfo.hash.remove(key, oldVal),
 	}
 
-	existedFatNode.Update(nil, newVersion)
-	m.mapOfFatNodes[key] = existedFatNode
+	m.writeCell(newVersion, key, nil)
 
 	_ = m.versionTree.SetVersionInfo(newVersion, newVersionInfo)
+	unlock()
+
+	m.notify(forVersion, newVersion, ChangeEvent{Op: OpDelete, Key: key, OldValue: oldVal})
+
+	return newVersion, nil
+}
+
+// replaceValues creates new version, which has only given keys and values. Cells are written only for keys,
+// which values differ from given version. Subscribers are notified with OpReplace event.
+func (m *Map[TKey, TVal]) replaceValues(forVersion uint64, values map[TKey]TVal) (uint64, error) {
+	oldValues, err := m.ToGoMap(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	info := mapVersionInfo[TKey, TVal]{
+		size: len(values),
+	}
+	for key, val := range values {
+		info.hash = info.hash.add(key, val)
+	}
+	event := ChangeEvent{Op: OpReplace, Count: len(values), NewValue: values}
+
+	if m.useHAMT {
+		oldVersionInfo, _ := m.versionTree.GetVersionInfo(forVersion)
+		info.hamt = oldVersionInfo.hamt
+		for key := range oldValues {
+			if _, kept := values[key]; !kept {
+				info.hamt, _ = info.hamt.Delete(key)
+			}
+		}
+		for key, val := range values {
+			if oldVal, exists := oldValues[key]; !exists || !valuesEqual(oldVal, val) {
+				info.hamt = info.hamt.Set(key, val)
+			}
+		}
+
+		return m.commitHAMT(forVersion, info, event)
+	}
+
+	unlock := m.forks.lock()
+	newVersion, err := m.versionTree.Update(forVersion)
+	if err != nil {
+		unlock()
+		return 0, err
+	}
+
+	for key := range oldValues {
+		if _, kept := values[key]; !kept {
+			m.writeCell(newVersion, key, nil)
+		}
+	}
+	for key, val := range values {
+		oldVal, exists := oldValues[key]
+		if exists && valuesEqual(oldVal, val) {
+			continue
+		}
+
+		m.writeCell(newVersion, key, val)
+	}
+
+	_ = m.versionTree.SetVersionInfo(newVersion, info)
+	unlock()
+
+	m.notify(forVersion, newVersion, event)
 
 	return newVersion, nil
 }
 
+// Equal reports whether versions v1 and v2 of Map contain equal keys and values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise same as for ToGoMap.
+func (m *Map[TKey, TVal]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := m.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := m.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.size != info2.size || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalMaps(m.ToGoMap, v1, v2)
+}
+
+// ContentHash returns hash of keys and values of Map of given version, that is maintained on each modification.
+// Versions with equal keys and values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (m *Map[TKey, TVal]) ContentHash(version uint64) (uint64, bool) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new Map, which initial version has content of given version. Map with Cells gets one Cell
+// with a single value for each key, Map created with WithHAMT option shares the tree with given version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: same as for ToGoMap, with WithVersionMapping plus O(Set) * k,
+// where k - amount of versions created after given one.
+func (m *Map[TKey, TVal]) Compact(version uint64, opts ...CompactOption) (*Map[TKey, TVal], error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	mapOpts, capacity := []MapOption{WithBackend(m.backend)}, info.size
+	if m.useHAMT {
+		mapOpts, capacity = append(mapOpts, WithHAMT()), 0
+	}
+	if m.subscribers.keepJournal {
+		mapOpts = append(mapOpts, WithJournal())
+	}
+	compacted, _ := NewMapWithCapacity[TKey, TVal](capacity, mapOpts...)
+
+	if !m.useHAMT {
+		values, toGoErr := m.ToGoMap(version)
+		if toGoErr != nil {
+			return nil, toGoErr
+		}
+
+		for key, val := range values {
+			compacted.mapOfCells[key] = compacted.backend.NewCell(compacted.versionTree.Store(), 0, val)
+		}
+	}
+
+	_ = compacted.versionTree.SetVersionInfo(0, mapVersionInfo[TKey, TVal]{
+		size: info.size,
+		hash: info.hash,
+		hamt: info.hamt,
+	})
+
+	err = m.replayDescendants(version, opts, m.rebuildEvent, func(newParent uint64, event ChangeEvent) (uint64, error) {
+		op := newMapOp[TKey, TVal](event)
+		op.Parent = newParent
+
+		return compacted.applyOp(op)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new Map, which initial version shares content of given version without copying it.
+// Map created with WithHAMT option shares the tree with given version. Map with Cells reads Cells
+// of the original Map for keys, which are not set after Fork, and writes its own Cells.
+// Modifications of the new Map don't create versions or Cell values of the original one, so after Fork returns
+// both of them can be modified concurrently. Modifications of the original Map with Cells then lock out
+// reads of the shared Cells by the new Map.
+//
+// Complexity: O(d), where d - depth of given version. For Map created with WithHAMT option complexity is O(1).
+func (m *Map[TKey, TVal]) Fork(version uint64) (*Map[TKey, TVal], error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	mapOpts := []MapOption{WithBackend(m.backend)}
+	if m.useHAMT {
+		mapOpts = append(mapOpts, WithHAMT())
+	}
+	if m.subscribers.keepJournal {
+		mapOpts = append(mapOpts, WithJournal())
+	}
+	forked, _ := NewMap[TKey, TVal](mapOpts...)
+
+	if !m.useHAMT {
+		history, historyErr := m.versionTree.GetHistory(version)
+		if historyErr != nil {
+			return nil, historyErr
+		}
+
+		m.forks.forked.Store(true)
+		forked.base = &mapBase[TKey, TVal]{
+			source:  m,
+			version: version,
+			history: history,
+		}
+	}
+
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
 // ToGoMap converts persistent Map for specified version into go map.
 //
 // Complexity: O(Get) * n, there:
 //   - n - amount of different keys in map from creation.
+//
+// For Map created with WithHAMT option complexity is O(n), there n - size of Map for the version.
 func (m *Map[TKey, TVal]) ToGoMap(version uint64) (map[TKey]TVal, error) {
 	versionInfo, err := m.versionTree.GetVersionInfo(version)
 	if err != nil {
@@ -218,12 +547,40 @@ func (m *Map[TKey, TVal]) ToGoMap(version uint64) (map[TKey]TVal, error) {
 	}
 
 	resMap := make(map[TKey]TVal, versionInfo.size)
-	for k := range m.mapOfFatNodes {
-		val, err := m.Get(version, k)
-		if err == nil {
-			resMap[k] = val
-		}
+	err = m.walk(version, func(k TKey, v TVal) bool {
+		resMap[k] = v
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return resMap, nil
 }
+
+// walk calls yield for keys and values of given version in unspecified order, until yield returns false.
+// History of version is computed once for all keys.
+func (m *Map[TKey, TVal]) walk(version uint64, yield func(key TKey, val TVal) bool) error {
+	versionInfo, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return err
+	}
+
+	if m.useHAMT {
+		versionInfo.hamt.All(yield)
+
+		return nil
+	}
+
+	changeHistory, err := m.versionTree.GetHistory(version)
+	if err != nil {
+		return err
+	}
+
+	m.entries(version, knownHistory(changeHistory), func(key TKey, val any) bool {
+		typed, _ := val.(TVal)
+		return yield(key, typed)
+	})
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package go_persistent_ds
+
+func (m *Map[TKey, TVal]) setToHAMT(forVersion uint64, key TKey, val TVal) (uint64, error) {
+	oldVersionInfo, err := m.versionTree.GetVersionInfo(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	event := ChangeEvent{Op: OpSet, Key: key, NewValue: val}
+	newHash := oldVersionInfo.hash.add(key, val)
+	if oldVal, found := oldVersionInfo.hamt.Get(key); found {
+		newHash = newHash.remove(key, oldVal)
+		event.OldValue = oldVal
+	}
+
+	newHAMT := oldVersionInfo.hamt.Set(key, val)
+
+	return m.commitHAMT(forVersion, mapVersionInfo[TKey, TVal]{
+		size: newHAMT.Len(),
+		hash: newHash,
+		hamt: newHAMT,
+	}, event)
+}
+
+func (m *Map[TKey, TVal]) getFromHAMT(version uint64, key TKey) (TVal, error) {
+	versionInfo, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(TVal), err
+	}
+
+	val, found := versionInfo.hamt.Get(key)
+	if !found {
+		return *new(TVal), newKeyError(version, key)
+	}
+
+	return val, nil
+}
+
+func (m *Map[TKey, TVal]) deleteFromHAMT(forVersion uint64, key TKey) (uint64, error) {
+	oldVersionInfo, err := m.versionTree.GetVersionInfo(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	oldVal, found := oldVersionInfo.hamt.Get(key)
+	if !found {
+		return 0, newKeyError(forVersion, key)
+	}
+
+	newHAMT, _ := oldVersionInfo.hamt.Delete(key)
+
+	return m.commitHAMT(forVersion, mapVersionInfo[TKey, TVal]{
+		size: newHAMT.Len(),
+		hash: oldVersionInfo.hash.remove(key, oldVal),
+		hamt: newHAMT,
+	}, ChangeEvent{Op: OpDelete, Key: key, OldValue: oldVal})
+}
+
+func (m *Map[TKey, TVal]) commitHAMT(forVersion uint64, info mapVersionInfo[TKey, TVal], event ChangeEvent) (uint64, error) {
+	newVersion, err := m.versionTree.Update(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = m.versionTree.SetVersionInfo(newVersion, info)
+
+	m.notify(forVersion, newVersion, event)
+
+	return newVersion, nil
+}
@@ -0,0 +1,84 @@
+package go_persistent_ds
+
+import (
+	"maps"
+	"strconv"
+	"testing"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+func TestMap_WithHAMT(t *testing.T) {
+	t.Run("Same behaviour as FatNode based Map", func(t *testing.T) {
+		t.Parallel()
+
+		fatNodeMap, fatNodeVersion := NewMap[string, int]()
+		hamtMap, hamtVersion := NewMap[string, int](WithHAMT())
+		versionShouldBe(t, hamtVersion, fatNodeVersion)
+
+		versions := []uint64{0}
+		for i := 0; i < 300; i++ {
+			forVersion := versions[(i*7)%len(versions)]
+			key := strconv.Itoa(i % 37)
+
+			var (
+				gotFatNode, gotHAMT uint64
+				errFatNode, errHAMT error
+			)
+			if i%3 == 2 {
+				gotFatNode, errFatNode = fatNodeMap.Delete(forVersion, key)
+				gotHAMT, errHAMT = hamtMap.Delete(forVersion, key)
+			} else {
+				gotFatNode, errFatNode = fatNodeMap.Set(forVersion, key, i)
+				gotHAMT, errHAMT = hamtMap.Set(forVersion, key, i)
+			}
+			errShouldBe(t, errHAMT, errFatNode)
+			versionShouldBe(t, gotHAMT, gotFatNode)
+			if errHAMT == nil {
+				versions = append(versions, gotHAMT)
+			}
+		}
+
+		for _, version := range versions {
+			expected, err := fatNodeMap.ToGoMap(version)
+			errIsNil(t, err)
+
+			got, err := hamtMap.ToGoMap(version)
+			errIsNil(t, err)
+			isTrue(t, maps.Equal(got, expected))
+
+			size, err := hamtMap.Len(version)
+			errIsNil(t, err)
+			isTrue(t, size == len(expected))
+
+			for i := 0; i < 37; i++ {
+				key := strconv.Itoa(i)
+				expectedVal, expectedErr := fatNodeMap.Get(version, key)
+				gotVal, gotErr := hamtMap.Get(version, key)
+				errShouldBe(t, gotErr, expectedErr)
+				isTrue(t, gotVal == expectedVal)
+			}
+		}
+	})
+
+	t.Run("Errors on unknown version", func(t *testing.T) {
+		t.Parallel()
+
+		m, _ := NewMapWithAnyValues[string](WithHAMT())
+
+		_, err := m.Set(1, "a", 1)
+		errShouldBe(t, err, internal.ErrVersionNotFound)
+
+		_, err = m.Get(1, "a")
+		errShouldBe(t, err, ErrVersionNotFound)
+
+		_, err = m.Delete(1, "a")
+		errShouldBe(t, err, ErrVersionNotFound)
+
+		_, err = m.Delete(0, "a")
+		errShouldBe(t, err, ErrNotFound)
+
+		_, err = m.ToGoMap(1)
+		errShouldBe(t, err, internal.ErrVersionNotFound)
+	})
+}
@@ -20,7 +20,7 @@ func isTrue(t *testing.T, flag bool) {
 	}
 }
 
-func errIsNil(t *testing.T, err error) {
+func errIsNil(t testing.TB, err error) {
 	if err != nil {
 		t.Errorf("error should be nil, but got %T: %s", err, err)
 	}
@@ -130,7 +130,7 @@ func TestMap_with_GetSet(t *testing.T) {
 		errIsNil(t, err)
 
 		val, err := m.Get(2, "a")
-		errShouldBe(t, err, ErrNotFound)
+		errShouldBe(t, err, ErrVersionNotFound)
 		isTrue(t, val == "")
 	})
 
@@ -301,7 +301,7 @@ func TestMap_with_GetSetDelete(t *testing.T) {
 		versionShouldBe(t, v, 1)
 
 		v, err = m.Delete(2, "a")
-		errShouldBe(t, err, ErrNotFound)
+		errShouldBe(t, err, ErrVersionNotFound)
 		versionShouldBe(t, v, 0)
 	})
 }
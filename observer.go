@@ -0,0 +1,194 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"sync"
+)
+
+// OpKind is the kind of modification, that created new version.
+type OpKind int
+
+const (
+	// OpSet sets value by key of Map, OrderedMap and Trie or by index of Slice and DoubleLinkedList.
+	// Event has Key or Index, OldValue if there was value, and NewValue.
+	OpSet OpKind = iota
+	// OpDelete deletes key of Map, OrderedMap and Trie, event has Key and OldValue.
+	// For Rope it deletes Count runes starting from Index, event has removed text as OldValue.
+	OpDelete
+	// OpInsert inserts value into DoubleLinkedList, event has Index and NewValue.
+	// For Rope it inserts text of Count runes at Index, event has the text as NewValue.
+	OpInsert
+	// OpRemove removes value from DoubleLinkedList, event has Index and OldValue.
+	OpRemove
+	// OpAppend adds value to the end of Slice, event has Index and NewValue.
+	OpAppend
+	// OpPrepend adds Count values to the beginning of Slice, event has slice of values as NewValue.
+	OpPrepend
+	// OpConcat adds Count values to the end of Slice starting from Index, event has slice of values as NewValue.
+	// Slice.Resize, that increases size, sends it with fill values.
+	OpConcat
+	// OpSplice inserts Count values into DoubleLinkedList starting from Index, event has slice of values as NewValue.
+	OpSplice
+	// OpRange takes Count values of Slice starting from Index.
+	OpRange
+	// OpPushFront adds value to the front of DoubleLinkedList or Deque, event has NewValue.
+	OpPushFront
+	// OpPushBack adds value to the back of DoubleLinkedList or Deque, event has Index and NewValue.
+	OpPushBack
+	// OpPopFront removes value from the front of DoubleLinkedList or Deque, event has OldValue.
+	OpPopFront
+	// OpPopBack removes value from the back of DoubleLinkedList or Deque, or the last value of Slice.
+	// Event has Index and OldValue.
+	OpPopBack
+	// OpPush adds value to Stack or PriorityQueue, event has NewValue. For Stack event also has Index.
+	OpPush
+	// OpPop removes value from the top of Stack, event has Index and OldValue.
+	OpPop
+	// OpEnqueue adds value to the end of Queue, event has Index and NewValue.
+	OpEnqueue
+	// OpDequeue removes value from the head of Queue, event has OldValue.
+	OpDequeue
+	// OpPopMin removes the minimal value from PriorityQueue, event has OldValue.
+	OpPopMin
+	// OpMeld adds values of other version of PriorityQueue, event has that version as NewValue.
+	OpMeld
+	// OpReplace replaces all elements of Map, Slice or DoubleLinkedList by result of transform such as MapValues,
+	// Filter or SortSlice. Event has new elements as NewValue, map[K]V for Map and []T otherwise,
+	// and their amount as Count.
+	OpReplace
+	// OpTruncate keeps first Count values of Slice. Slice.Resize, that reduces size, sends it too.
+	OpTruncate
+)
+
+var opKindNames = [...]string{
+	OpSet:       "Set",
+	OpDelete:    "Delete",
+	OpInsert:    "Insert",
+	OpRemove:    "Remove",
+	OpAppend:    "Append",
+	OpPrepend:   "Prepend",
+	OpConcat:    "Concat",
+	OpSplice:    "Splice",
+	OpRange:     "Range",
+	OpPushFront: "PushFront",
+	OpPushBack:  "PushBack",
+	OpPopFront:  "PopFront",
+	OpPopBack:   "PopBack",
+	OpPush:      "Push",
+	OpPop:       "Pop",
+	OpEnqueue:   "Enqueue",
+	OpDequeue:   "Dequeue",
+	OpPopMin:    "PopMin",
+	OpMeld:      "Meld",
+	OpReplace:   "Replace",
+	OpTruncate:  "Truncate",
+}
+
+// String returns name of OpKind.
+func (k OpKind) String() string {
+	if k < 0 || int(k) >= len(opKindNames) {
+		return "Unknown"
+	}
+
+	return opKindNames[k]
+}
+
+// ChangeEvent describes modification, that created new version of structure.
+// Fields, which are not described for the operation in OpKind, are zero.
+type ChangeEvent struct {
+	// Parent is the modified version.
+	Parent uint64
+	// Version is the created version.
+	Version uint64
+	// Op is the kind of modification.
+	Op OpKind
+	// Key is the affected key of Map, OrderedMap or Trie.
+	Key any
+	// Index is the affected index of Slice, DoubleLinkedList, Deque, Stack, Queue or Rope.
+	Index int
+	// Count is the amount of affected values or runes.
+	Count int
+	// OldValue is the replaced or removed value.
+	OldValue any
+	// NewValue is the set or added value.
+	NewValue any
+}
+
+// subscribers keeps functions, that are called on each modification of structure.
+// Subscribe and unsubscribe are safe to call concurrently with modifications.
+type subscribers struct {
+	mu       sync.Mutex
+	handlers []*func(ChangeEvent)
+	// journal keeps event of each version by its index to replay changes, if keepJournal is set
+	// by WithJournal option.
+	journal     []ChangeEvent
+	keepJournal bool
+}
+
+// Subscribe makes handler to be called synchronously after each modification of structure,
+// that created new version. Handler is not called for reads. Returns function, that unsubscribes handler.
+// Handlers are called in order of subscription and may unsubscribe themselves.
+//
+// Complexity: O(1).
+func (h versionHistory[T]) Subscribe(handler func(ev ChangeEvent)) (unsubscribe func()) {
+	h.subscribers.mu.Lock()
+	defer h.subscribers.mu.Unlock()
+
+	return h.subscribers.add(handler)
+}
+
+// add registers handler. Lock must be held.
+func (s *subscribers) add(handler func(ev ChangeEvent)) (unsubscribe func()) {
+	entry := &handler
+	s.handlers = append(s.handlers, entry)
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.handlers = slices.DeleteFunc(s.handlers, func(other *func(ChangeEvent)) bool {
+			return other == entry
+		})
+	}
+}
+
+// subscribed reports whether there are subscribers or journal, so events are worth building.
+func (h versionHistory[T]) subscribed() bool {
+	h.subscribers.mu.Lock()
+	defer h.subscribers.mu.Unlock()
+
+	return h.subscribers.keepJournal || len(h.subscribers.handlers) > 0
+}
+
+// journaled returns event of version from journal. If version is not journaled, false is returned.
+// Lock must be held.
+func (s *subscribers) journaled(version uint64) (ChangeEvent, bool) {
+	if version >= uint64(len(s.journal)) {
+		return ChangeEvent{}, false
+	}
+
+	// version has zero event only if its creation failed after it was added to the tree.
+	event := s.journal[version]
+
+	return event, event.Version == version && version != 0
+}
+
+// notify records event about creation of version from parent into journal and calls subscribers with it.
+func (h versionHistory[T]) notify(parent, version uint64, event ChangeEvent) {
+	event.Parent = parent
+	event.Version = version
+
+	h.subscribers.mu.Lock()
+	if h.subscribers.keepJournal {
+		for uint64(len(h.subscribers.journal)) <= version {
+			h.subscribers.journal = append(h.subscribers.journal, ChangeEvent{})
+		}
+		h.subscribers.journal[version] = event
+	}
+	handlers := slices.Clone(h.subscribers.handlers)
+	h.subscribers.mu.Unlock()
+
+	for _, handler := range handlers {
+		(*handler)(event)
+	}
+}
@@ -0,0 +1,258 @@
+package go_persistent_ds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOpKind_String(t *testing.T) {
+	isTrue(t, OpSet.String() == "Set")
+	isTrue(t, OpPushFront.String() == "PushFront")
+	isTrue(t, OpMeld.String() == "Meld")
+	isTrue(t, OpReplace.String() == "Replace")
+	isTrue(t, OpKind(-1).String() == "Unknown")
+	isTrue(t, OpTruncate.String() == "Truncate")
+	isTrue(t, (OpTruncate+1).String() == "Unknown")
+}
+
+func TestSubscribe_Unsubscribe(t *testing.T) {
+	m, v0 := NewMap[string, int]()
+
+	var events []ChangeEvent
+	unsubscribe := m.Subscribe(func(ev ChangeEvent) {
+		events = append(events, ev)
+	})
+
+	var selfRemovingCalls int
+	var unsubscribeSelf func()
+	unsubscribeSelf = m.Subscribe(func(ChangeEvent) {
+		selfRemovingCalls++
+		unsubscribeSelf()
+	})
+
+	v1, err := m.Set(v0, "a", 1)
+	errIsNil(t, err)
+	v2, err := m.Set(v1, "a", 2)
+	errIsNil(t, err)
+
+	_, err = m.Get(v2, "a")
+	errIsNil(t, err)
+	_, err = m.ToGoMap(v2)
+	errIsNil(t, err)
+	_, err = m.Set(v2+1, "a", 3)
+	isTrue(t, err != nil)
+
+	isTrue(t, selfRemovingCalls == 1)
+	isTrue(t, reflect.DeepEqual(events, []ChangeEvent{
+		{Parent: v0, Version: v1, Op: OpSet, Key: "a", NewValue: 1},
+		{Parent: v1, Version: v2, Op: OpSet, Key: "a", OldValue: 1, NewValue: 2},
+	}))
+
+	unsubscribe()
+	unsubscribe()
+
+	_, err = m.Delete(v2, "a")
+	errIsNil(t, err)
+	isTrue(t, len(events) == 2)
+}
+
+// eventCase is a modification, that should notify subscribers with expected event.
+type eventCase struct {
+	subscribe func(handler func(ChangeEvent)) func()
+	modify    func(t *testing.T) (parent, version uint64)
+	expected  ChangeEvent
+}
+
+func TestSubscribe_Events(t *testing.T) {
+	cases := map[string]eventCase{}
+
+	slice, sv := NewSlice[int]()
+	sv, _ = slice.Append(sv, 1)
+	sv, _ = slice.Append(sv, 2)
+	other, ov := NewSlice[int](WithRRBTree())
+	ov, _ = other.Append(ov, 3)
+	cases["Slice.Set"] = eventCase{
+		subscribe: slice.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := slice.Set(sv, 1, 20)
+			errIsNil(t, err)
+			return sv, v
+		},
+		expected: ChangeEvent{Op: OpSet, Index: 1, OldValue: 2, NewValue: 20},
+	}
+	cases["Slice.Concat"] = eventCase{
+		subscribe: slice.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := slice.Concat(sv, other, ov)
+			errIsNil(t, err)
+			return sv, v
+		},
+		expected: ChangeEvent{Op: OpConcat, Index: 2, Count: 1, NewValue: []int{3}},
+	}
+	cases["Slice.Range"] = eventCase{
+		subscribe: slice.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := slice.Range(sv, 1, 2)
+			errIsNil(t, err)
+			return sv, v
+		},
+		expected: ChangeEvent{Op: OpRange, Index: 1, Count: 1},
+	}
+	cases["Slice.Prepend with RRB-tree"] = eventCase{
+		subscribe: other.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := other.Prepend(ov, 0)
+			errIsNil(t, err)
+			return ov, v
+		},
+		expected: ChangeEvent{Op: OpPrepend, Count: 1, NewValue: []int{0}},
+	}
+
+	l, lv := NewDoubleLinkedList[string]()
+	lv, _ = l.PushBack(lv, "a")
+	lv, _ = l.PushBack(lv, "c")
+	cases["DoubleLinkedList.InsertAt"] = eventCase{
+		subscribe: l.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := l.InsertAt(lv, 1, "b")
+			errIsNil(t, err)
+			return lv, v
+		},
+		expected: ChangeEvent{Op: OpInsert, Index: 1, NewValue: "b"},
+	}
+	cases["DoubleLinkedList.PopBack"] = eventCase{
+		subscribe: l.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			_, v, err := l.PopBack(lv)
+			errIsNil(t, err)
+			return lv, v
+		},
+		expected: ChangeEvent{Op: OpPopBack, Index: 1, OldValue: "c"},
+	}
+	cases["Cursor.Remove"] = eventCase{
+		subscribe: l.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			c, err := l.Back(lv)
+			errIsNil(t, err)
+			_, v, err := c.Remove()
+			errIsNil(t, err)
+			return lv, v
+		},
+		expected: ChangeEvent{Op: OpRemove, Index: 1, OldValue: "c"},
+	}
+
+	m, mv := NewMap[string, int](WithHAMT())
+	mv, _ = m.Set(mv, "a", 1)
+	cases["Map.Delete with HAMT"] = eventCase{
+		subscribe: m.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := m.Delete(mv, "a")
+			errIsNil(t, err)
+			return mv, v
+		},
+		expected: ChangeEvent{Op: OpDelete, Key: "a", OldValue: 1},
+	}
+
+	d, dv := NewDeque[int]()
+	dv, _ = d.PushBack(dv, 1)
+	cases["Deque.PushBack"] = eventCase{
+		subscribe: d.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := d.PushBack(dv, 2)
+			errIsNil(t, err)
+			return dv, v
+		},
+		expected: ChangeEvent{Op: OpPushBack, Index: 1, NewValue: 2},
+	}
+
+	st, stv := NewStack[int]()
+	stv, _ = st.Push(stv, 1)
+	cases["Stack.Pop"] = eventCase{
+		subscribe: st.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			_, v, err := st.Pop(stv)
+			errIsNil(t, err)
+			return stv, v
+		},
+		expected: ChangeEvent{Op: OpPop, Index: 0, OldValue: 1},
+	}
+
+	q, qv := NewQueue[int]()
+	qv, _ = q.Enqueue(qv, 1)
+	cases["Queue.Enqueue"] = eventCase{
+		subscribe: q.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := q.Enqueue(qv, 2)
+			errIsNil(t, err)
+			return qv, v
+		},
+		expected: ChangeEvent{Op: OpEnqueue, Index: 1, NewValue: 2},
+	}
+
+	pq, pqv := NewOrderedPriorityQueue[int]()
+	pqv, _ = pq.Push(pqv, 2)
+	pqv, _ = pq.Push(pqv, 1)
+	cases["PriorityQueue.PopMin"] = eventCase{
+		subscribe: pq.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			_, v, err := pq.PopMin(pqv)
+			errIsNil(t, err)
+			return pqv, v
+		},
+		expected: ChangeEvent{Op: OpPopMin, OldValue: 1},
+	}
+
+	tr, trv := NewTrie[int]()
+	trv, _ = tr.Set(trv, "key", 1)
+	cases["Trie.Set"] = eventCase{
+		subscribe: tr.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := tr.Set(trv, "key", 2)
+			errIsNil(t, err)
+			return trv, v
+		},
+		expected: ChangeEvent{Op: OpSet, Key: "key", OldValue: 1, NewValue: 2},
+	}
+
+	om, omv := NewOrderedMap[int, string]()
+	cases["OrderedMap.Set"] = eventCase{
+		subscribe: om.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := om.Set(omv, 1, "a")
+			errIsNil(t, err)
+			return omv, v
+		},
+		expected: ChangeEvent{Op: OpSet, Key: 1, NewValue: "a"},
+	}
+
+	r, rv := NewRopeFromString("hello, world")
+	cases["Rope.Delete"] = eventCase{
+		subscribe: r.Subscribe,
+		modify: func(t *testing.T) (uint64, uint64) {
+			v, err := r.Delete(rv, 5, 2)
+			errIsNil(t, err)
+			return rv, v
+		},
+		expected: ChangeEvent{Op: OpDelete, Index: 5, Count: 2, OldValue: ", "},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var events []ChangeEvent
+			unsubscribe := c.subscribe(func(ev ChangeEvent) {
+				events = append(events, ev)
+			})
+			defer unsubscribe()
+
+			parent, version := c.modify(t)
+
+			expected := c.expected
+			expected.Parent = parent
+			expected.Version = version
+			isTrue(t, len(events) == 1)
+			if len(events) == 1 && !reflect.DeepEqual(events[0], expected) {
+				t.Errorf("expected event: %+v, got: %+v", expected, events[0])
+			}
+		})
+	}
+}
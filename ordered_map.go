@@ -0,0 +1,743 @@
+package go_persistent_ds
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"slices"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// DefaultOrderedMapDegree is the degree of OrderedMap B-tree used by NewOrderedMap.
+const DefaultOrderedMapDegree = 32
+
+// ErrNotSorted is returned then values for bulk loading are not sorted by key or keys are not unique.
+var ErrNotSorted = errors.New("keys are not sorted")
+
+// OrderedMap is a persistent ordered map based on B-tree.
+// While working with map you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Each version keeps the root of immutable B-tree. Modification copies only the nodes on the path
+// to the changed key, so every version takes O(log(n)) new nodes and all other nodes are shared.
+// Every node stores the size of its subtree, so keys can be accessed by their position.
+//
+// B-tree of degree t stores from t-1 to 2t-1 keys in each node except root.
+//
+// OrderedMap can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing OrderedMap, the good idea is to use Compact method, that creates new OrderedMap
+// with special version as the initial one.
+//
+// Note that OrderedMap is not thread safe.
+type OrderedMap[TKey comparable, TVal any] struct {
+	versionHistory[orderedMapVersionInfo[TKey, TVal]]
+	compare func(a, b TKey) int
+	degree  int
+}
+
+type orderedMapVersionInfo[TKey comparable, TVal any] struct {
+	root *btreeNode[TKey, TVal]
+	hash unorderedHash
+}
+
+// btreeNode is a node of B-tree. Leaves have no children, inner nodes have len(keys)+1 children.
+// Nodes are never changed after they become visible from any version.
+type btreeNode[TKey comparable, TVal any] struct {
+	keys     []TKey
+	values   []TVal
+	children []*btreeNode[TKey, TVal]
+	// size is the amount of keys in the subtree.
+	size int
+}
+
+// NewOrderedMap creates empty OrderedMap with DefaultOrderedMapDegree.
+func NewOrderedMap[TKey cmp.Ordered, TVal any]() (*OrderedMap[TKey, TVal], uint64) {
+	return NewOrderedMapFunc[TKey, TVal](cmp.Compare[TKey], DefaultOrderedMapDegree)
+}
+
+// NewOrderedMapWithDegree creates empty OrderedMap with given B-tree degree.
+// Degree less than 2 is treated as 2.
+func NewOrderedMapWithDegree[TKey cmp.Ordered, TVal any](degree int) (*OrderedMap[TKey, TVal], uint64) {
+	return NewOrderedMapFunc[TKey, TVal](cmp.Compare[TKey], degree)
+}
+
+// NewOrderedMapFunc creates empty OrderedMap with keys ordered by compare and given B-tree degree.
+// Compare must return a negative number when a < b, a positive number when a > b and zero when a == b.
+// Degree less than 2 is treated as 2.
+func NewOrderedMapFunc[TKey comparable, TVal any](compare func(a, b TKey) int, degree int) (*OrderedMap[TKey, TVal], uint64) {
+	return &OrderedMap[TKey, TVal]{
+		versionHistory: newVersionHistory(internal.NewVersionTree[orderedMapVersionInfo[TKey, TVal]]()),
+		compare:        compare,
+		degree:         max(degree, 2),
+	}, 0
+}
+
+// NewOrderedMapFromSorted creates OrderedMap with given B-tree degree, which initial version contains
+// all pairs from seq. Keys in seq must be unique and sorted in ascending order, otherwise ErrNotSorted is returned.
+//
+// Complexity: O(n).
+func NewOrderedMapFromSorted[TKey cmp.Ordered, TVal any](
+	degree int,
+	seq iter.Seq2[TKey, TVal],
+) (*OrderedMap[TKey, TVal], uint64, error) {
+	m, version := NewOrderedMapWithDegree[TKey, TVal](degree)
+
+	var (
+		keys   []TKey
+		values []TVal
+		hash   unorderedHash
+	)
+	for k, v := range seq {
+		if len(keys) > 0 && m.compare(keys[len(keys)-1], k) >= 0 {
+			return nil, 0, ErrNotSorted
+		}
+
+		keys = append(keys, k)
+		values = append(values, v)
+		hash = hash.add(k, v)
+	}
+
+	_ = m.versionTree.SetVersionInfo(version, orderedMapVersionInfo[TKey, TVal]{
+		root: m.build(keys, values, m.heightFor(len(keys))),
+		hash: hash,
+	})
+
+	return m, version, nil
+}
+
+// Get returns a pair of value and error for provided version and key.
+// If error is nil then the value for such key and version exists.
+//
+// Complexity: O(t * log(n)), where t - degree of B-tree.
+func (m *OrderedMap[TKey, TVal]) Get(version uint64, key TKey) (TVal, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(TVal), err
+	}
+
+	node := info.root
+	for node != nil {
+		pos, found := m.search(node, key)
+		if found {
+			return node.values[pos], nil
+		}
+		if node.isLeaf() {
+			break
+		}
+		node = node.children[pos]
+	}
+
+	return *new(TVal), newKeyError(version, key)
+}
+
+// Set value for given key and version in OrderedMap. Returns OrderedMap's new version.
+//
+// Complexity: O(t * log(n)), where t - degree of B-tree.
+func (m *OrderedMap[TKey, TVal]) Set(version uint64, key TKey, val TVal) (uint64, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	newHash := info.hash.add(key, val)
+	event := ChangeEvent{Op: OpSet, Key: key, NewValue: val}
+	if oldVal, getErr := m.Get(version, key); getErr == nil {
+		newHash = newHash.remove(key, oldVal)
+		event.OldValue = oldVal
+	}
+
+	if info.root == nil {
+		return m.commit(version, newBTreeNode([]TKey{key}, []TVal{val}, nil), newHash, event)
+	}
+
+	root, split := m.insert(info.root, key, val)
+	if split != nil {
+		root = newBTreeNode(
+			[]TKey{split.key},
+			[]TVal{split.value},
+			[]*btreeNode[TKey, TVal]{root, split.right},
+		)
+	}
+
+	return m.commit(version, root, newHash, event)
+}
+
+// Delete the value from OrderedMap for given key for given version. Returns OrderedMap's new version.
+// If there is no such key ErrNotFound is returned.
+//
+// Complexity: O(t * log(n)), where t - degree of B-tree.
+func (m *OrderedMap[TKey, TVal]) Delete(version uint64, key TKey) (uint64, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	oldVal, err := m.Get(version, key)
+	if err != nil {
+		return 0, err
+	}
+
+	root, _ := m.delete(info.root, key)
+
+	switch {
+	case root.size == 0:
+		root = nil
+	case len(root.keys) == 0:
+		root = root.children[0]
+	}
+
+	return m.commit(version, root, info.hash.remove(key, oldVal), ChangeEvent{Op: OpDelete, Key: key, OldValue: oldVal})
+}
+
+// Len returns the amount of keys in OrderedMap.
+//
+// Complexity: O(1).
+func (m *OrderedMap[TKey, TVal]) Len(version uint64) (int, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.root.treeSize(), nil
+}
+
+// IndexOf returns the position of the key among all keys of given version in ascending order.
+// If there is no such key ErrNotFound is returned.
+//
+// Complexity: O(t * log(n)), where t - degree of B-tree.
+func (m *OrderedMap[TKey, TVal]) IndexOf(version uint64, key TKey) (int, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	index := 0
+	node := info.root
+	for node != nil {
+		pos, found := m.search(node, key)
+
+		index += pos
+		if !node.isLeaf() {
+			for _, child := range node.children[:pos] {
+				index += child.size
+			}
+		}
+
+		if found {
+			if !node.isLeaf() {
+				index += node.children[pos].size
+			}
+			return index, nil
+		}
+		if node.isLeaf() {
+			break
+		}
+		node = node.children[pos]
+	}
+
+	return 0, newKeyError(version, key)
+}
+
+// At returns the key and the value by their position among all keys of given version in ascending order.
+//
+// Complexity: O(t * log(n)), where t - degree of B-tree.
+func (m *OrderedMap[TKey, TVal]) At(version uint64, index int) (TKey, TVal, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(TKey), *new(TVal), err
+	}
+	if index < 0 || index >= info.root.treeSize() {
+		return *new(TKey), *new(TVal), newIndexError(version, index, info.root.treeSize())
+	}
+
+	node := info.root
+	for !node.isLeaf() {
+		next := -1
+		for i, child := range node.children {
+			if index < child.size {
+				next = i
+				break
+			}
+			index -= child.size
+
+			if i < len(node.keys) {
+				if index == 0 {
+					return node.keys[i], node.values[i], nil
+				}
+				index--
+			}
+		}
+		node = node.children[next]
+	}
+
+	return node.keys[index], node.values[index], nil
+}
+
+// All returns iterator over all pairs of given version in ascending order of keys.
+//
+// Complexity: O(n).
+func (m *OrderedMap[TKey, TVal]) All(version uint64) (iter.Seq2[TKey, TVal], error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(TKey, TVal) bool) {
+		m.ascend(info.root, nil, nil, yield)
+	}, nil
+}
+
+// Range returns iterator over pairs of given version with keys from `from` (inclusive) to `to` (not inclusive)
+// in ascending order of keys.
+//
+// Complexity: O(t * log(n) + p), where t - degree of B-tree and p - amount of iterated pairs.
+func (m *OrderedMap[TKey, TVal]) Range(version uint64, from, to TKey) (iter.Seq2[TKey, TVal], error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(TKey, TVal) bool) {
+		m.ascend(info.root, &from, &to, yield)
+	}, nil
+}
+
+// ToGoMap converts persistent OrderedMap for specified version into go map.
+//
+// Complexity: O(n).
+func (m *OrderedMap[TKey, TVal]) ToGoMap(version uint64) (map[TKey]TVal, error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	resMap := make(map[TKey]TVal, info.root.treeSize())
+	m.ascend(info.root, nil, nil, func(k TKey, v TVal) bool {
+		resMap[k] = v
+		return true
+	})
+
+	return resMap, nil
+}
+
+// Equal reports whether versions v1 and v2 of OrderedMap contain equal keys and values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n).
+func (m *OrderedMap[TKey, TVal]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := m.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := m.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.root.treeSize() != info2.root.treeSize() || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalMaps(m.ToGoMap, v1, v2)
+}
+
+// ContentHash returns hash of keys and values of OrderedMap of given version, that is maintained on each modification.
+// Versions with equal keys and values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (m *OrderedMap[TKey, TVal]) ContentHash(version uint64) (uint64, bool) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new OrderedMap, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (m *OrderedMap[TKey, TVal]) Compact(version uint64, opts ...CompactOption) (*OrderedMap[TKey, TVal], error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewOrderedMapFunc[TKey, TVal](m.compare, m.degree)
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = m.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new OrderedMap, which initial version shares content of given version without copying it.
+// Modifications of the new OrderedMap don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (m *OrderedMap[TKey, TVal]) Fork(version uint64) (*OrderedMap[TKey, TVal], error) {
+	info, err := m.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewOrderedMapFunc[TKey, TVal](m.compare, m.degree)
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+func (m *OrderedMap[TKey, TVal]) commit(
+	version uint64,
+	root *btreeNode[TKey, TVal],
+	hash unorderedHash,
+	event ChangeEvent,
+) (uint64, error) {
+	newVersion, err := m.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = m.versionTree.SetVersionInfo(newVersion, orderedMapVersionInfo[TKey, TVal]{
+		root: root,
+		hash: hash,
+	})
+
+	m.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
+
+func (m *OrderedMap[TKey, TVal]) maxKeys() int {
+	return 2*m.degree - 1
+}
+
+func (m *OrderedMap[TKey, TVal]) minKeys() int {
+	return m.degree - 1
+}
+
+// search finds position of the key inside the node. If the key is absent, position of the child,
+// which may contain the key, is returned.
+func (m *OrderedMap[TKey, TVal]) search(node *btreeNode[TKey, TVal], key TKey) (int, bool) {
+	return slices.BinarySearchFunc(node.keys, key, m.compare)
+}
+
+// btreeSplit describes the right half of split node and the key that separates halves.
+type btreeSplit[TKey comparable, TVal any] struct {
+	key   TKey
+	value TVal
+	right *btreeNode[TKey, TVal]
+}
+
+// insert returns copy of the node with the key set. If the node overflows it is split,
+// so the left half is returned and the split is reported.
+func (m *OrderedMap[TKey, TVal]) insert(
+	node *btreeNode[TKey, TVal],
+	key TKey,
+	val TVal,
+) (*btreeNode[TKey, TVal], *btreeSplit[TKey, TVal]) {
+	pos, found := m.search(node, key)
+
+	c := node.clone()
+	switch {
+	case found:
+		c.values[pos] = val
+		return c, nil
+	case node.isLeaf():
+		c.keys = slices.Insert(c.keys, pos, key)
+		c.values = slices.Insert(c.values, pos, val)
+	default:
+		child, split := m.insert(node.children[pos], key, val)
+		c.children[pos] = child
+		if split != nil {
+			c.keys = slices.Insert(c.keys, pos, split.key)
+			c.values = slices.Insert(c.values, pos, split.value)
+			c.children = slices.Insert(c.children, pos+1, split.right)
+		}
+	}
+
+	if len(c.keys) <= m.maxKeys() {
+		c.updateSize()
+		return c, nil
+	}
+
+	mid := len(c.keys) / 2
+	left := newBTreeNode(c.keys[:mid:mid], c.values[:mid:mid], nil)
+	right := newBTreeNode(c.keys[mid+1:], c.values[mid+1:], nil)
+	if !c.isLeaf() {
+		left = newBTreeNode(left.keys, left.values, c.children[:mid+1:mid+1])
+		right = newBTreeNode(right.keys, right.values, c.children[mid+1:])
+	}
+
+	return left, &btreeSplit[TKey, TVal]{
+		key:   c.keys[mid],
+		value: c.values[mid],
+		right: right,
+	}
+}
+
+// delete returns copy of the node without the key. Returned node may have less than minimal amount of keys.
+func (m *OrderedMap[TKey, TVal]) delete(node *btreeNode[TKey, TVal], key TKey) (*btreeNode[TKey, TVal], bool) {
+	if node == nil {
+		return nil, false
+	}
+
+	pos, found := m.search(node, key)
+
+	switch {
+	case found && node.isLeaf():
+		c := node.clone()
+		c.keys = slices.Delete(c.keys, pos, pos+1)
+		c.values = slices.Delete(c.values, pos, pos+1)
+		c.updateSize()
+		return c, true
+	case found:
+		// replace the key with the greatest key of the left subtree
+		child, maxKey, maxVal := m.deleteMax(node.children[pos])
+		c := node.clone()
+		c.keys[pos], c.values[pos] = maxKey, maxVal
+		c.children[pos] = child
+		m.fixChild(c, pos)
+		return c, true
+	case node.isLeaf():
+		return node, false
+	default:
+		child, removed := m.delete(node.children[pos], key)
+		if !removed {
+			return node, false
+		}
+
+		c := node.clone()
+		c.children[pos] = child
+		m.fixChild(c, pos)
+		return c, true
+	}
+}
+
+// deleteMax returns copy of the node without its greatest key and the removed pair.
+func (m *OrderedMap[TKey, TVal]) deleteMax(node *btreeNode[TKey, TVal]) (*btreeNode[TKey, TVal], TKey, TVal) {
+	c := node.clone()
+
+	if node.isLeaf() {
+		last := len(c.keys) - 1
+		key, val := c.keys[last], c.values[last]
+		c.keys, c.values = c.keys[:last], c.values[:last]
+		c.updateSize()
+		return c, key, val
+	}
+
+	last := len(c.children) - 1
+	child, key, val := m.deleteMax(c.children[last])
+	c.children[last] = child
+	m.fixChild(c, last)
+	return c, key, val
+}
+
+// fixChild restores amount of keys in the child of the node, which must be a copy, by borrowing a key
+// from a sibling or merging with it. Also updates size of the node.
+func (m *OrderedMap[TKey, TVal]) fixChild(node *btreeNode[TKey, TVal], pos int) {
+	defer node.updateSize()
+
+	child := node.children[pos]
+	if len(child.keys) >= m.minKeys() {
+		return
+	}
+
+	if pos > 0 && len(node.children[pos-1].keys) > m.minKeys() {
+		left, right := node.children[pos-1].clone(), child.clone()
+
+		last := len(left.keys) - 1
+		right.keys = slices.Insert(right.keys, 0, node.keys[pos-1])
+		right.values = slices.Insert(right.values, 0, node.values[pos-1])
+		node.keys[pos-1], node.values[pos-1] = left.keys[last], left.values[last]
+		left.keys, left.values = left.keys[:last], left.values[:last]
+		if !left.isLeaf() {
+			right.children = slices.Insert(right.children, 0, left.children[last+1])
+			left.children = left.children[:last+1]
+		}
+
+		left.updateSize()
+		right.updateSize()
+		node.children[pos-1], node.children[pos] = left, right
+		return
+	}
+
+	if pos < len(node.keys) && len(node.children[pos+1].keys) > m.minKeys() {
+		left, right := child.clone(), node.children[pos+1].clone()
+
+		left.keys = append(left.keys, node.keys[pos])
+		left.values = append(left.values, node.values[pos])
+		node.keys[pos], node.values[pos] = right.keys[0], right.values[0]
+		right.keys, right.values = right.keys[1:], right.values[1:]
+		if !right.isLeaf() {
+			left.children = append(left.children, right.children[0])
+			right.children = right.children[1:]
+		}
+
+		left.updateSize()
+		right.updateSize()
+		node.children[pos], node.children[pos+1] = left, right
+		return
+	}
+
+	// merge the child with one of its siblings and separating key
+	if pos == len(node.keys) {
+		pos--
+	}
+	left, right := node.children[pos], node.children[pos+1]
+
+	merged := &btreeNode[TKey, TVal]{
+		keys:   slices.Concat(left.keys, []TKey{node.keys[pos]}, right.keys),
+		values: slices.Concat(left.values, []TVal{node.values[pos]}, right.values),
+	}
+	if !left.isLeaf() {
+		merged.children = slices.Concat(left.children, right.children)
+	}
+	merged.updateSize()
+
+	node.keys = slices.Delete(node.keys, pos, pos+1)
+	node.values = slices.Delete(node.values, pos, pos+1)
+	node.children = slices.Delete(node.children, pos+1, pos+2)
+	node.children[pos] = merged
+}
+
+// ascend calls yield for pairs of the subtree with keys in [from, to) in ascending order.
+// Nil bound means no bound. Returns false if yield asked to stop.
+func (m *OrderedMap[TKey, TVal]) ascend(node *btreeNode[TKey, TVal], from, to *TKey, yield func(TKey, TVal) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	start := 0
+	if from != nil {
+		start, _ = m.search(node, *from)
+	}
+
+	for i := start; i <= len(node.keys); i++ {
+		if !node.isLeaf() && !m.ascend(node.children[i], from, to, yield) {
+			return false
+		}
+		if i == len(node.keys) {
+			break
+		}
+		if to != nil && m.compare(node.keys[i], *to) >= 0 {
+			return false
+		}
+		if !yield(node.keys[i], node.values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// heightFor returns the height of B-tree, that bulk loading builds for n keys.
+func (m *OrderedMap[TKey, TVal]) heightFor(n int) int {
+	height, capacity := 0, m.maxKeys()
+	for capacity < n {
+		height++
+		capacity = capacity*2*m.degree + m.maxKeys()
+	}
+
+	return height
+}
+
+// capacityFor returns the maximal amount of keys in B-tree of given height.
+func (m *OrderedMap[TKey, TVal]) capacityFor(height int) int {
+	capacity := m.maxKeys()
+	for i := 0; i < height; i++ {
+		capacity = capacity*2*m.degree + m.maxKeys()
+	}
+
+	return capacity
+}
+
+// build creates B-tree of given height with sorted keys, keys are distributed evenly between subtrees.
+func (m *OrderedMap[TKey, TVal]) build(keys []TKey, values []TVal, height int) *btreeNode[TKey, TVal] {
+	if len(keys) == 0 {
+		return nil
+	}
+	if height == 0 {
+		return newBTreeNode(keys, values, nil)
+	}
+
+	childCapacity := m.capacityFor(height - 1)
+	childrenCount := max(2, (len(keys)+1+childCapacity)/(childCapacity+1))
+
+	node := &btreeNode[TKey, TVal]{}
+	perChild, extra := (len(keys)-childrenCount+1)/childrenCount, (len(keys)-childrenCount+1)%childrenCount
+
+	start := 0
+	for i := 0; i < childrenCount; i++ {
+		end := start + perChild
+		if i < extra {
+			end++
+		}
+
+		node.children = append(node.children, m.build(keys[start:end:end], values[start:end:end], height-1))
+		if i < childrenCount-1 {
+			node.keys = append(node.keys, keys[end])
+			node.values = append(node.values, values[end])
+		}
+		start = end + 1
+	}
+	node.updateSize()
+
+	return node
+}
+
+func newBTreeNode[TKey comparable, TVal any](
+	keys []TKey,
+	values []TVal,
+	children []*btreeNode[TKey, TVal],
+) *btreeNode[TKey, TVal] {
+	node := &btreeNode[TKey, TVal]{
+		keys:     keys,
+		values:   values,
+		children: children,
+	}
+	node.updateSize()
+
+	return node
+}
+
+func (n *btreeNode[TKey, TVal]) isLeaf() bool {
+	return len(n.children) == 0
+}
+
+func (n *btreeNode[TKey, TVal]) treeSize() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+func (n *btreeNode[TKey, TVal]) updateSize() {
+	n.size = len(n.keys)
+	for _, child := range n.children {
+		n.size += child.size
+	}
+}
+
+// clone returns copy of the node, that can be changed without affecting the node.
+func (n *btreeNode[TKey, TVal]) clone() *btreeNode[TKey, TVal] {
+	return &btreeNode[TKey, TVal]{
+		keys:     slices.Clone(n.keys),
+		values:   slices.Clone(n.values),
+		children: slices.Clone(n.children),
+		size:     n.size,
+	}
+}
@@ -0,0 +1,252 @@
+package go_persistent_ds
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkBTree checks B-tree invariants and returns the height of the subtree.
+func checkBTree[TKey comparable, TVal any](
+	t *testing.T,
+	m *OrderedMap[TKey, TVal],
+	node *btreeNode[TKey, TVal],
+	isRoot bool,
+) int {
+	t.Helper()
+
+	if node == nil {
+		return 0
+	}
+
+	if len(node.keys) > m.maxKeys() || (!isRoot && len(node.keys) < m.minKeys()) {
+		t.Fatalf("node has %d keys, but degree is %d", len(node.keys), m.degree)
+	}
+	if !slices.IsSortedFunc(node.keys, m.compare) {
+		t.Fatal("node keys are not sorted")
+	}
+
+	size := len(node.keys)
+	height := -1
+	for _, child := range node.children {
+		childHeight := checkBTree(t, m, child, false)
+		if height != -1 && height != childHeight {
+			t.Fatal("leaves have different depth")
+		}
+		height = childHeight
+		size += child.size
+	}
+	if !node.isLeaf() && len(node.children) != len(node.keys)+1 {
+		t.Fatal("wrong amount of children")
+	}
+	if size != node.size {
+		t.Fatalf("expected node size %d, got %d", size, node.size)
+	}
+
+	return height + 1
+}
+
+func collectPairs[TKey comparable, TVal any](t *testing.T, seq func(func(TKey, TVal) bool)) []TKey {
+	t.Helper()
+
+	var keys []TKey
+	for k := range seq {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+func TestOrderedMap_GetSetDelete(t *testing.T) {
+	m, version := NewOrderedMapWithDegree[string, int](2)
+
+	_, err := m.Get(version, "a")
+	errShouldBe(t, err, ErrNotFound)
+	_, err = m.Delete(version, "a")
+	errShouldBe(t, err, ErrNotFound)
+
+	keys := strings.Split("q w e r t y u i o p a s d f g h j k l z x c v b n m", " ")
+	for i, key := range keys {
+		version, err = m.Set(version, key, i)
+		errIsNil(t, err)
+	}
+	filledVersion := version
+
+	for i, key := range keys {
+		val, err := m.Get(filledVersion, key)
+		errIsNil(t, err)
+		isTrue(t, val == i)
+	}
+
+	size, err := m.Len(filledVersion)
+	errIsNil(t, err)
+	isTrue(t, size == len(keys))
+
+	version, err = m.Set(filledVersion, "q", 100)
+	errIsNil(t, err)
+	size, err = m.Len(version)
+	errIsNil(t, err)
+	isTrue(t, size == len(keys))
+
+	for _, key := range keys[:20] {
+		version, err = m.Delete(version, key)
+		errIsNil(t, err)
+
+		info, err := m.versionTree.GetVersionInfo(version)
+		errIsNil(t, err)
+		checkBTree(t, m, info.root, true)
+	}
+
+	goMap, err := m.ToGoMap(version)
+	errIsNil(t, err)
+	isTrue(t, maps.Equal(goMap, map[string]int{"x": 20, "c": 21, "v": 22, "b": 23, "n": 24, "m": 25}))
+
+	val, err := m.Get(filledVersion, "q")
+	errIsNil(t, err)
+	isTrue(t, val == 0)
+}
+
+func TestOrderedMap_RankAndRange(t *testing.T) {
+	m, version := NewOrderedMapWithDegree[int, string](3)
+
+	var err error
+	for _, key := range rand.New(rand.NewSource(1)).Perm(100) {
+		version, err = m.Set(version, key*2, "")
+		errIsNil(t, err)
+	}
+
+	for i := 0; i < 100; i++ {
+		index, err := m.IndexOf(version, i*2)
+		errIsNil(t, err)
+		isTrue(t, index == i)
+
+		key, _, err := m.At(version, i)
+		errIsNil(t, err)
+		isTrue(t, key == i*2)
+	}
+
+	_, err = m.IndexOf(version, 3)
+	errShouldBe(t, err, ErrNotFound)
+	_, _, err = m.At(version, 100)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+
+	seq, err := m.Range(version, 11, 20)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(collectPairs(t, seq), []int{12, 14, 16, 18}))
+
+	seq, err = m.Range(version, 190, 1000)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(collectPairs(t, seq), []int{190, 192, 194, 196, 198}))
+
+	seq, err = m.All(version)
+	errIsNil(t, err)
+	all := collectPairs(t, seq)
+	isTrue(t, len(all) == 100 && slices.IsSorted(all))
+
+	seq, err = m.All(0)
+	errIsNil(t, err)
+	isTrue(t, len(collectPairs(t, seq)) == 0)
+}
+
+func TestOrderedMap_FromSorted(t *testing.T) {
+	t.Run("Not sorted", func(t *testing.T) {
+		t.Parallel()
+
+		pairs := func(keys ...int) func(func(int, int) bool) {
+			return func(yield func(int, int) bool) {
+				for _, key := range keys {
+					if !yield(key, key) {
+						return
+					}
+				}
+			}
+		}
+
+		_, _, err := NewOrderedMapFromSorted(2, pairs(1, 3, 2))
+		errShouldBe(t, err, ErrNotSorted)
+
+		_, _, err = NewOrderedMapFromSorted(2, pairs(1, 1))
+		errShouldBe(t, err, ErrNotSorted)
+	})
+
+	t.Run("Different sizes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, degree := range []int{2, 3, 8} {
+			for n := 0; n < 300; n += 7 {
+				values := make([]int, n)
+				for i := range values {
+					values[i] = i * 10
+				}
+
+				// keys are indexes of values
+
+				m, version, err := NewOrderedMapFromSorted(degree, slices.All(values))
+				errIsNil(t, err)
+
+				info, err := m.versionTree.GetVersionInfo(version)
+				errIsNil(t, err)
+				checkBTree(t, m, info.root, true)
+
+				for i, val := range values {
+					got, err := m.Get(version, i)
+					errIsNil(t, err)
+					isTrue(t, got == val)
+				}
+
+				version, err = m.Set(version, n, 0)
+				errIsNil(t, err)
+				size, err := m.Len(version)
+				errIsNil(t, err)
+				isTrue(t, size == n+1)
+			}
+		}
+	})
+}
+
+func TestOrderedMap_RandomOperations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	m, version := NewOrderedMapFunc[int, int](func(a, b int) int { return b - a }, 2)
+
+	models := map[uint64]map[int]int{version: {}}
+	versions := []uint64{version}
+
+	for i := 0; i < 3000; i++ {
+		from := versions[rnd.Intn(len(versions))]
+		model := maps.Clone(models[from])
+		key := rnd.Intn(60)
+
+		var err error
+		if _, exists := model[key]; exists && rnd.Intn(2) == 0 {
+			version, err = m.Delete(from, key)
+			delete(model, key)
+		} else {
+			version, err = m.Set(from, key, i)
+			model[key] = i
+		}
+		errIsNil(t, err)
+
+		models[version] = model
+		versions = append(versions, version)
+	}
+
+	for v, model := range models {
+		info, err := m.versionTree.GetVersionInfo(v)
+		errIsNil(t, err)
+		checkBTree(t, m, info.root, true)
+
+		goMap, err := m.ToGoMap(v)
+		errIsNil(t, err)
+		isTrue(t, maps.Equal(goMap, model))
+
+		keys := slices.Sorted(maps.Keys(model))
+		slices.Reverse(keys)
+		for i, key := range keys {
+			index, err := m.IndexOf(v, key)
+			errIsNil(t, err)
+			isTrue(t, index == i)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package go_persistent_ds
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// PriorityQueue is a persistent min-priority queue.
+// While working with priority queue you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Each version keeps immutable leftist heap, heaps of different versions share structure.
+//
+// PriorityQueue can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing PriorityQueue, the good idea is to use Compact method, that creates new PriorityQueue
+// with special version as the initial one.
+//
+// Note that PriorityQueue is not thread safe.
+type PriorityQueue[T any] struct {
+	versionHistory[priorityQueueVersionInfo[T]]
+	less func(a, b T) bool
+}
+
+type priorityQueueVersionInfo[T any] struct {
+	heap internal.LeftistHeap[T]
+	hash unorderedHash
+}
+
+// NewPriorityQueue creates empty PriorityQueue, that pops values in order defined by less.
+func NewPriorityQueue[T any](less func(a, b T) bool) (*PriorityQueue[T], uint64) {
+	pq := &PriorityQueue[T]{
+		versionHistory: newVersionHistory(internal.NewVersionTree[priorityQueueVersionInfo[T]]()),
+		less:           less,
+	}
+
+	_ = pq.versionTree.SetVersionInfo(0, priorityQueueVersionInfo[T]{
+		heap: internal.NewLeftistHeap(less),
+	})
+
+	return pq, 0
+}
+
+// NewOrderedPriorityQueue creates empty PriorityQueue, that pops values in ascending order.
+func NewOrderedPriorityQueue[T cmp.Ordered]() (*PriorityQueue[T], uint64) {
+	return NewPriorityQueue(cmp.Less[T])
+}
+
+// Push adds the value to PriorityQueue of given version. Returns PriorityQueue's new version.
+//
+// Complexity: O(log(n)).
+func (pq *PriorityQueue[T]) Push(version uint64, val T) (uint64, error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return pq.commit(version, priorityQueueVersionInfo[T]{
+		heap: info.heap.Push(val),
+		hash: info.hash.add(val),
+	}, ChangeEvent{Op: OpPush, NewValue: val})
+}
+
+// PopMin removes the minimal value from PriorityQueue of given version.
+// Returns removed value and PriorityQueue's new version.
+// If PriorityQueue is empty ErrEmpty is returned.
+//
+// Complexity: O(log(n)).
+func (pq *PriorityQueue[T]) PopMin(version uint64) (T, uint64, error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	val, ok := info.heap.Min()
+	if !ok {
+		return *new(T), 0, ErrEmpty
+	}
+
+	newVersion, err := pq.commit(version, priorityQueueVersionInfo[T]{
+		heap: info.heap.PopMin(),
+		hash: info.hash.remove(val),
+	}, ChangeEvent{Op: OpPopMin, OldValue: val})
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// PeekMin returns the minimal value from PriorityQueue of given version.
+// If PriorityQueue is empty ErrEmpty is returned.
+//
+// Complexity: O(1).
+func (pq *PriorityQueue[T]) PeekMin(version uint64) (T, error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), err
+	}
+
+	val, ok := info.heap.Min()
+	if !ok {
+		return *new(T), ErrEmpty
+	}
+
+	return val, nil
+}
+
+// Len returns the len of PriorityQueue.
+//
+// Complexity: O(1).
+func (pq *PriorityQueue[T]) Len(version uint64) (int, error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.heap.Len(), nil
+}
+
+// Meld creates new version from version, that contains values of both version and otherVersion.
+// New version is a child of version. Returns PriorityQueue's new version.
+//
+// Complexity: O(log(n)).
+func (pq *PriorityQueue[T]) Meld(version, otherVersion uint64) (uint64, error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	otherInfo, err := pq.versionTree.GetVersionInfo(otherVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	return pq.commit(version, priorityQueueVersionInfo[T]{
+		heap: info.heap.Merge(otherInfo.heap),
+		hash: info.hash.merge(otherInfo.hash),
+	}, ChangeEvent{Op: OpMeld, NewValue: otherVersion})
+}
+
+// ToGoSlice converts persistent PriorityQueue for specified version into go slice sorted by priority.
+//
+// Complexity: O(n * log(n)).
+func (pq *PriorityQueue[T]) ToGoSlice(version uint64) ([]T, error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	values := info.heap.Values()
+	slices.SortStableFunc(values, func(a, b T) int {
+		switch {
+		case pq.less(a, b):
+			return -1
+		case pq.less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return values, nil
+}
+
+// Equal reports whether versions v1 and v2 of PriorityQueue contain the same values regardless of their order.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n * log(n) + k^2),
+// where k - the maximal amount of values with equal priority.
+func (pq *PriorityQueue[T]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := pq.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := pq.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.heap.Len() != info2.heap.Len() || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	values1, _ := pq.ToGoSlice(v1)
+	values2, _ := pq.ToGoSlice(v2)
+
+	return pq.equalSorted(values1, values2), nil
+}
+
+// ContentHash returns hash of values of PriorityQueue of given version, that is maintained on each modification.
+// Versions with the same values have equal hashes regardless of the order, in which values were pushed.
+// Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (pq *PriorityQueue[T]) ContentHash(version uint64) (uint64, bool) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new PriorityQueue, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (pq *PriorityQueue[T]) Compact(version uint64, opts ...CompactOption) (*PriorityQueue[T], error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewPriorityQueue(pq.less)
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = pq.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new PriorityQueue, which initial version shares content of given version without copying it.
+// Modifications of the new PriorityQueue don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (pq *PriorityQueue[T]) Fork(version uint64) (*PriorityQueue[T], error) {
+	info, err := pq.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewPriorityQueue(pq.less)
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+// equalSorted reports whether slices of the same size sorted by priority contain the same values.
+// Values with equal priority may be placed in any order, so groups of them are matched regardless of order.
+func (pq *PriorityQueue[T]) equalSorted(values1, values2 []T) bool {
+	for start := 0; start < len(values1); {
+		end := start + 1
+		for end < len(values1) && !pq.less(values1[start], values1[end]) {
+			end++
+		}
+
+		for i := start; i < end; i++ {
+			if pq.less(values1[start], values2[i]) || pq.less(values2[i], values1[start]) {
+				return false
+			}
+		}
+		if end < len(values2) && !pq.less(values1[start], values2[end]) {
+			return false
+		}
+
+		if !matchValues(values1[start:end], values2[start:end]) {
+			return false
+		}
+
+		start = end
+	}
+
+	return true
+}
+
+// matchValues reports whether values1 and values2 contain the same values regardless of order.
+func matchValues[T any](values1, values2 []T) bool {
+	matched := make([]bool, len(values2))
+	for _, val := range values1 {
+		found := false
+		for i, other := range values2 {
+			if !matched[i] && valuesEqual(val, other) {
+				matched[i], found = true, true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (pq *PriorityQueue[T]) commit(version uint64, info priorityQueueVersionInfo[T], event ChangeEvent) (uint64, error) {
+	newVersion, err := pq.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = pq.versionTree.SetVersionInfo(newVersion, info)
+
+	pq.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
@@ -0,0 +1,90 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPriorityQueue_PushAndPopMin(t *testing.T) {
+	pq, initialVersion := NewOrderedPriorityQueue[int]()
+	versionShouldBe(t, initialVersion, 0)
+
+	_, _, err := pq.PopMin(0)
+	errShouldBe(t, err, ErrEmpty)
+	_, err = pq.PeekMin(0)
+	errShouldBe(t, err, ErrEmpty)
+
+	version := initialVersion
+	for _, val := range []int{5, 2, 8, 1, 9} {
+		version, err = pq.Push(version, val)
+		errIsNil(t, err)
+	}
+	pushedVersion := version
+
+	minVal, err := pq.PeekMin(version)
+	errIsNil(t, err)
+	isTrue(t, minVal == 1)
+
+	var popped []int
+	for {
+		var val int
+		val, version, err = pq.PopMin(version)
+		if err != nil {
+			errShouldBe(t, err, ErrEmpty)
+			break
+		}
+		popped = append(popped, val)
+	}
+	isTrue(t, slices.Equal(popped, []int{1, 2, 5, 8, 9}))
+
+	size, err := pq.Len(pushedVersion)
+	errIsNil(t, err)
+	isTrue(t, size == 5)
+
+	slice, err := pq.ToGoSlice(pushedVersion)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []int{1, 2, 5, 8, 9}))
+}
+
+func TestPriorityQueue_Meld(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+
+	pq, version := NewPriorityQueue(func(a, b task) bool {
+		return a.priority > b.priority
+	})
+
+	branchA, err := pq.Push(version, task{name: "a", priority: 1})
+	errIsNil(t, err)
+	branchA, err = pq.Push(branchA, task{name: "b", priority: 5})
+	errIsNil(t, err)
+
+	branchB, err := pq.Push(version, task{name: "c", priority: 3})
+	errIsNil(t, err)
+	branchB, err = pq.Push(branchB, task{name: "d", priority: 7})
+	errIsNil(t, err)
+
+	melded, err := pq.Meld(branchA, branchB)
+	errIsNil(t, err)
+
+	size, err := pq.Len(melded)
+	errIsNil(t, err)
+	isTrue(t, size == 4)
+
+	slice, err := pq.ToGoSlice(melded)
+	errIsNil(t, err)
+	names := make([]string, 0, len(slice))
+	for _, tsk := range slice {
+		names = append(names, tsk.name)
+	}
+	isTrue(t, slices.Equal(names, []string{"d", "b", "c", "a"}))
+
+	top, err := pq.PeekMin(branchA)
+	errIsNil(t, err)
+	isTrue(t, top.name == "b")
+
+	_, err = pq.Meld(branchA, 100)
+	isTrue(t, err != nil)
+}
@@ -0,0 +1,215 @@
+package go_persistent_ds
+
+import (
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// Queue is a persistent FIFO queue.
+// While working with queue you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Queue is stored as immutable real-time queue: the end of Queue is kept in reversed list, which is moved
+// to the front lazily, a constant amount of values on each modification. The moved values are shared
+// by all versions, so every operation takes O(1) on any version, however many times it is modified.
+//
+// Queue can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Queue, the good idea is to use Compact method, that creates new Queue
+// with special version as the initial one.
+//
+// Note that Queue is not thread safe.
+type Queue[T any] struct {
+	versionHistory[queueVersionInfo[T]]
+}
+
+type queueVersionInfo[T any] struct {
+	values internal.RealTimeQueue[T]
+	hash   sequenceHash
+}
+
+// NewQueue creates empty Queue.
+func NewQueue[T any]() (*Queue[T], uint64) {
+	return &Queue[T]{
+		versionHistory: newVersionHistory(internal.NewVersionTree[queueVersionInfo[T]]()),
+	}, 0
+}
+
+// NewQueueWithAnyValues creates a Queue, that can store values of any type.
+func NewQueueWithAnyValues() (*Queue[any], uint64) {
+	return NewQueue[any]()
+}
+
+// Enqueue adds the value to the end of Queue of given version. Returns Queue's new version.
+//
+// Complexity: O(1).
+func (q *Queue[T]) Enqueue(version uint64, val T) (uint64, error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return q.commit(version, queueVersionInfo[T]{
+		values: info.values.Snoc(val),
+		hash:   info.hash.pushBack(val),
+	}, ChangeEvent{Op: OpEnqueue, Index: info.values.Len(), NewValue: val})
+}
+
+// Dequeue removes the value from the head of Queue of given version.
+// Returns removed value and Queue's new version.
+// If Queue is empty ErrEmpty is returned.
+//
+// Complexity: O(1).
+func (q *Queue[T]) Dequeue(version uint64) (T, uint64, error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	val, ok := info.values.Head()
+	if !ok {
+		return *new(T), 0, ErrEmpty
+	}
+
+	newVersion, err := q.commit(version, queueVersionInfo[T]{
+		values: info.values.Tail(),
+		hash:   info.hash.popFront(val),
+	}, ChangeEvent{Op: OpDequeue, OldValue: val})
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// Peek returns the value from the head of Queue of given version.
+// If Queue is empty ErrEmpty is returned.
+//
+// Complexity: O(1).
+func (q *Queue[T]) Peek(version uint64) (T, error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), err
+	}
+
+	val, ok := info.values.Head()
+	if !ok {
+		return *new(T), ErrEmpty
+	}
+
+	return val, nil
+}
+
+// Len returns the len of Queue.
+//
+// Complexity: O(1).
+func (q *Queue[T]) Len(version uint64) (int, error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.values.Len(), nil
+}
+
+// ToGoSlice converts persistent Queue for specified version into go slice. Head of Queue becomes the first element.
+//
+// Complexity: O(n).
+func (q *Queue[T]) ToGoSlice(version uint64) ([]T, error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.values.Values(), nil
+}
+
+// Equal reports whether versions v1 and v2 of Queue contain equal values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n).
+func (q *Queue[T]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := q.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := q.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.values.Len() != info2.values.Len() || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalSequences(q.ToGoSlice, v1, v2)
+}
+
+// ContentHash returns hash of values of Queue of given version, that is maintained on each modification.
+// Versions with equal values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (q *Queue[T]) ContentHash(version uint64) (uint64, bool) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new Queue, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (q *Queue[T]) Compact(version uint64, opts ...CompactOption) (*Queue[T], error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewQueue[T]()
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = q.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new Queue, which initial version shares content of given version without copying it.
+// Modifications of the new Queue don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (q *Queue[T]) Fork(version uint64) (*Queue[T], error) {
+	info, err := q.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewQueue[T]()
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+// commit saves info as new version of Queue. Subscribers are notified with given event.
+func (q *Queue[T]) commit(version uint64, info queueVersionInfo[T], event ChangeEvent) (uint64, error) {
+	newVersion, err := q.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = q.versionTree.SetVersionInfo(newVersion, info)
+
+	q.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
@@ -0,0 +1,103 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestQueue_EnqueueDequeuePeek(t *testing.T) {
+	q, initialVersion := NewQueue[int]()
+	versionShouldBe(t, initialVersion, 0)
+
+	_, _, err := q.Dequeue(0)
+	errShouldBe(t, err, ErrEmpty)
+	_, err = q.Peek(0)
+	errShouldBe(t, err, ErrEmpty)
+
+	version := initialVersion
+	for i := 1; i <= 5; i++ {
+		version, err = q.Enqueue(version, i)
+		errIsNil(t, err)
+
+		head, err := q.Peek(version)
+		errIsNil(t, err)
+		isTrue(t, head == 1)
+	}
+	enqueuedVersion := version
+
+	val, version, err := q.Dequeue(version)
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+
+	version, err = q.Enqueue(version, 6)
+	errIsNil(t, err)
+
+	slice, err := q.ToGoSlice(version)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []int{2, 3, 4, 5, 6}))
+
+	for i := 2; i <= 6; i++ {
+		head, err := q.Peek(version)
+		errIsNil(t, err)
+		isTrue(t, head == i)
+
+		val, version, err = q.Dequeue(version)
+		errIsNil(t, err)
+		isTrue(t, val == i)
+	}
+
+	size, err := q.Len(version)
+	errIsNil(t, err)
+	isTrue(t, size == 0)
+
+	slice, err = q.ToGoSlice(enqueuedVersion)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []int{1, 2, 3, 4, 5}))
+}
+
+func TestQueueWithAnyValues(t *testing.T) {
+	q, version := NewQueueWithAnyValues()
+
+	version, err := q.Enqueue(version, "a")
+	errIsNil(t, err)
+	version, err = q.Enqueue(version, 1)
+	errIsNil(t, err)
+
+	val, version, err := q.Dequeue(version)
+	errIsNil(t, err)
+	isTrue(t, val == "a")
+
+	val, err = q.Peek(version)
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+}
+
+func TestQueue_DequeueSameVersion(t *testing.T) {
+	q, version := NewQueue[int]()
+
+	var err error
+	for i := 0; i < 100; i++ {
+		version, err = q.Enqueue(version, i)
+		errIsNil(t, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		newVersion, err := q.Enqueue(version, 100+i)
+		errIsNil(t, err)
+
+		for j := 0; j <= 100; j++ {
+			expected := j
+			if j == 100 {
+				expected = 100 + i
+			}
+
+			var val int
+			val, newVersion, err = q.Dequeue(newVersion)
+			errIsNil(t, err)
+			isTrue(t, val == expected)
+		}
+
+		_, _, err = q.Dequeue(newVersion)
+		errShouldBe(t, err, ErrEmpty)
+	}
+}
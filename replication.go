@@ -0,0 +1,287 @@
+package go_persistent_ds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+)
+
+var (
+	// ErrOpsGap is returned by ApplyOps if operations start after a version, that is not created yet.
+	ErrOpsGap = errors.New("operations don't follow the last version")
+	// ErrOpsDiverged is returned by ApplyOps if operation doesn't match the existing version with the same number.
+	ErrOpsDiverged = errors.New("operations diverged from existing versions")
+	// ErrOpsTooLarge is returned by ExportOps and ApplyOps if encoded operation exceeds the size limit.
+	ErrOpsTooLarge = errors.New("operation is too large")
+)
+
+// mapOp is the operation, that created version of Map. Operation with Replace set replaces all keys and values
+// by Values.
+type mapOp[TKey comparable, TVal any] struct {
+	Version uint64
+	Parent  uint64
+	Delete  bool
+	Key     TKey
+	Value   TVal
+	Replace bool
+	Values  map[TKey]TVal
+}
+
+// mapOpsHeader starts the batch of Count operations, that created all versions after Since.
+// Each operation of the batch follows it in its own frame.
+type mapOpsHeader struct {
+	Since uint64
+	Count uint64
+}
+
+// maxOpsFrameSize limits size of encoded header or operation, so corrupted length doesn't make reader
+// allocate arbitrary amount of memory.
+const maxOpsFrameSize = 64 << 20
+
+// ExportOps writes operations, that created all versions after since in all branches, to w.
+// The operations are written as a batch, that is read by ApplyOps of follower Map. Each operation is encoded
+// and written separately, so the batch is streamed without being kept in memory.
+// If Map is created without WithJournal, operations are rebuilt as replacements of all keys and values of versions.
+// Keys and values are encoded with encoding/gob, so types of values kept in interfaces must be registered with gob.Register.
+// If encoded operation is larger than 64 MiB, ErrOpsTooLarge is returned.
+//
+// Complexity: O(k), where k - amount of exported versions. Without journal O(k * n), where n - size of map.
+func (m *Map[TKey, TVal]) ExportOps(w io.Writer, since uint64) error {
+	if _, err := m.versionTree.GetVersionInfo(since); err != nil {
+		return err
+	}
+
+	last := m.LastVersion()
+
+	var frame bytes.Buffer
+	if err := writeOpsFrame(w, &frame, mapOpsHeader{Since: since, Count: last - since}); err != nil {
+		return err
+	}
+
+	for version := since + 1; version <= last; version++ {
+		event, err := m.event(version, m.rebuildEvent)
+		if err != nil {
+			return err
+		}
+
+		if err = writeOpsFrame(w, &frame, newMapOp[TKey, TVal](event)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyOps reads a batch of operations written by ExportOps of primary Map from r and applies them,
+// so that Map gets the same versions with the same parents and values. Versions, that already exist, are checked
+// to be created by the same operations and skipped, so the same batch may be applied several times.
+// Returns the last version of Map. If there are no more batches in r, io.EOF is returned.
+// If operations start after a version, that Map doesn't have, ErrOpsGap is returned.
+// If existing version differs from the primary one or operation can't be applied, ErrOpsDiverged is returned
+// and the rest of operations is skipped, so the next batch may be read from r.
+// If length of encoded operation exceeds 64 MiB, ErrOpsTooLarge is returned.
+//
+// Complexity: O(k * c), where k - amount of operations and c - complexity of Set.
+func (m *Map[TKey, TVal]) ApplyOps(r io.Reader) (uint64, error) {
+	var header mapOpsHeader
+	if err := readOpsFrame(r, &header); err != nil {
+		return m.LastVersion(), err
+	}
+
+	if header.Since > m.LastVersion() {
+		return m.LastVersion(), skipOpsFrames(r, header.Count, ErrOpsGap)
+	}
+
+	for i := uint64(0); i < header.Count; i++ {
+		var op mapOp[TKey, TVal]
+		if err := readOpsFrame(r, &op); err != nil {
+			return m.LastVersion(), err
+		}
+
+		if err := m.applyExported(op); err != nil {
+			return m.LastVersion(), skipOpsFrames(r, header.Count-i-1, err)
+		}
+	}
+
+	return m.LastVersion(), nil
+}
+
+// applyExported applies op read by ApplyOps, if its version doesn't exist, or checks,
+// that existing version was created by the same operation.
+func (m *Map[TKey, TVal]) applyExported(op mapOp[TKey, TVal]) error {
+	if op.Version <= m.LastVersion() {
+		if !m.createdBy(op) {
+			return ErrOpsDiverged
+		}
+
+		return nil
+	}
+
+	newVersion, err := m.applyOp(op)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOpsDiverged, err)
+	}
+
+	if newVersion != op.Version {
+		return ErrOpsDiverged
+	}
+
+	return nil
+}
+
+// applyOp creates new version from parent of op by the same operation.
+func (m *Map[TKey, TVal]) applyOp(op mapOp[TKey, TVal]) (uint64, error) {
+	if op.Replace {
+		return m.replaceValues(op.Parent, op.Values)
+	}
+
+	if op.Delete {
+		return m.Delete(op.Parent, op.Key)
+	}
+
+	return m.Set(op.Parent, op.Key, op.Value)
+}
+
+// createdBy reports whether existing version of op was created by the same operation.
+// If version is not journaled, it is checked to have the same parent and content, that op makes.
+func (m *Map[TKey, TVal]) createdBy(op mapOp[TKey, TVal]) bool {
+	m.subscribers.mu.Lock()
+	event, ok := m.subscribers.journaled(op.Version)
+	m.subscribers.mu.Unlock()
+
+	if !ok {
+		return m.madeBy(op)
+	}
+
+	existing := newMapOp[TKey, TVal](event)
+
+	return existing.Parent == op.Parent &&
+		existing.Delete == op.Delete &&
+		existing.Key == op.Key &&
+		valuesEqual(existing.Value, op.Value) &&
+		existing.Replace == op.Replace &&
+		maps.EqualFunc(existing.Values, op.Values, valuesEqual[TVal])
+}
+
+// madeBy reports whether existing version of op has parent of op and content, that op makes from the parent.
+func (m *Map[TKey, TVal]) madeBy(op mapOp[TKey, TVal]) bool {
+	if parent, ok := m.versionTree.Parent(op.Version); !ok || parent != op.Parent {
+		return false
+	}
+
+	actual, err := m.ToGoMap(op.Version)
+	if err != nil {
+		return false
+	}
+
+	expected := op.Values
+	if !op.Replace {
+		if expected, err = m.ToGoMap(op.Parent); err != nil {
+			return false
+		}
+
+		if op.Delete {
+			delete(expected, op.Key)
+		} else {
+			expected[op.Key] = op.Value
+		}
+	}
+
+	return maps.EqualFunc(actual, expected, valuesEqual[TVal])
+}
+
+func newMapOp[TKey comparable, TVal any](event ChangeEvent) mapOp[TKey, TVal] {
+	op := mapOp[TKey, TVal]{
+		Version: event.Version,
+		Parent:  event.Parent,
+		Delete:  event.Op == OpDelete,
+	}
+	if event.Op == OpReplace {
+		op.Replace = true
+		op.Values, _ = event.NewValue.(map[TKey]TVal)
+
+		return op
+	}
+
+	op.Key, _ = event.Key.(TKey)
+	op.Value, _ = event.NewValue.(TVal)
+
+	return op
+}
+
+// writeOpsFrame encodes value into frame and writes it to w prefixed by its length.
+func writeOpsFrame(w io.Writer, frame *bytes.Buffer, value any) error {
+	frame.Reset()
+	if err := gob.NewEncoder(frame).Encode(value); err != nil {
+		return err
+	}
+
+	if frame.Len() > maxOpsFrameSize {
+		return ErrOpsTooLarge
+	}
+
+	if err := binary.Write(w, binary.BigEndian, uint64(frame.Len())); err != nil {
+		return err
+	}
+
+	_, err := w.Write(frame.Bytes())
+
+	return err
+}
+
+// readOpsFrame reads length of the next frame from r and decodes value from the frame.
+func readOpsFrame(r io.Reader, value any) error {
+	size, err := readOpsFrameSize(r)
+	if err != nil {
+		return err
+	}
+
+	limited := &io.LimitedReader{R: r, N: int64(size)}
+	if err = gob.NewDecoder(limited).Decode(value); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+
+		return err
+	}
+
+	// decoder may leave the rest of frame unread.
+	_, err = io.Copy(io.Discard, limited)
+
+	return err
+}
+
+// skipOpsFrames skips count frames of r and returns err, if frames are skipped successfully.
+// Otherwise, error of reading is returned.
+func skipOpsFrames(r io.Reader, count uint64, err error) error {
+	for range count {
+		size, sizeErr := readOpsFrameSize(r)
+		if sizeErr != nil {
+			return sizeErr
+		}
+
+		if _, sizeErr = io.CopyN(io.Discard, r, int64(size)); sizeErr != nil {
+			return io.ErrUnexpectedEOF
+		}
+	}
+
+	return err
+}
+
+// readOpsFrameSize reads length of the next frame from r. If r has no frames, io.EOF is returned.
+func readOpsFrameSize(r io.Reader) (uint64, error) {
+	var size uint64
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return 0, err
+	}
+
+	if size > maxOpsFrameSize {
+		return 0, ErrOpsTooLarge
+	}
+
+	return size, nil
+}
@@ -0,0 +1,151 @@
+package go_persistent_ds
+
+import (
+	"bytes"
+	"io"
+	"maps"
+	"net"
+	"testing"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+func mapsShouldBeReplicas(t *testing.T, primary, follower *Map[string, int]) {
+	versionShouldBe(t, follower.LastVersion(), primary.LastVersion())
+
+	for version := uint64(0); version <= primary.LastVersion(); version++ {
+		primaryParent, primaryOk := primary.Parent(version)
+		followerParent, followerOk := follower.Parent(version)
+		isTrue(t, primaryOk == followerOk)
+		versionShouldBe(t, followerParent, primaryParent)
+
+		primaryMap, err := primary.ToGoMap(version)
+		errIsNil(t, err)
+		followerMap, err := follower.ToGoMap(version)
+		errIsNil(t, err)
+		isTrue(t, maps.Equal(primaryMap, followerMap))
+	}
+}
+
+func TestMap_ReplicationOverPipe(t *testing.T) {
+	primary, v0 := NewMap[string, int]()
+	follower, _ := NewMap[string, int](WithHAMT())
+
+	v1, err := primary.Set(v0, "a", 1)
+	errIsNil(t, err)
+	v2, err := primary.Set(v1, "b", 2)
+	errIsNil(t, err)
+	_, err = primary.Delete(v1, "a")
+	errIsNil(t, err)
+	_, err = primary.Set(v2, "a", 3)
+	errIsNil(t, err)
+
+	primaryConn, followerConn := net.Pipe()
+	defer followerConn.Close()
+
+	exported := make(chan error, 1)
+	go func() {
+		defer primaryConn.Close()
+
+		exportErr := primary.ExportOps(primaryConn, 0)
+		if exportErr == nil {
+			since := primary.LastVersion()
+			_, exportErr = primary.Set(since, "c", 4)
+			if exportErr == nil {
+				exportErr = primary.ExportOps(primaryConn, since)
+			}
+		}
+		exported <- exportErr
+	}()
+
+	last, err := follower.ApplyOps(followerConn)
+	errIsNil(t, err)
+	versionShouldBe(t, last, 4)
+
+	last, err = follower.ApplyOps(followerConn)
+	errIsNil(t, err)
+	versionShouldBe(t, last, 5)
+
+	_, err = follower.ApplyOps(followerConn)
+	errShouldBe(t, err, io.EOF)
+
+	errIsNil(t, <-exported)
+	mapsShouldBeReplicas(t, primary, follower)
+}
+
+func TestMap_ApplyOps_Repeated(t *testing.T) {
+	primary, version := NewMap[string, int]()
+	follower, _ := NewMap[string, int]()
+
+	var err error
+	for i := range 3 {
+		version, err = primary.Set(version, "key", i)
+		errIsNil(t, err)
+	}
+
+	var ops bytes.Buffer
+	errIsNil(t, primary.ExportOps(&ops, 0))
+	batch := ops.Bytes()
+
+	_, err = follower.ApplyOps(bytes.NewReader(batch))
+	errIsNil(t, err)
+	_, err = follower.ApplyOps(bytes.NewReader(batch))
+	errIsNil(t, err)
+
+	mapsShouldBeReplicas(t, primary, follower)
+}
+
+func TestMap_ApplyOps_GapAndDivergence(t *testing.T) {
+	primary, version := NewMap[string, int]()
+
+	var err error
+	for i := range 3 {
+		version, err = primary.Set(version, "key", i)
+		errIsNil(t, err)
+	}
+
+	var ops bytes.Buffer
+	errIsNil(t, primary.ExportOps(&ops, 2))
+
+	follower, _ := NewMap[string, int]()
+	last, err := follower.ApplyOps(&ops)
+	errShouldBe(t, err, ErrOpsGap)
+	versionShouldBe(t, last, 0)
+
+	errIsNil(t, primary.ExportOps(&ops, 0))
+
+	_, err = follower.Set(0, "key", 10)
+	errIsNil(t, err)
+
+	last, err = follower.ApplyOps(&ops)
+	errShouldBe(t, err, ErrOpsDiverged)
+	versionShouldBe(t, last, 1)
+
+	err = primary.ExportOps(&ops, version+1)
+	isTrue(t, err != nil)
+
+	_, err = follower.ApplyOps(bytes.NewReader([]byte{0, 0, 0}))
+	errShouldBe(t, err, io.ErrUnexpectedEOF)
+
+	_, err = follower.ApplyOps(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+	errShouldBe(t, err, ErrOpsTooLarge)
+}
+
+func TestMap_ApplyOps_InvalidOp(t *testing.T) {
+	follower, _ := NewMap[string, int]()
+
+	var ops, frame bytes.Buffer
+	errIsNil(t, writeOpsFrame(&ops, &frame, mapOpsHeader{Since: 0, Count: 2}))
+	errIsNil(t, writeOpsFrame(&ops, &frame, mapOp[string, int]{Version: 1, Parent: 5, Key: "a", Value: 1}))
+	errIsNil(t, writeOpsFrame(&ops, &frame, mapOp[string, int]{Version: 2, Parent: 0, Key: "b", Value: 2}))
+	errIsNil(t, writeOpsFrame(&ops, &frame, mapOpsHeader{Since: 0, Count: 0}))
+
+	last, err := follower.ApplyOps(&ops)
+	errShouldBe(t, err, ErrOpsDiverged)
+	errShouldBe(t, err, internal.ErrVersionNotFound)
+	versionShouldBe(t, last, 0)
+
+	last, err = follower.ApplyOps(&ops)
+	errIsNil(t, err)
+	versionShouldBe(t, last, 0)
+}
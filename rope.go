@@ -0,0 +1,458 @@
+package go_persistent_ds
+
+import (
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// ropeLeafSize is the maximal amount of runes in a single leaf of Rope.
+const ropeLeafSize = 64
+
+// Rope is a persistent text representation for versioned text editing.
+// While working with rope you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Rope is a balanced binary tree with chunks of text in leaves. Each version keeps the root of immutable tree.
+// Modification copies only O(log(n)) nodes, other nodes are shared between versions.
+// All offsets and lengths are measured in runes.
+//
+// Rope can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Rope, the good idea is to use Compact method, that creates new Rope
+// with special version as the initial one.
+//
+// Note that Rope is not thread safe.
+type Rope struct {
+	versionHistory[ropeVersionInfo]
+}
+
+type ropeVersionInfo struct {
+	root *ropeNode
+}
+
+// ropeNode is a node of AVL tree. Leaves hold runes, inner nodes always have both children.
+// Nodes are never changed after creation.
+type ropeNode struct {
+	left  *ropeNode
+	right *ropeNode
+	runes []rune
+
+	length   int
+	newlines int
+	height   int
+	hash     sequenceHash
+}
+
+// NewRope creates empty Rope.
+func NewRope() (*Rope, uint64) {
+	return &Rope{
+		versionHistory: newVersionHistory(internal.NewVersionTree[ropeVersionInfo]()),
+	}, 0
+}
+
+// NewRopeFromString creates Rope, which initial version contains given text.
+func NewRopeFromString(text string) (*Rope, uint64) {
+	r, version := NewRope()
+	_ = r.versionTree.SetVersionInfo(version, ropeVersionInfo{
+		root: newRopeFromRunes([]rune(text)),
+	})
+
+	return r, version
+}
+
+// Insert inserts text into Rope of given version, so it starts at given offset. Returns Rope's new version.
+//
+// Complexity: O(log(n) + l), where l - length of the text.
+func (r *Rope) Insert(version uint64, offset int, text string) (uint64, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 || offset > info.root.size() {
+		return 0, newIndexError(version, offset, info.root.size())
+	}
+
+	left, right := info.root.split(offset)
+	runes := []rune(text)
+
+	return r.commit(version, ropeVersionInfo{
+		root: joinRopes(joinRopes(left, newRopeFromRunes(runes)), right),
+	}, ChangeEvent{Op: OpInsert, Index: offset, Count: len(runes), NewValue: text})
+}
+
+// Delete removes length runes starting from offset from Rope of given version. Returns Rope's new version.
+//
+// Complexity: O(log(n)). If there are subscribers, the removed text is passed to them, which takes O(log(n) + l),
+// where l - length.
+func (r *Rope) Delete(version uint64, offset, length int) (uint64, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+	if !info.root.inRange(offset, length) {
+		return 0, newRangeError(version, offset, offset+length, info.root.size())
+	}
+
+	left, rest := info.root.split(offset)
+	_, right := rest.split(length)
+
+	event := ChangeEvent{Op: OpDelete, Index: offset, Count: length}
+	if r.subscribed() {
+		event.OldValue = string(info.root.appendRunes(make([]rune, 0, length), offset, offset+length))
+	}
+
+	return r.commit(version, ropeVersionInfo{
+		root: joinRopes(left, right),
+	}, event)
+}
+
+// Substring returns length runes starting from offset from Rope of given version.
+//
+// Complexity: O(log(n) + l), where l - length of the substring.
+func (r *Rope) Substring(version uint64, offset, length int) (string, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return "", err
+	}
+	if !info.root.inRange(offset, length) {
+		return "", newRangeError(version, offset, offset+length, info.root.size())
+	}
+
+	return string(info.root.appendRunes(make([]rune, 0, length), offset, offset+length)), nil
+}
+
+// Len returns the amount of runes in Rope.
+//
+// Complexity: O(1).
+func (r *Rope) Len(version uint64) (int, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.root.size(), nil
+}
+
+// LineCount returns the amount of lines in Rope. Lines are separated by '\n', so empty Rope has one line.
+//
+// Complexity: O(1).
+func (r *Rope) LineCount(version uint64) (int, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.root.newlineCount() + 1, nil
+}
+
+// LineAt returns the line of Rope by its index without trailing '\n'.
+//
+// Complexity: O(log(n) + l), where l - length of the line.
+func (r *Rope) LineAt(version uint64, line int) (string, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return "", err
+	}
+	if line < 0 || line > info.root.newlineCount() {
+		return "", newIndexError(version, line, info.root.newlineCount()+1)
+	}
+
+	start := 0
+	if line > 0 {
+		start = info.root.newlineOffset(line) + 1
+	}
+
+	end := info.root.size()
+	if line < info.root.newlineCount() {
+		end = info.root.newlineOffset(line + 1)
+	}
+
+	return string(info.root.appendRunes(make([]rune, 0, end-start), start, end)), nil
+}
+
+// String returns the whole text of Rope of given version.
+//
+// Complexity: O(n).
+func (r *Rope) String(version uint64) (string, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return "", err
+	}
+
+	return string(info.root.appendRunes(make([]rune, 0, info.root.size()), 0, info.root.size())), nil
+}
+
+// Equal reports whether versions v1 and v2 of Rope contain the same text.
+//
+// Complexity: O(1) if lengths or content hashes of versions differ, otherwise O(n).
+func (r *Rope) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := r.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := r.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.root.size() != info2.root.size() || hashesDiffer(info1.root.textHash(), info2.root.textHash()) {
+		return false, nil
+	}
+
+	text1, _ := r.String(v1)
+	text2, _ := r.String(v2)
+
+	return text1 == text2, nil
+}
+
+// ContentHash returns hash of text of Rope of given version. Each node of Rope keeps hash of its text,
+// so hash is maintained on each modification. Versions with the same text have equal hashes.
+// If version doesn't exist, false is returned.
+//
+// Complexity: O(1).
+func (r *Rope) ContentHash(version uint64) (uint64, bool) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.root.textHash().contentHash()
+}
+
+// Compact creates new Rope, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (r *Rope) Compact(version uint64, opts ...CompactOption) (*Rope, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewRope()
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = r.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new Rope, which initial version shares content of given version without copying it.
+// Modifications of the new Rope don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (r *Rope) Fork(version uint64) (*Rope, error) {
+	info, err := r.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewRope()
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+func (r *Rope) commit(version uint64, info ropeVersionInfo, event ChangeEvent) (uint64, error) {
+	newVersion, err := r.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = r.versionTree.SetVersionInfo(newVersion, info)
+
+	r.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
+
+// newRopeFromRunes builds balanced tree from runes, that must not be changed afterward.
+func newRopeFromRunes(runes []rune) *ropeNode {
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) <= ropeLeafSize {
+		return newRopeLeaf(runes)
+	}
+
+	half := len(runes) / 2
+	return newRopeInner(newRopeFromRunes(runes[:half]), newRopeFromRunes(runes[half:]))
+}
+
+func newRopeLeaf(runes []rune) *ropeNode {
+	newlines := 0
+	for _, r := range runes {
+		if r == '\n' {
+			newlines++
+		}
+	}
+
+	return &ropeNode{
+		runes:    runes,
+		length:   len(runes),
+		newlines: newlines,
+		hash:     sequenceHashOf(runes),
+	}
+}
+
+func newRopeInner(left, right *ropeNode) *ropeNode {
+	return &ropeNode{
+		left:     left,
+		right:    right,
+		length:   left.length + right.length,
+		newlines: left.newlines + right.newlines,
+		height:   max(left.height, right.height) + 1,
+		hash:     left.hash.concat(right.hash),
+	}
+}
+
+func (n *ropeNode) size() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.length
+}
+
+func (n *ropeNode) newlineCount() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.newlines
+}
+
+// textHash returns hash of runes of the tree.
+func (n *ropeNode) textHash() sequenceHash {
+	if n == nil {
+		return sequenceHash{}
+	}
+
+	return n.hash
+}
+
+func (n *ropeNode) treeHeight() int {
+	if n == nil {
+		return -1
+	}
+
+	return n.height
+}
+
+func (n *ropeNode) isLeaf() bool {
+	return n.left == nil
+}
+
+func (n *ropeNode) inRange(offset, length int) bool {
+	return offset >= 0 && length >= 0 && offset+length <= n.size()
+}
+
+// split returns two trees: with first offset runes and with the rest ones.
+func (n *ropeNode) split(offset int) (*ropeNode, *ropeNode) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case offset == 0:
+		return nil, n
+	case offset == n.length:
+		return n, nil
+	case n.isLeaf():
+		return newRopeLeaf(n.runes[:offset:offset]), newRopeLeaf(n.runes[offset:])
+	case offset < n.left.length:
+		left, right := n.left.split(offset)
+		return left, joinRopes(right, n.right)
+	default:
+		left, right := n.right.split(offset - n.left.length)
+		return joinRopes(n.left, left), right
+	}
+}
+
+// newlineOffset returns the offset of the k-th '\n' counting from 1.
+func (n *ropeNode) newlineOffset(k int) int {
+	offset := 0
+	for !n.isLeaf() {
+		if k <= n.left.newlines {
+			n = n.left
+		} else {
+			k -= n.left.newlines
+			offset += n.left.length
+			n = n.right
+		}
+	}
+
+	for i, r := range n.runes {
+		if r == '\n' {
+			k--
+			if k == 0 {
+				return offset + i
+			}
+		}
+	}
+
+	return offset + n.length
+}
+
+// appendRunes appends runes with offsets in [from, to) to dst.
+func (n *ropeNode) appendRunes(dst []rune, from, to int) []rune {
+	if n == nil || from >= to {
+		return dst
+	}
+	if n.isLeaf() {
+		return append(dst, n.runes[from:to]...)
+	}
+
+	if from < n.left.length {
+		dst = n.left.appendRunes(dst, from, min(to, n.left.length))
+	}
+	if to > n.left.length {
+		dst = n.right.appendRunes(dst, max(from-n.left.length, 0), to-n.left.length)
+	}
+
+	return dst
+}
+
+// joinRopes concatenates two trees keeping the result balanced.
+func joinRopes(left, right *ropeNode) *ropeNode {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.isLeaf() && right.isLeaf() && left.length+right.length <= ropeLeafSize:
+		runes := make([]rune, 0, left.length+right.length)
+		return newRopeLeaf(append(append(runes, left.runes...), right.runes...))
+	case left.height > right.height+1:
+		return balanceRope(left.left, joinRopes(left.right, right))
+	case right.height > left.height+1:
+		return balanceRope(joinRopes(left, right.left), right.right)
+	default:
+		return newRopeInner(left, right)
+	}
+}
+
+// balanceRope creates inner node from trees, which heights differ at most by 2, performing AVL rotations.
+func balanceRope(left, right *ropeNode) *ropeNode {
+	switch {
+	case left.treeHeight() > right.treeHeight()+1:
+		if left.left.treeHeight() >= left.right.treeHeight() {
+			return newRopeInner(left.left, newRopeInner(left.right, right))
+		}
+		return newRopeInner(
+			newRopeInner(left.left, left.right.left),
+			newRopeInner(left.right.right, right),
+		)
+	case right.treeHeight() > left.treeHeight()+1:
+		if right.right.treeHeight() >= right.left.treeHeight() {
+			return newRopeInner(newRopeInner(left, right.left), right.right)
+		}
+		return newRopeInner(
+			newRopeInner(left, right.left.left),
+			newRopeInner(right.left.right, right.right),
+		)
+	default:
+		return newRopeInner(left, right)
+	}
+}
@@ -0,0 +1,163 @@
+package go_persistent_ds
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func ropeShouldBe(t *testing.T, r *Rope, version uint64, expected string) {
+	t.Helper()
+
+	text, err := r.String(version)
+	errIsNil(t, err)
+	if text != expected {
+		t.Errorf("expected text %q for version %d, got %q", expected, version, text)
+	}
+
+	size, err := r.Len(version)
+	errIsNil(t, err)
+	if size != len([]rune(expected)) {
+		t.Errorf("expected len %d for version %d, got %d", len([]rune(expected)), version, size)
+	}
+}
+
+func checkRopeBalance(t *testing.T, n *ropeNode) {
+	t.Helper()
+
+	if n == nil || n.isLeaf() {
+		return
+	}
+
+	diff := n.left.height - n.right.height
+	if diff > 1 || diff < -1 {
+		t.Fatalf("rope is not balanced: left height %d, right height %d", n.left.height, n.right.height)
+	}
+
+	checkRopeBalance(t, n.left)
+	checkRopeBalance(t, n.right)
+}
+
+func TestRope_InsertAndDelete(t *testing.T) {
+	r, version := NewRope()
+	ropeShouldBe(t, r, version, "")
+
+	v1, err := r.Insert(version, 0, "world")
+	errIsNil(t, err)
+	v2, err := r.Insert(v1, 0, "hello ")
+	errIsNil(t, err)
+	v3, err := r.Insert(v2, 11, "!")
+	errIsNil(t, err)
+	v4, err := r.Insert(v3, 5, ", дорогой")
+	errIsNil(t, err)
+
+	ropeShouldBe(t, r, v3, "hello world!")
+	ropeShouldBe(t, r, v4, "hello, дорогой world!")
+
+	v5, err := r.Delete(v4, 5, 9)
+	errIsNil(t, err)
+	ropeShouldBe(t, r, v5, "hello world!")
+
+	v6, err := r.Delete(v5, 0, 12)
+	errIsNil(t, err)
+	ropeShouldBe(t, r, v6, "")
+
+	_, err = r.Insert(v5, 13, "x")
+	errShouldBe(t, err, ErrIndexOutOfRange)
+	_, err = r.Insert(v5, -1, "x")
+	errShouldBe(t, err, ErrIndexOutOfRange)
+	_, err = r.Delete(v5, 10, 3)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+
+	substring, err := r.Substring(v4, 7, 7)
+	errIsNil(t, err)
+	isTrue(t, substring == "дорогой")
+
+	_, err = r.Substring(v4, 20, 2)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+
+	ropeShouldBe(t, r, v1, "world")
+}
+
+func TestRope_Lines(t *testing.T) {
+	r, version := NewRopeFromString("first\nsecond\n\nfourth")
+
+	count, err := r.LineCount(version)
+	errIsNil(t, err)
+	isTrue(t, count == 4)
+
+	for i, expected := range []string{"first", "second", "", "fourth"} {
+		line, err := r.LineAt(version, i)
+		errIsNil(t, err)
+		isTrue(t, line == expected)
+	}
+
+	_, err = r.LineAt(version, 4)
+	errShouldBe(t, err, ErrIndexOutOfRange)
+
+	version, err = r.Insert(version, 0, "zero\n")
+	errIsNil(t, err)
+
+	line, err := r.LineAt(version, 1)
+	errIsNil(t, err)
+	isTrue(t, line == "first")
+
+	empty, emptyVersion := NewRope()
+	count, err = empty.LineCount(emptyVersion)
+	errIsNil(t, err)
+	isTrue(t, count == 1)
+
+	line, err = empty.LineAt(emptyVersion, 0)
+	errIsNil(t, err)
+	isTrue(t, line == "")
+}
+
+func TestRope_ManyEdits(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	alphabet := []rune("ab\nцд")
+
+	r, version := NewRope()
+	expected := []rune{}
+	texts := map[uint64]string{version: ""}
+
+	for i := 0; i < 1000; i++ {
+		var err error
+		if len(expected) > 0 && rnd.Intn(3) == 0 {
+			offset := rnd.Intn(len(expected))
+			length := rnd.Intn(len(expected) - offset + 1)
+
+			version, err = r.Delete(version, offset, length)
+			expected = append(expected[:offset:offset], expected[offset+length:]...)
+		} else {
+			text := make([]rune, rnd.Intn(100))
+			for j := range text {
+				text[j] = alphabet[rnd.Intn(len(alphabet))]
+			}
+			offset := rnd.Intn(len(expected) + 1)
+
+			version, err = r.Insert(version, offset, string(text))
+			expected = append(expected[:offset:offset], append(text, expected[offset:]...)...)
+		}
+		errIsNil(t, err)
+		texts[version] = string(expected)
+	}
+
+	for v, text := range texts {
+		ropeShouldBe(t, r, v, text)
+
+		info, err := r.versionTree.GetVersionInfo(v)
+		errIsNil(t, err)
+		checkRopeBalance(t, info.root)
+
+		lines := strings.Split(text, "\n")
+		count, err := r.LineCount(v)
+		errIsNil(t, err)
+		isTrue(t, count == len(lines))
+
+		for i, expectedLine := range lines {
+			line, err := r.LineAt(v, i)
+			errIsNil(t, err)
+			isTrue(t, line == expectedLine)
+		}
+	}
+}
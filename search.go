@@ -0,0 +1,157 @@
+package go_persistent_ds
+
+import (
+	"sort"
+)
+
+// findIn returns index and value of the first element of version visited by walk, for which pred returns true.
+// If there is no such element, -1 is returned.
+func findIn[T any](
+	walk func(version uint64, yield func(index int, val T) bool) error,
+	version uint64,
+	pred func(val T) bool,
+) (int, T, error) {
+	foundIndex, foundVal := -1, *new(T)
+	err := walk(version, func(index int, val T) bool {
+		if !pred(val) {
+			return true
+		}
+
+		foundIndex, foundVal = index, val
+
+		return false
+	})
+
+	return foundIndex, foundVal, err
+}
+
+// countIn returns the amount of elements of version visited by walk, for which pred returns true.
+func countIn[T any](
+	walk func(version uint64, yield func(index int, val T) bool) error,
+	version uint64,
+	pred func(val T) bool,
+) (int, error) {
+	count := 0
+	err := walk(version, func(_ int, val T) bool {
+		if pred(val) {
+			count++
+		}
+
+		return true
+	})
+
+	return count, err
+}
+
+// equalTo returns predicate, that reports whether value is equal to target. Comparable values are compared with ==,
+// other values are compared with reflect.DeepEqual.
+func equalTo[T any](target T) func(val T) bool {
+	return func(val T) bool {
+		return valuesEqual(val, target)
+	}
+}
+
+// IndexOf returns the index of the first value of given version equal to value, or -1 if there is no such value.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: same as for ToGoSlice.
+func (s *Slice[TVal]) IndexOf(version uint64, value TVal) (int, error) {
+	index, _, err := findIn(s.walk, version, equalTo(value))
+
+	return index, err
+}
+
+// Contains reports whether there is value of given version equal to value.
+//
+// Complexity: same as for ToGoSlice.
+func (s *Slice[TVal]) Contains(version uint64, value TVal) (bool, error) {
+	index, err := s.IndexOf(version, value)
+
+	return index >= 0, err
+}
+
+// FindFunc returns the index and the first value of given version, for which pred returns true.
+// If there is no such value, -1 is returned.
+//
+// Complexity: same as for ToGoSlice.
+func (s *Slice[TVal]) FindFunc(version uint64, pred func(val TVal) bool) (int, TVal, error) {
+	return findIn(s.walk, version, pred)
+}
+
+// Count returns the amount of values of given version, for which pred returns true.
+//
+// Complexity: same as for ToGoSlice.
+func (s *Slice[TVal]) Count(version uint64, pred func(val TVal) bool) (int, error) {
+	return countIn(s.walk, version, pred)
+}
+
+// BinarySearch searches for target in given version, which values must be sorted in increasing order by cmp.
+// Returns the position, where target is found or would be inserted, and whether it is found.
+//
+// Complexity: O(d + log(n) * r), where d - depth of version, n - size of Slice and r - complexity of reading
+// a single value, which is O(log(m) * k) for Slice with Cells and O(log32(n)) for Slice created with WithRRBTree option.
+func (s *Slice[TVal]) BinarySearch(version uint64, target TVal, cmp func(val, target TVal) int) (int, bool, error) {
+	read, size, err := s.reader(version)
+	if err != nil {
+		return 0, false, err
+	}
+
+	index := sort.Search(size, func(i int) bool {
+		return cmp(read(i), target) >= 0
+	})
+
+	return index, index < size && cmp(read(index), target) == 0, nil
+}
+
+// IndexOf returns the index of the first value of given version equal to value, or -1 if there is no such value.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: same as for ToGoSlice.
+func (l *DoubleLinkedList[T]) IndexOf(version uint64, value T) (int, error) {
+	index, _, err := findIn(l.walk, version, equalTo(value))
+
+	return index, err
+}
+
+// Contains reports whether there is value of given version equal to value.
+//
+// Complexity: same as for ToGoSlice.
+func (l *DoubleLinkedList[T]) Contains(version uint64, value T) (bool, error) {
+	index, err := l.IndexOf(version, value)
+
+	return index >= 0, err
+}
+
+// FindFunc returns the index and the first value of given version from head, for which pred returns true.
+// If there is no such value, -1 is returned.
+//
+// Complexity: same as for ToGoSlice.
+func (l *DoubleLinkedList[T]) FindFunc(version uint64, pred func(val T) bool) (int, T, error) {
+	return findIn(l.walk, version, pred)
+}
+
+// Count returns the amount of values of given version, for which pred returns true.
+//
+// Complexity: same as for ToGoSlice.
+func (l *DoubleLinkedList[T]) Count(version uint64, pred func(val T) bool) (int, error) {
+	return countIn(l.walk, version, pred)
+}
+
+// FindKeys returns keys of given version, for which pred returns true, in unspecified order.
+//
+// Complexity: same as for ToGoMap.
+func (m *Map[TKey, TVal]) FindKeys(version uint64, pred func(key TKey, val TVal) bool) ([]TKey, error) {
+	var keys []TKey
+	err := m.walk(version, func(key TKey, val TVal) bool {
+		if pred(key, val) {
+			keys = append(keys, key)
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
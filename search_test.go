@@ -0,0 +1,127 @@
+package go_persistent_ds
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestSearch_Indexed(t *testing.T) {
+	type searchable interface {
+		Transformable[int]
+		IndexOf(version uint64, value int) (int, error)
+		Contains(version uint64, value int) (bool, error)
+		FindFunc(version uint64, pred func(val int) bool) (int, int, error)
+		Count(version uint64, pred func(val int) bool) (int, error)
+	}
+
+	for name, create := range transformables() {
+		t.Run(name, func(t *testing.T) {
+			structure, push := create()
+			s := structure.(searchable)
+
+			var (
+				version uint64
+				err     error
+			)
+			for _, val := range []int{3, 7, 1, 7, 4} {
+				version, err = push(version, val)
+				errIsNil(t, err)
+			}
+			branch, err := s.Set(version, 1, 8)
+			errIsNil(t, err)
+
+			index, err := s.IndexOf(version, 7)
+			errIsNil(t, err)
+			isTrue(t, index == 1)
+			index, err = s.IndexOf(branch, 7)
+			errIsNil(t, err)
+			isTrue(t, index == 3)
+			index, err = s.IndexOf(0, 7)
+			errIsNil(t, err)
+			isTrue(t, index == -1)
+
+			contains, err := s.Contains(branch, 8)
+			errIsNil(t, err)
+			isTrue(t, contains)
+			contains, err = s.Contains(version, 8)
+			errIsNil(t, err)
+			isTrue(t, !contains)
+
+			index, val, err := s.FindFunc(branch, func(val int) bool { return val > 5 })
+			errIsNil(t, err)
+			isTrue(t, index == 1 && val == 8)
+			index, val, err = s.FindFunc(branch, func(val int) bool { return val > 10 })
+			errIsNil(t, err)
+			isTrue(t, index == -1 && val == 0)
+
+			count, err := s.Count(version, func(val int) bool { return val%2 == 1 })
+			errIsNil(t, err)
+			isTrue(t, count == 4)
+
+			_, err = s.Count(branch+1, func(int) bool { return true })
+			errShouldBe(t, err, ErrVersionNotFound)
+		})
+	}
+}
+
+func TestSlice_BinarySearch(t *testing.T) {
+	for name, opts := range map[string][]SliceOption{"Cells": nil, "RRB-tree": {WithRRBTree()}} {
+		t.Run(name, func(t *testing.T) {
+			s, version := NewSlice[int](opts...)
+
+			var err error
+			for i := 0; i < 100; i++ {
+				version, err = s.Append(version, i*2)
+				errIsNil(t, err)
+			}
+
+			values, err := s.ToGoSlice(version)
+			errIsNil(t, err)
+
+			for _, target := range []int{-1, 0, 31, 64, 198, 199} {
+				index, found, searchErr := s.BinarySearch(version, target, cmp.Compare[int])
+				errIsNil(t, searchErr)
+
+				expectedIndex, expectedFound := slices.BinarySearch(values, target)
+				isTrue(t, index == expectedIndex && found == expectedFound)
+			}
+
+			index, found, err := s.BinarySearch(0, 1, cmp.Compare[int])
+			errIsNil(t, err)
+			isTrue(t, index == 0 && !found)
+		})
+	}
+}
+
+func TestMap_FindKeys(t *testing.T) {
+	for name, opts := range map[string][]MapOption{
+		"Cells":              nil,
+		"HAMT":               {WithHAMT()},
+		"VersionListBackend": {WithBackend(VersionListBackend())},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, version := NewMap[string, int](opts...)
+
+			var err error
+			for i, key := range []string{"a", "b", "c", "d"} {
+				version, err = m.Set(version, key, i)
+				errIsNil(t, err)
+			}
+			deleted, err := m.Delete(version, "c")
+			errIsNil(t, err)
+
+			keys, err := m.FindKeys(deleted, func(_ string, val int) bool { return val >= 1 })
+			errIsNil(t, err)
+			slices.Sort(keys)
+			isTrue(t, slices.Equal(keys, []string{"b", "d"}))
+
+			keys, err = m.FindKeys(version, func(key string, _ int) bool { return key == "c" })
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(keys, []string{"c"}))
+
+			_, err = m.FindKeys(deleted+1, func(string, int) bool { return true })
+			errShouldBe(t, err, ErrVersionNotFound)
+		})
+	}
+}
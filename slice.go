@@ -2,6 +2,7 @@ package go_persistent_ds
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/AleksandrMatsko/go-persistent-ds/internal"
 )
@@ -9,7 +10,7 @@ import (
 var (
 	// ErrSliceInitialize is returned then there is a problem in creating new slice because of tree problems.
 	ErrSliceInitialize = errors.New("failed to init Slice because version tree is damaged")
-	// ErrIndexOutOfRange is returned then index is greater than len array for the version.
+	// ErrIndexOutOfRange is matched by IndexError, that is returned then index is out of range for the version.
 	ErrIndexOutOfRange = errors.New("array index out of range")
 )
 
@@ -17,30 +18,107 @@ var (
 // While working with slice you can access and/or modify each previous version.
 // Note that modifying version creates new one.
 //
-// Slice can perform total of 2^65-1 modifications, and will panic on attempt to modify it for 2^65 time.
-// If you need to continue editing Slice, the good idea is to use ToGoSlice method to dump Slice for special version.
+// By default, Slice is based on Cells: one Cell for each index ever used. Cells and versions are stored by Backend,
+// which is FatNodeBackend unless WithBackend option is given.
+// Slice created with WithRRBTree option keeps relaxed radix balanced tree for each version instead.
+//
+// Versions with Cells refer to storage of Cells with startIndex and capacity, like go slices refer to arrays.
+// Range gives new version its own capacity, so modifications, which don't fit in it, copy values to new storage,
+// and the old storage is kept only by versions, that refer to it.
+//
+// Slice can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Slice, the good idea is to use Compact method, that creates new Slice
+// with special version as the initial one.
 //
 // Note that Slice is not thread safe.
 type Slice[TVal any] struct {
-	versionTree     *internal.VersionTree[sliceVersionInfo]
-	sliceOfFatNodes []*internal.FatNode
+	versionHistory[sliceVersionInfo[TVal]]
+	backend    Backend
+	useRRBTree bool
+	forks      forkLock
+}
+
+// unboundedCapacity means, that version may use all Cells of storage after startIndex and add new ones.
+const unboundedCapacity = -1
+
+// sliceStorage keeps Cells shared by versions of Slice.
+type sliceStorage[TVal any] struct {
+	// cells may have nil Cells for positions, which values are read from base.
+	cells []internal.Cell
+	// base reads values of positions without own values from the forked Slice, if storage is created by Fork.
+	base *sliceBase[TVal]
+}
+
+// sliceBase is the version of Slice with Cells, that is shared with Slice created by Fork.
+type sliceBase[TVal any] struct {
+	lock    *forkLock
+	info    sliceVersionInfo[TVal]
+	version uint64
+	history []uint64
 }
 
-type sliceVersionInfo struct {
+type sliceVersionInfo[TVal any] struct {
 	size       int
 	startIndex int
+	// capacity is the amount of Cells after startIndex, that version may use, or unboundedCapacity.
+	capacity int
+	storage  *sliceStorage[TVal]
+	hash     sequenceHash
+	// rrb is used only if Slice is created with WithRRBTree option.
+	rrb internal.RRBTree[TVal]
+}
+
+// SliceOption configures Slice on creation.
+type SliceOption interface {
+	applyToSlice(cfg *sliceConfig)
+}
+
+type sliceConfig struct {
+	backend    Backend
+	useRRBTree bool
+	journal    bool
+}
+
+type sliceOptionFunc func(cfg *sliceConfig)
+
+func (f sliceOptionFunc) applyToSlice(cfg *sliceConfig) {
+	f(cfg)
+}
+
+// WithRRBTree makes Slice keep relaxed radix balanced tree with path copying for each version instead of Cells.
+// Then Get, Set, Append, Prepend, Concat, Slice and Range take O(log32(n)) regardless of the amount of versions.
+func WithRRBTree() SliceOption {
+	return sliceOptionFunc(func(cfg *sliceConfig) {
+		cfg.useRRBTree = true
+	})
 }
 
 // NewSlice creates empty Slice.
-func NewSlice[TVal any]() (*Slice[TVal], uint64) {
-	return NewSliceWithCapacity[TVal](0)
+func NewSlice[TVal any](opts ...SliceOption) (*Slice[TVal], uint64) {
+	return NewSliceWithCapacity[TVal](0, opts...)
 }
 
 // NewSliceWithCapacity creates empty Slice with given capacity.
-func NewSliceWithCapacity[TVal any](capacity int) (*Slice[TVal], uint64) {
+func NewSliceWithCapacity[TVal any](capacity int, opts ...SliceOption) (*Slice[TVal], uint64) {
+	cfg := sliceConfig{
+		backend: FatNodeBackend(),
+	}
+	for _, opt := range opts {
+		opt.applyToSlice(&cfg)
+	}
+
 	s := &Slice[TVal]{
-		versionTree:     internal.NewVersionTree[sliceVersionInfo](),
-		sliceOfFatNodes: make([]*internal.FatNode, 0, capacity),
+		versionHistory: newJournaledVersionHistory(
+			internal.NewVersionTreeWithStore[sliceVersionInfo[TVal]](cfg.backend.NewVersionStore()),
+			cfg.journal,
+		),
+		backend:    cfg.backend,
+		useRRBTree: cfg.useRRBTree,
+	}
+	var storage *sliceStorage[TVal]
+	if !cfg.useRRBTree {
+		storage = &sliceStorage[TVal]{cells: make([]internal.Cell, 0, capacity)}
 	}
 
 	var (
@@ -51,9 +129,11 @@ func NewSliceWithCapacity[TVal any](capacity int) (*Slice[TVal], uint64) {
 
 	err := s.versionTree.SetVersionInfo(
 		initialVersion,
-		sliceVersionInfo{
+		sliceVersionInfo[TVal]{
 			size:       initialSliceSize,
 			startIndex: initialStartIndex,
+			capacity:   unboundedCapacity,
+			storage:    storage,
 		})
 	if err != nil {
 		panic(ErrSliceInitialize)
@@ -63,16 +143,37 @@ func NewSliceWithCapacity[TVal any](capacity int) (*Slice[TVal], uint64) {
 }
 
 // NewSliceWithAnyValues creates a Slice, that can store values of any type.
-func NewSliceWithAnyValues() (*Slice[any], uint64) {
-	return NewSliceWithCapacity[any](0)
+func NewSliceWithAnyValues(opts ...SliceOption) (*Slice[any], uint64) {
+	return NewSliceWithCapacity[any](0, opts...)
+}
+
+// newSliceOfValues creates Slice configured by opts, which initial version has given values.
+func newSliceOfValues[TVal any](values []TVal, opts ...SliceOption) *Slice[TVal] {
+	s, _ := NewSlice[TVal](opts...)
+
+	info := sliceVersionInfo[TVal]{
+		size:     len(values),
+		capacity: unboundedCapacity,
+		hash:     sequenceHashOf(values),
+	}
+	if s.useRRBTree {
+		info.rrb = internal.NewRRBTree(values)
+	} else {
+		info.storage = s.initialStorage(values)
+	}
+
+	_ = s.versionTree.SetVersionInfo(0, info)
+
+	return s
 }
 
 // Set value for given index and version in Slice.
 //
-// Complexity: O(1).
+// Complexity: O(1). For Slice created with WithRRBTree option complexity is O(log32(n)).
+// If there are subscribers, the replaced value is read for the event, which takes the same time as Get.
 func (s *Slice[TVal]) Set(forVersion uint64, index int, val TVal) (uint64, error) {
-	if index < 0 {
-		return 0, ErrIndexOutOfRange
+	if s.useRRBTree {
+		return s.setToRRBTree(forVersion, index, val)
 	}
 
 	oldVersionInfo, err := s.versionTree.GetVersionInfo(forVersion)
@@ -80,30 +181,38 @@ func (s *Slice[TVal]) Set(forVersion uint64, index int, val TVal) (uint64, error
 		return 0, err
 	}
 
-	actualIndex := oldVersionInfo.startIndex + index
-
-	if len(s.sliceOfFatNodes) <= actualIndex {
-		return 0, ErrIndexOutOfRange
+	if index < 0 || index >= oldVersionInfo.size {
+		return 0, newIndexError(forVersion, index, oldVersionInfo.size)
 	}
 
-	if forVersion == 0 {
-		return 0, ErrIndexOutOfRange
-	}
+	// replaced value is needed only for the event, without it the hash can't be updated and becomes stale.
+	event := ChangeEvent{Op: OpSet, Index: index, NewValue: val}
+	newHash := oldVersionInfo.hash.invalidate()
+	if s.subscribed() {
+		oldVal, getErr := s.Get(forVersion, index)
+		if getErr != nil {
+			return 0, getErr
+		}
 
-	fatNode := s.sliceOfFatNodes[actualIndex]
+		event.OldValue = oldVal
+		newHash = oldVersionInfo.hash.set(index, oldVal, val)
+	}
 
+	unlock := s.forks.lock()
 	newVersion, err := s.versionTree.Update(forVersion)
 	if err != nil {
+		unlock()
 		return 0, err
 	}
 
-	newVersionInfo := sliceVersionInfo{
-		size:       oldVersionInfo.size,
-		startIndex: oldVersionInfo.startIndex,
-	}
+	newVersionInfo := *oldVersionInfo
+	newVersionInfo.hash = newHash
 
-	fatNode.Update(val, newVersion)
+	s.writeValues(newVersion, &newVersionInfo, index, []TVal{val})
 	_ = s.versionTree.SetVersionInfo(newVersion, newVersionInfo)
+	unlock()
+
+	s.notify(forVersion, newVersion, event)
 
 	return newVersion, nil
 }
@@ -114,9 +223,11 @@ func (s *Slice[TVal]) Set(forVersion uint64, index int, val TVal) (uint64, error
 // Complexity: O(log(m) * k) there:
 //   - m - amount of modifications for value by the index from slice creation.
 //   - k - amount of modifications visible from current branch.
+//
+// For Slice created with WithRRBTree option complexity is O(log32(n)), there n - size of Slice.
 func (s *Slice[TVal]) Get(version uint64, index int) (TVal, error) {
-	if index < 0 {
-		return *new(TVal), ErrIndexOutOfRange
+	if s.useRRBTree {
+		return s.getFromRRBTree(version, index)
 	}
 
 	info, err := s.versionTree.GetVersionInfo(version)
@@ -124,145 +235,795 @@ func (s *Slice[TVal]) Get(version uint64, index int) (TVal, error) {
 		return *new(TVal), err
 	}
 
-	actualIndex := info.startIndex + index
+	if index < 0 || index >= info.size {
+		return *new(TVal), newIndexError(version, index, info.size)
+	}
+
+	val, found := info.value(version, index, func() []uint64 {
+		changeHistory, _ := s.versionTree.GetHistory(version)
+		return changeHistory
+	})
+	if !found {
+		return *new(TVal), newIndexError(version, index, info.size)
+	}
+
+	return val, nil
+}
+
+// Len returns the len of Slice.
+//
+// Complexity: O(1).
+func (s *Slice[TVal]) Len(version uint64) (int, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.size, nil
+}
+
+// Cap returns the capacity of Slice for given version: the amount of values it can have without copying
+// them to new storage. Capacity of Slice created with WithRRBTree option is equal to its size.
+//
+// Complexity: O(1).
+func (s *Slice[TVal]) Cap(version uint64) (int, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.useRRBTree {
+		return info.size, nil
+	}
+
+	if info.capacity == unboundedCapacity {
+		return max(info.size, len(info.storage.cells)-info.startIndex), nil
+	}
+
+	return info.capacity, nil
+}
+
+// Append adds the value to the end of Slice of given version.
+//
+// Complexity: O(1), or O(n) if the size of version has reached its capacity.
+// For Slice created with WithRRBTree option complexity is O(log32(n)).
+func (s *Slice[TVal]) Append(version uint64, val TVal) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	event := ChangeEvent{Op: OpAppend, Index: oldVersionInfo.size, NewValue: val}
+
+	if s.useRRBTree {
+		return s.commitRRBTree(version, oldVersionInfo.rrb.Append(val), oldVersionInfo.hash.pushBack(val), event)
+	}
+
+	return s.writeCells(version, oldVersionInfo, oldVersionInfo.size, []TVal{val}, oldVersionInfo.hash.pushBack(val), event)
+}
+
+// ToGoSlice converts persistent Slice for specified version into go slice.
+//
+// Complexity: O(Get) * n, there:
+//   - n - size of Slice for version.
+//
+// For Slice created with WithRRBTree option complexity is O(n).
+func (s *Slice[TVal]) ToGoSlice(forVersion uint64) ([]TVal, error) {
+	if s.useRRBTree {
+		info, err := s.versionTree.GetVersionInfo(forVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		return info.rrb.Values(), nil
+	}
+
+	var resSlice []TVal
+	err := s.walk(forVersion, func(_ int, val TVal) bool {
+		resSlice = append(resSlice, val)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resSlice, nil
+}
 
-	if len(s.sliceOfFatNodes) <= actualIndex {
-		return *new(TVal), ErrIndexOutOfRange
+// walk calls yield for values of given version in order, until yield returns false.
+// History of version is computed once for all values.
+func (s *Slice[TVal]) walk(version uint64, yield func(index int, val TVal) bool) error {
+	read, size, err := s.reader(version)
+	if err != nil {
+		return err
 	}
 
-	if version == 0 {
-		return *new(TVal), ErrIndexOutOfRange
+	for i := 0; i < size; i++ {
+		if !yield(i, read(i)) {
+			break
+		}
 	}
 
-	fatNode := s.sliceOfFatNodes[actualIndex]
+	return nil
+}
 
-	val, _, found := fatNode.FindByVersion(version)
-	if found {
-		return val.(TVal), nil
+// reader returns function, that reads value of given version by index less than size of the version,
+// and the size. History of version is computed once for all reads.
+func (s *Slice[TVal]) reader(version uint64) (func(index int) TVal, int, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if s.useRRBTree {
+		return func(index int) TVal {
+			val, _ := info.rrb.Get(index)
+			return val
+		}, info.size, nil
 	}
 
 	changeHistory, err := s.versionTree.GetHistory(version)
 	if err != nil {
-		return *new(TVal), err
+		return nil, 0, err
+	}
+
+	return func(index int) TVal {
+		val, _ := info.value(version, index, knownHistory(changeHistory))
+		return val
+	}, info.size, nil
+}
+
+// Equal reports whether versions v1 and v2 of Slice contain equal values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise same as for ToGoSlice.
+// Stale hashes are computed first, see ContentHash.
+func (s *Slice[TVal]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := s.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
 	}
 
-	if len(changeHistory) == 1 || len(changeHistory) == 2 {
-		return *new(TVal), ErrIndexOutOfRange
+	info2, err := s.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.size != info2.size {
+		return false, nil
+	}
+
+	hash1, err := s.knownHash(v1, info1)
+	if err != nil {
+		return false, err
 	}
 
-	for i := len(changeHistory) - 2; i >= 1; i-- {
-		val, _, found = fatNode.FindByVersion(changeHistory[i])
-		if found {
-			return val.(TVal), nil
+	hash2, err := s.knownHash(v2, info2)
+	if err != nil {
+		return false, err
+	}
+
+	if hashesDiffer(hash1, hash2) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalSequences(s.ToGoSlice, v1, v2)
+}
+
+// ContentHash returns hash of values of Slice of given version, that is maintained on each modification.
+// Versions with equal values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Hash of version created by Set, Range, FullRange, Slice or Truncate is stale: it is computed from values
+// on the first request and saved for the version.
+//
+// Complexity: O(1), for stale hash same as for ToGoSlice.
+func (s *Slice[TVal]) ContentHash(version uint64) (uint64, bool) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	hash, err := s.knownHash(version, info)
+	if err != nil {
+		return 0, false
+	}
+
+	return hash.contentHash()
+}
+
+// knownHash returns hash of version. Stale hash is computed from values and saved in info.
+func (s *Slice[TVal]) knownHash(version uint64, info *sliceVersionInfo[TVal]) (sequenceHash, error) {
+	if !info.hash.stale {
+		return info.hash, nil
+	}
+
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return sequenceHash{}, err
+	}
+
+	unlock := s.forks.lock()
+	info.hash = sequenceHashOf(values)
+	unlock()
+
+	return info.hash, nil
+}
+
+// Compact creates new Slice, which initial version has content of given version. Slice with Cells gets one Cell
+// with a single value for each index, Slice created with WithRRBTree option shares the tree with given version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: same as for ToGoSlice, with WithVersionMapping plus O(k) modifications,
+// where k - amount of versions created after given one.
+func (s *Slice[TVal]) Compact(version uint64, opts ...CompactOption) (*Slice[TVal], error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	sliceOpts := []SliceOption{WithBackend(s.backend)}
+	if s.useRRBTree {
+		sliceOpts = append(sliceOpts, WithRRBTree())
+	}
+	if s.subscribers.keepJournal {
+		sliceOpts = append(sliceOpts, WithJournal())
+	}
+	compacted, _ := NewSlice[TVal](sliceOpts...)
+
+	var storage *sliceStorage[TVal]
+	if !s.useRRBTree {
+		values, toGoErr := s.ToGoSlice(version)
+		if toGoErr != nil {
+			return nil, toGoErr
 		}
+
+		storage = compacted.initialStorage(values)
 	}
 
-	return *new(TVal), ErrIndexOutOfRange
+	_ = compacted.versionTree.SetVersionInfo(0, sliceVersionInfo[TVal]{
+		size:     info.size,
+		capacity: unboundedCapacity,
+		storage:  storage,
+		hash:     info.hash,
+		rrb:      info.rrb,
+	})
+
+	if err = s.replayDescendants(version, opts, s.rebuildEvent, compacted.applyEvent); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
 }
 
-// Len returns the len of Slice.
+// Fork creates new Slice, which initial version shares content of given version without copying it.
+// Slice created with WithRRBTree option shares the tree with given version. Slice with Cells reads Cells
+// of the original Slice for indexes, which are not set after Fork, and writes its own Cells.
+// Modifications of the new Slice don't create versions or Cell values of the original one, so after Fork returns
+// both of them can be modified concurrently. Modifications of the original Slice with Cells then lock out
+// reads of the shared Cells by the new Slice.
 //
-// Complexity: O(1).
-func (s *Slice[TVal]) Len(version uint64) (int, error) {
+// Complexity: O(d), where d - depth of given version. For Slice created with WithRRBTree option complexity is O(1).
+func (s *Slice[TVal]) Fork(version uint64) (*Slice[TVal], error) {
 	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	sliceOpts := []SliceOption{WithBackend(s.backend)}
+	if s.useRRBTree {
+		sliceOpts = append(sliceOpts, WithRRBTree())
+	}
+	if s.subscribers.keepJournal {
+		sliceOpts = append(sliceOpts, WithJournal())
+	}
+	forked, _ := NewSlice[TVal](sliceOpts...)
+
+	forkedInfo := *info
+	if !s.useRRBTree {
+		history, historyErr := s.versionTree.GetHistory(version)
+		if historyErr != nil {
+			return nil, historyErr
+		}
+
+		s.forks.forked.Store(true)
+		forkedInfo.startIndex, forkedInfo.capacity = 0, unboundedCapacity
+		forkedInfo.storage = &sliceStorage[TVal]{
+			base: &sliceBase[TVal]{
+				lock:    &s.forks,
+				info:    *info,
+				version: version,
+				history: history,
+			},
+		}
+	}
+
+	_ = forked.versionTree.SetVersionInfo(0, forkedInfo)
+
+	return forked, nil
+}
+
+// applyEvent repeats modification described by event for given version.
+func (s *Slice[TVal]) applyEvent(version uint64, event ChangeEvent) (uint64, error) {
+	val, _ := event.NewValue.(TVal)
+
+	switch event.Op {
+	case OpSet:
+		return s.Set(version, event.Index, val)
+	case OpAppend:
+		return s.Append(version, val)
+	case OpPrepend:
+		values, _ := event.NewValue.([]TVal)
+
+		return s.Prepend(version, values...)
+	case OpRange:
+		return s.Range(version, event.Index, event.Index+event.Count)
+	case OpTruncate:
+		return s.Truncate(version, event.Count)
+	case OpPopBack:
+		_, newVersion, err := s.Pop(version)
+
+		return newVersion, err
+	case OpConcat:
+		info, err := s.versionTree.GetVersionInfo(version)
+		if err != nil {
+			return 0, err
+		}
+
+		values, _ := event.NewValue.([]TVal)
+
+		return s.concatValues(version, info, values, info.hash.concat(sequenceHashOf(values)), event)
+	case OpReplace:
+		values, _ := event.NewValue.([]TVal)
+
+		return s.replaceValues(version, values)
+	default:
+		return 0, ErrNotFound
+	}
+}
+
+// Range takes the range of Slice for given version from startIndex (inclusive) to
+// endIndex (not inclusive), like s[startIndex:endIndex:endIndex] does for go slice.
+// The range may be empty. Capacity of new version is equal to its size, so modifications, that add values to it,
+// copy values to new storage instead of using Cells after endIndex.
+//
+// Complexity: O(1). For Slice created with WithRRBTree option complexity is O(log32(n)).
+func (s *Slice[TVal]) Range(forVersion uint64, startIndex, endIndex int) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(forVersion)
 	if err != nil {
 		return 0, err
 	}
 
-	return info.size, nil
+	if startIndex < 0 || startIndex > oldVersionInfo.size {
+		return 0, newIndexError(forVersion, startIndex, oldVersionInfo.size)
+	}
+
+	if endIndex < startIndex || endIndex > oldVersionInfo.size {
+		return 0, newIndexError(forVersion, endIndex, oldVersionInfo.size)
+	}
+
+	return s.slice(forVersion, oldVersionInfo, startIndex, endIndex, endIndex-startIndex)
 }
 
-// Append adds the value to the end of Slice of given version.
+// FullRange takes the range of Slice for given version from startIndex (inclusive) to endIndex (not inclusive)
+// with capacity maxIndex-startIndex, like s[startIndex:endIndex:maxIndex] does for go slice.
+// maxIndex must not be greater than capacity of given version.
 //
-// Complexity: O(1).
-func (s *Slice[TVal]) Append(version uint64, val TVal) (uint64, error) {
+// Complexity: O(1). For Slice created with WithRRBTree option complexity is O(log32(n)).
+func (s *Slice[TVal]) FullRange(forVersion uint64, startIndex, endIndex, maxIndex int) (uint64, error) {
+	capacity, err := s.Cap(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	oldVersionInfo, _ := s.versionTree.GetVersionInfo(forVersion)
+
+	if startIndex < 0 || startIndex > endIndex || endIndex > oldVersionInfo.size {
+		return 0, newRangeError(forVersion, startIndex, endIndex, oldVersionInfo.size)
+	}
+
+	if maxIndex < endIndex || maxIndex > capacity {
+		return 0, newIndexError(forVersion, maxIndex, capacity)
+	}
+
+	return s.slice(forVersion, oldVersionInfo, startIndex, endIndex, maxIndex-startIndex)
+}
+
+// Slice takes the range of Slice for given version from startIndex (inclusive) to
+// endIndex (not inclusive), like s[startIndex:endIndex] does for go slice.
+// Unlike Range, new version keeps the rest of capacity of given version.
+//
+// Complexity: O(1). For Slice created with WithRRBTree option complexity is O(log32(n)).
+func (s *Slice[TVal]) Slice(forVersion uint64, startIndex, endIndex int) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	if startIndex < 0 || startIndex > endIndex || endIndex > oldVersionInfo.size {
+		return 0, newRangeError(forVersion, startIndex, endIndex, oldVersionInfo.size)
+	}
+
+	capacity := oldVersionInfo.capacity
+	if capacity != unboundedCapacity {
+		capacity -= startIndex
+	}
+
+	return s.slice(forVersion, oldVersionInfo, startIndex, endIndex, capacity)
+}
+
+// Prepend adds values to the beginning of Slice of given version in a single new version.
+// If the values don't fit in capacity of version, values are copied to new storage.
+//
+// Complexity: O(n + k), there k - amount of given values.
+// For Slice created with WithRRBTree option complexity is O(k + log32(n)).
+func (s *Slice[TVal]) Prepend(version uint64, vals ...TVal) (uint64, error) {
 	oldVersionInfo, err := s.versionTree.GetVersionInfo(version)
 	if err != nil {
 		return 0, err
 	}
 
-	newVersion, err := s.versionTree.Update(version)
+	vals = slices.Clone(vals)
+	newHash := sequenceHashOf(vals).concat(oldVersionInfo.hash)
+	event := ChangeEvent{Op: OpPrepend, Count: len(vals), NewValue: vals}
+
+	if s.useRRBTree {
+		return s.commitRRBTree(version, internal.NewRRBTree(vals).Concat(oldVersionInfo.rrb), newHash, event)
+	}
+
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.writeCells(version, oldVersionInfo, 0, append(slices.Clone(vals), values...), newHash, event)
+}
+
+// Truncate keeps first n values of Slice of given version, like s[:n] does for go slice.
+// New version keeps capacity of given version.
+//
+// Complexity: O(1). For Slice created with WithRRBTree option complexity is O(log32(n)).
+func (s *Slice[TVal]) Truncate(version uint64, n int) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(version)
 	if err != nil {
 		return 0, err
 	}
 
-	newVersionInfo := sliceVersionInfo{
-		size:       oldVersionInfo.size + 1,
-		startIndex: oldVersionInfo.startIndex,
+	if n < 0 || n > oldVersionInfo.size {
+		return 0, newIndexError(version, n, oldVersionInfo.size)
 	}
 
-	actualIndex := oldVersionInfo.startIndex + oldVersionInfo.size
+	return s.truncate(version, oldVersionInfo, n, oldVersionInfo.rangeHash(0, n), ChangeEvent{Op: OpTruncate, Count: n})
+}
 
-	if actualIndex >= len(s.sliceOfFatNodes) {
-		newFatNode := internal.NewFatNode(val, newVersion)
-		s.sliceOfFatNodes = append(s.sliceOfFatNodes, newFatNode)
-	} else {
-		existedFatNode := s.sliceOfFatNodes[actualIndex]
-		existedFatNode.Update(val, newVersion)
+// Resize changes size of Slice of given version to n. If n is less than the size, Resize works as Truncate,
+// otherwise missing values are set to fill.
+//
+// Complexity: same as for Truncate or O(k) to add k values.
+// For Slice created with WithRRBTree option complexity is O(k + log32(n)).
+func (s *Slice[TVal]) Resize(version uint64, n int, fill TVal) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	if n < 0 {
+		return 0, newIndexError(version, n, oldVersionInfo.size)
+	}
+
+	if n <= oldVersionInfo.size {
+		return s.Truncate(version, n)
+	}
+
+	values := slices.Repeat([]TVal{fill}, n-oldVersionInfo.size)
+	event := ChangeEvent{Op: OpConcat, Index: oldVersionInfo.size, Count: len(values), NewValue: values}
+
+	return s.concatValues(version, oldVersionInfo, values, oldVersionInfo.hash.concat(sequenceHashOf(values)), event)
+}
+
+// Pop removes the last value of Slice of given version. Returns removed value and new version.
+// If Slice is empty ErrIndexOutOfRange is returned.
+//
+// Complexity: same as for Get.
+func (s *Slice[TVal]) Pop(version uint64) (TVal, uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(TVal), 0, err
+	}
+
+	if oldVersionInfo.size == 0 {
+		return *new(TVal), 0, newIndexError(version, 0, 0)
+	}
+
+	last := oldVersionInfo.size - 1
+
+	val, err := s.Get(version, last)
+	if err != nil {
+		return *new(TVal), 0, err
+	}
+
+	event := ChangeEvent{Op: OpPopBack, Index: last, OldValue: val}
+
+	newVersion, err := s.truncate(version, oldVersionInfo, last, oldVersionInfo.hash.popBack(val), event)
+	if err != nil {
+		return *new(TVal), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// AppendSlice adds values of other Slice of otherVersion to the end of Slice of given version,
+// like append(s, other...) does for go slices. It is the same as Concat.
+//
+// Complexity: same as for Concat.
+func (s *Slice[TVal]) AppendSlice(version uint64, other *Slice[TVal], otherVersion uint64) (uint64, error) {
+	return s.Concat(version, other, otherVersion)
+}
+
+// rangeHash returns hash of values from startIndex (inclusive) to endIndex (not inclusive).
+// Hash of the part of values can't be found without reading them, so it becomes stale.
+func (info *sliceVersionInfo[TVal]) rangeHash(startIndex, endIndex int) sequenceHash {
+	switch {
+	case startIndex == endIndex:
+		return sequenceHash{}
+	case startIndex == 0 && endIndex == info.size:
+		return info.hash
+	default:
+		return info.hash.invalidate()
 	}
+}
+
+// truncate creates new version with given hash, which has first n values of forVersion.
+// Subscribers are notified with given event.
+func (s *Slice[TVal]) truncate(
+	forVersion uint64,
+	oldVersionInfo *sliceVersionInfo[TVal],
+	n int,
+	hash sequenceHash,
+	event ChangeEvent,
+) (uint64, error) {
+	if s.useRRBTree {
+		newRRB, _ := oldVersionInfo.rrb.Slice(0, n)
+		return s.commitRRBTree(forVersion, newRRB, hash, event)
+	}
+
+	unlock := s.forks.lock()
+	newVersion, err := s.versionTree.Update(forVersion)
+	if err != nil {
+		unlock()
+		return 0, err
+	}
+
+	newVersionInfo := *oldVersionInfo
+	newVersionInfo.size = n
+	newVersionInfo.hash = hash
 
 	_ = s.versionTree.SetVersionInfo(newVersion, newVersionInfo)
+	unlock()
+
+	s.notify(forVersion, newVersion, event)
 
 	return newVersion, nil
 }
 
-// ToGoSlice converts persistent Slice for specified version into go slice.
+// Concat adds values of other Slice of otherVersion to the end of Slice of given version.
+// Other Slice may be the same as Slice.
 //
-// Complexity: O(Get) * n, there:
-//   - n - size of Slice for version.
-func (s *Slice[TVal]) ToGoSlice(forVersion uint64) ([]TVal, error) {
-	size, err := s.Len(forVersion)
+// Complexity: O(m), there m - size of other Slice.
+// If both Slices are created with WithRRBTree option complexity is O(log32(n + m)).
+func (s *Slice[TVal]) Concat(version uint64, other *Slice[TVal], otherVersion uint64) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(version)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	resSlice := make([]TVal, 0, size)
+	otherVersionInfo, err := other.versionTree.GetVersionInfo(otherVersion)
+	if err != nil {
+		return 0, err
+	}
+	newHash := oldVersionInfo.hash.concat(otherVersionInfo.hash)
+	event := ChangeEvent{Op: OpConcat, Index: oldVersionInfo.size, Count: otherVersionInfo.size}
 
-	for i := 0; i < size; i++ {
-		val, err := s.Get(forVersion, i)
-		if err != nil {
-			return nil, err
+	if s.useRRBTree && other.useRRBTree {
+		if s.subscribed() {
+			event.NewValue = otherVersionInfo.rrb.Values()
 		}
 
-		resSlice = append(resSlice, val)
+		return s.commitRRBTree(version, oldVersionInfo.rrb.Concat(otherVersionInfo.rrb), newHash, event)
 	}
 
-	return resSlice, nil
+	values, err := other.ToGoSlice(otherVersion)
+	if err != nil {
+		return 0, err
+	}
+	event.NewValue = values
+
+	return s.concatValues(version, oldVersionInfo, values, newHash, event)
 }
 
-// Range takes the range of Slice for given version from startIndex (inclusive) to
-// endIndex (not inclusive).
-//
-// Complexity: O(1).
-func (s *Slice[TVal]) Range(forVersion uint64, startIndex, endIndex int) (uint64, error) {
-	if startIndex < 0 || endIndex < 0 {
-		return 0, ErrIndexOutOfRange
+// concatValues creates new version with given hash, in which values are added to the end of forVersion.
+func (s *Slice[TVal]) concatValues(
+	forVersion uint64,
+	oldVersionInfo *sliceVersionInfo[TVal],
+	values []TVal,
+	hash sequenceHash,
+	event ChangeEvent,
+) (uint64, error) {
+	if s.useRRBTree {
+		return s.commitRRBTree(forVersion, oldVersionInfo.rrb.Concat(internal.NewRRBTree(values)), hash, event)
 	}
 
-	if startIndex > endIndex {
-		return 0, ErrIndexOutOfRange
+	return s.writeCells(forVersion, oldVersionInfo, oldVersionInfo.size, values, hash, event)
+}
+
+func (s *Slice[TVal]) slice(
+	forVersion uint64,
+	oldVersionInfo *sliceVersionInfo[TVal],
+	startIndex, endIndex, capacity int,
+) (uint64, error) {
+	event := ChangeEvent{Op: OpRange, Index: startIndex, Count: endIndex - startIndex}
+
+	hash := oldVersionInfo.rangeHash(startIndex, endIndex)
+
+	if s.useRRBTree {
+		newRRB, _ := oldVersionInfo.rrb.Slice(startIndex, endIndex)
+		return s.commitRRBTree(forVersion, newRRB, hash, event)
+	}
+
+	unlock := s.forks.lock()
+	newVersion, err := s.versionTree.Update(forVersion)
+	if err != nil {
+		unlock()
+		return 0, err
+	}
+
+	newVersionInfo := sliceVersionInfo[TVal]{
+		size:       endIndex - startIndex,
+		startIndex: oldVersionInfo.startIndex + startIndex,
+		capacity:   capacity,
+		storage:    oldVersionInfo.storage,
+		hash:       hash,
 	}
 
+	_ = s.versionTree.SetVersionInfo(newVersion, newVersionInfo)
+	unlock()
+
+	s.notify(forVersion, newVersion, event)
+
+	return newVersion, nil
+}
+
+// replaceValues creates new version, which has only given values. Subscribers are notified with OpReplace event.
+func (s *Slice[TVal]) replaceValues(forVersion uint64, values []TVal) (uint64, error) {
 	oldVersionInfo, err := s.versionTree.GetVersionInfo(forVersion)
 	if err != nil {
 		return 0, err
 	}
 
-	if startIndex >= oldVersionInfo.size {
-		return 0, ErrIndexOutOfRange
+	event := ChangeEvent{Op: OpReplace, Count: len(values), NewValue: values}
+
+	if s.useRRBTree {
+		return s.commitRRBTree(forVersion, internal.NewRRBTree(values), sequenceHashOf(values), event)
 	}
 
-	if endIndex > oldVersionInfo.size {
-		return 0, ErrIndexOutOfRange
+	return s.writeCells(forVersion, oldVersionInfo, 0, values, sequenceHashOf(values), event)
+}
+
+// writeCells creates new version with given hash, which has given values starting from index, and values
+// before index taken from forVersion. If the values don't fit in capacity of forVersion, all of them are
+// written to new storage with unbounded capacity. Subscribers are notified with given event.
+func (s *Slice[TVal]) writeCells(
+	forVersion uint64,
+	oldVersionInfo *sliceVersionInfo[TVal],
+	index int,
+	values []TVal,
+	hash sequenceHash,
+	event ChangeEvent,
+) (uint64, error) {
+	newVersionInfo := *oldVersionInfo
+	newVersionInfo.size = index + len(values)
+	newVersionInfo.hash = hash
+
+	fits := oldVersionInfo.capacity == unboundedCapacity || newVersionInfo.size <= oldVersionInfo.capacity
+
+	var kept []TVal
+	if !fits {
+		read, _, err := s.reader(forVersion)
+		if err != nil {
+			return 0, err
+		}
+
+		kept = make([]TVal, index)
+		for i := range kept {
+			kept[i] = read(i)
+		}
 	}
 
-	newVersion, _ := s.versionTree.Update(forVersion)
-	newVersionInfo := sliceVersionInfo{
-		size:       endIndex - startIndex,
-		startIndex: oldVersionInfo.startIndex + startIndex,
+	unlock := s.forks.lock()
+	newVersion, err := s.versionTree.Update(forVersion)
+	if err != nil {
+		unlock()
+		return 0, err
 	}
 
+	if !fits {
+		newVersionInfo.startIndex, newVersionInfo.capacity = 0, unboundedCapacity
+		newVersionInfo.storage = &sliceStorage[TVal]{cells: make([]internal.Cell, 0, 2*newVersionInfo.size)}
+		s.writeValues(newVersion, &newVersionInfo, 0, kept)
+	}
+
+	s.writeValues(newVersion, &newVersionInfo, index, values)
+
 	_ = s.versionTree.SetVersionInfo(newVersion, newVersionInfo)
+	unlock()
+
+	s.notify(forVersion, newVersion, event)
 
 	return newVersion, nil
 }
+
+// writeValues writes values to Cells of given version info starting from index,
+// adding new Cells to the storage if needed.
+func (s *Slice[TVal]) writeValues(version uint64, info *sliceVersionInfo[TVal], index int, values []TVal) {
+	store := s.versionTree.Store()
+	for i, val := range values {
+		actualIndex := info.startIndex + index + i
+		switch {
+		case actualIndex >= len(info.storage.cells):
+			// storage created by Fork has no Cells for positions, which are not written yet.
+			for len(info.storage.cells) < actualIndex {
+				info.storage.cells = append(info.storage.cells, nil)
+			}
+			info.storage.cells = append(info.storage.cells, s.backend.NewCell(store, version, val))
+		case info.storage.cells[actualIndex] == nil:
+			info.storage.cells[actualIndex] = s.backend.NewCell(store, version, val)
+		default:
+			info.storage.cells[actualIndex].Write(version, val)
+		}
+	}
+}
+
+// initialStorage creates storage with Cells, which have given values for the initial version.
+func (s *Slice[TVal]) initialStorage(values []TVal) *sliceStorage[TVal] {
+	storage := &sliceStorage[TVal]{cells: make([]internal.Cell, 0, len(values))}
+
+	store := s.versionTree.Store()
+	for _, val := range values {
+		storage.cells = append(storage.cells, s.backend.NewCell(store, 0, val))
+	}
+
+	return storage
+}
+
+// value returns value of version by index. If storage has no value for the index, false is returned.
+func (info *sliceVersionInfo[TVal]) value(version uint64, index int, history func() []uint64) (TVal, bool) {
+	return info.storage.value(info.startIndex+index, version, history)
+}
+
+// value returns value of version by position in storage. Positions without own values are read from base.
+func (storage *sliceStorage[TVal]) value(position int, version uint64, history func() []uint64) (TVal, bool) {
+	if position < len(storage.cells) && storage.cells[position] != nil {
+		if val, found := readCell(storage.cells[position], version, history); found {
+			typed, _ := val.(TVal)
+			return typed, true
+		}
+	}
+
+	if storage.base == nil || position >= storage.base.info.size {
+		return *new(TVal), false
+	}
+
+	return storage.base.value(position)
+}
+
+// value returns value of the forked version by index.
+func (b *sliceBase[TVal]) value(index int) (TVal, bool) {
+	b.lock.mu.RLock()
+	defer b.lock.mu.RUnlock()
+
+	return b.info.value(b.version, index, knownHistory(b.history))
+}
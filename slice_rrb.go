@@ -0,0 +1,62 @@
+package go_persistent_ds
+
+import (
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+func (s *Slice[TVal]) setToRRBTree(forVersion uint64, index int, val TVal) (uint64, error) {
+	oldVersionInfo, err := s.versionTree.GetVersionInfo(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	oldVal, ok := oldVersionInfo.rrb.Get(index)
+	if !ok {
+		return 0, newIndexError(forVersion, index, oldVersionInfo.size)
+	}
+
+	newRRB, _ := oldVersionInfo.rrb.Set(index, val)
+
+	return s.commitRRBTree(
+		forVersion,
+		newRRB,
+		oldVersionInfo.hash.set(index, oldVal, val),
+		ChangeEvent{Op: OpSet, Index: index, OldValue: oldVal, NewValue: val},
+	)
+}
+
+func (s *Slice[TVal]) getFromRRBTree(version uint64, index int) (TVal, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(TVal), err
+	}
+
+	val, ok := info.rrb.Get(index)
+	if !ok {
+		return *new(TVal), newIndexError(version, index, info.size)
+	}
+
+	return val, nil
+}
+
+func (s *Slice[TVal]) commitRRBTree(
+	forVersion uint64,
+	rrb internal.RRBTree[TVal],
+	hash sequenceHash,
+	event ChangeEvent,
+) (uint64, error) {
+	newVersion, err := s.versionTree.Update(forVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = s.versionTree.SetVersionInfo(newVersion, sliceVersionInfo[TVal]{
+		size: rrb.Len(),
+		hash: hash,
+		rrb:  rrb,
+	})
+
+	s.notify(forVersion, newVersion, event)
+
+	return newVersion, nil
+}
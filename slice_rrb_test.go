@@ -0,0 +1,146 @@
+package go_persistent_ds
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func sliceShouldBe(t *testing.T, s *Slice[int], version uint64, expected []int) {
+	t.Helper()
+
+	slice, err := s.ToGoSlice(version)
+	errIsNil(t, err)
+	if !slices.Equal(slice, expected) {
+		t.Errorf("expected slice: %v, got: %v", expected, slice)
+	}
+
+	size, err := s.Len(version)
+	errIsNil(t, err)
+	isTrue(t, size == len(expected))
+}
+
+func TestSlice_PrependConcatSlice(t *testing.T) {
+	representations := map[string][]SliceOption{
+		"FatNodes": nil,
+		"RRBTree":  {WithRRBTree()},
+	}
+
+	for name, opts := range representations {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, version := NewSlice[int](opts...)
+
+			var err error
+			for i := 1; i <= 3; i++ {
+				version, err = s.Append(version, i)
+				errIsNil(t, err)
+			}
+			appendedVersion := version
+
+			v, err := s.Prepend(appendedVersion, 0)
+			errIsNil(t, err)
+			versionShouldBe(t, v, 4)
+			sliceShouldBe(t, s, 4, []int{0, 1, 2, 3})
+			sliceShouldBe(t, s, appendedVersion, []int{1, 2, 3})
+
+			v, err = s.Concat(4, s, appendedVersion)
+			errIsNil(t, err)
+			versionShouldBe(t, v, 5)
+			sliceShouldBe(t, s, 5, []int{0, 1, 2, 3, 1, 2, 3})
+
+			other, otherVersion := NewSlice[int]()
+			otherVersion, err = other.Append(otherVersion, 10)
+			errIsNil(t, err)
+
+			v, err = s.Concat(5, other, otherVersion)
+			errIsNil(t, err)
+			versionShouldBe(t, v, 6)
+			sliceShouldBe(t, s, 6, []int{0, 1, 2, 3, 1, 2, 3, 10})
+
+			v, err = s.Slice(6, 2, 5)
+			errIsNil(t, err)
+			versionShouldBe(t, v, 7)
+			sliceShouldBe(t, s, 7, []int{2, 3, 1})
+
+			v, err = s.Slice(7, 3, 3)
+			errIsNil(t, err)
+			versionShouldBe(t, v, 8)
+			sliceShouldBe(t, s, 8, []int{})
+
+			_, err = s.Slice(7, 2, 4)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			_, err = s.Slice(7, 2, 1)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			val, err := s.Get(7, 1)
+			errIsNil(t, err)
+			isTrue(t, val == 3)
+
+			sliceShouldBe(t, s, 6, []int{0, 1, 2, 3, 1, 2, 3, 10})
+		})
+	}
+}
+
+func TestSlice_WithRRBTree(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, version := NewSlice[int](WithRRBTree())
+	versions := []uint64{version}
+	expected := [][]int{nil}
+
+	for i := 0; i < 500; i++ {
+		from := r.Intn(len(versions))
+		forVersion, model := versions[from], slices.Clone(expected[from])
+
+		var err error
+		switch r.Intn(5) {
+		case 0:
+			version, err = s.Append(forVersion, i)
+			model = append(model, i)
+		case 1:
+			version, err = s.Prepend(forVersion, i)
+			model = append([]int{i}, model...)
+		case 2:
+			other := r.Intn(len(versions))
+			version, err = s.Concat(forVersion, s, versions[other])
+			model = append(model, expected[other]...)
+		case 3:
+			start := r.Intn(len(model) + 1)
+			end := start + r.Intn(len(model)-start+1)
+			version, err = s.Slice(forVersion, start, end)
+			model = model[start:end]
+		case 4:
+			if len(model) == 0 {
+				_, err = s.Set(forVersion, 0, i)
+				errShouldBe(t, err, ErrIndexOutOfRange)
+				continue
+			}
+			index := r.Intn(len(model))
+			version, err = s.Set(forVersion, index, i)
+			model[index] = i
+		}
+		errIsNil(t, err)
+
+		if len(model) > 10000 {
+			continue
+		}
+		versions = append(versions, version)
+		expected = append(expected, model)
+	}
+
+	for i, version := range versions {
+		sliceShouldBe(t, s, version, expected[i])
+
+		for index, expectedVal := range expected[i] {
+			val, err := s.Get(version, index)
+			errIsNil(t, err)
+			isTrue(t, val == expectedVal)
+		}
+
+		_, err := s.Get(version, len(expected[i]))
+		errShouldBe(t, err, ErrIndexOutOfRange)
+	}
+}
@@ -515,8 +515,12 @@ func TestSlice_Range(t *testing.T) {
 		versionShouldBe(t, initialVersion, 0)
 
 		v, err := s.Range(0, 0, 0)
-		errShouldBe(t, err, ErrIndexOutOfRange)
-		versionShouldBe(t, v, 0)
+		errIsNil(t, err)
+		versionShouldBe(t, v, 1)
+
+		size, err := s.Len(1)
+		errIsNil(t, err)
+		isTrue(t, size == 0)
 	})
 
 	t.Run("With branched slice", func(t *testing.T) {
@@ -628,3 +632,202 @@ func TestSliceWithAnyTypes(t *testing.T) {
 		}())
 	})
 }
+
+func TestSlice_RangeCapacity(t *testing.T) {
+	for name, opts := range map[string][]SliceOption{
+		"Cells":   nil,
+		"RRBTree": {WithRRBTree()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newSliceOfValues([]string{"a", "b", "c", "d", "e"}, opts...)
+
+			ranged, err := s.Range(0, 0, 2)
+			errIsNil(t, err)
+
+			capacity, err := s.Cap(ranged)
+			errIsNil(t, err)
+			isTrue(t, capacity == 2)
+
+			appended, err := s.Append(ranged, "x")
+			errIsNil(t, err)
+
+			values, err := s.ToGoSlice(appended)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []string{"a", "b", "x"}))
+
+			values, err = s.ToGoSlice(0)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []string{"a", "b", "c", "d", "e"}))
+
+			_, err = s.Get(ranged, 2)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			_, err = s.Set(ranged, 2, "y")
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			empty, err := s.Range(0, 3, 3)
+			errIsNil(t, err)
+
+			appended, err = s.Append(empty, "y")
+			errIsNil(t, err)
+
+			values, err = s.ToGoSlice(appended)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []string{"y"}))
+
+			_, err = s.FullRange(0, 1, 2, 6)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			_, err = s.FullRange(0, 2, 1, 3)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+		})
+	}
+
+	t.Run("Cells storage", func(t *testing.T) {
+		t.Parallel()
+
+		s := newSliceOfValues([]string{"a", "b", "c", "d", "e"})
+		original, _ := s.versionTree.GetVersionInfo(0)
+
+		shared, err := s.FullRange(0, 1, 2, 4)
+		errIsNil(t, err)
+
+		capacity, err := s.Cap(shared)
+		errIsNil(t, err)
+		isTrue(t, capacity == 3)
+
+		shared, err = s.Append(shared, "x")
+		errIsNil(t, err)
+		info, _ := s.versionTree.GetVersionInfo(shared)
+		isTrue(t, info.storage == original.storage)
+
+		shared, err = s.Append(shared, "y")
+		errIsNil(t, err)
+		info, _ = s.versionTree.GetVersionInfo(shared)
+		isTrue(t, info.storage == original.storage)
+
+		// capacity is exhausted, so the next Append copies values to new storage.
+		shared, err = s.Append(shared, "z")
+		errIsNil(t, err)
+		info, _ = s.versionTree.GetVersionInfo(shared)
+		isTrue(t, info.storage != original.storage)
+		isTrue(t, len(info.storage.cells) == 4)
+
+		values, err := s.ToGoSlice(shared)
+		errIsNil(t, err)
+		isTrue(t, slices.Equal(values, []string{"b", "x", "y", "z"}))
+
+		values, err = s.ToGoSlice(0)
+		errIsNil(t, err)
+		isTrue(t, slices.Equal(values, []string{"a", "b", "c", "d", "e"}))
+
+		// Slice keeps capacity of given version, like s[i:j] does.
+		sliced, err := s.Slice(0, 1, 2)
+		errIsNil(t, err)
+
+		sliced, err = s.Append(sliced, "w")
+		errIsNil(t, err)
+		info, _ = s.versionTree.GetVersionInfo(sliced)
+		isTrue(t, info.storage == original.storage)
+
+		values, err = s.ToGoSlice(sliced)
+		errIsNil(t, err)
+		isTrue(t, slices.Equal(values, []string{"b", "w"}))
+	})
+}
+
+func TestSlice_Resizing(t *testing.T) {
+	for name, opts := range map[string][]SliceOption{
+		"Cells":   nil,
+		"RRBTree": {WithRRBTree()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, v := NewSlice[int](opts...)
+			var ops []OpKind
+			unsubscribe := s.Subscribe(func(ev ChangeEvent) {
+				ops = append(ops, ev.Op)
+			})
+			defer unsubscribe()
+
+			shouldHave := func(version uint64, expected []int) {
+				t.Helper()
+
+				values, err := s.ToGoSlice(version)
+				errIsNil(t, err)
+				isTrue(t, slices.Equal(values, expected))
+
+				equal, err := Equal[int](s, version, newSliceOfValues(expected), 0)
+				errIsNil(t, err)
+				isTrue(t, equal)
+			}
+
+			v1, err := s.Prepend(v, 3, 4)
+			errIsNil(t, err)
+			versionShouldBe(t, v1, v+1)
+			shouldHave(v1, []int{3, 4})
+
+			v2, err := s.Prepend(v1, 1, 2)
+			errIsNil(t, err)
+			versionShouldBe(t, v2, v1+1)
+			shouldHave(v2, []int{1, 2, 3, 4})
+
+			v3, err := s.AppendSlice(v2, s, v1)
+			errIsNil(t, err)
+			versionShouldBe(t, v3, v2+1)
+			shouldHave(v3, []int{1, 2, 3, 4, 3, 4})
+
+			v4, err := s.Truncate(v3, 3)
+			errIsNil(t, err)
+			versionShouldBe(t, v4, v3+1)
+			shouldHave(v4, []int{1, 2, 3})
+
+			v5, err := s.Resize(v4, 6, 9)
+			errIsNil(t, err)
+			versionShouldBe(t, v5, v4+1)
+			shouldHave(v5, []int{1, 2, 3, 9, 9, 9})
+
+			v6, err := s.Resize(v5, 2, 0)
+			errIsNil(t, err)
+			versionShouldBe(t, v6, v5+1)
+			shouldHave(v6, []int{1, 2})
+
+			hash, ok := s.ContentHash(v6)
+			expectedHash, _ := newSliceOfValues([]int{1, 2}).ContentHash(0)
+			isTrue(t, ok && hash == expectedHash)
+
+			val, v7, err := s.Pop(v6)
+			errIsNil(t, err)
+			versionShouldBe(t, v7, v6+1)
+			isTrue(t, val == 2)
+			shouldHave(v7, []int{1})
+
+			_, v8, err := s.Pop(v7)
+			errIsNil(t, err)
+			shouldHave(v8, []int{})
+
+			_, _, err = s.Pop(v8)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			_, err = s.Truncate(v5, 7)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			_, err = s.Resize(v5, -1, 0)
+			errShouldBe(t, err, ErrIndexOutOfRange)
+
+			shouldHave(v3, []int{1, 2, 3, 4, 3, 4})
+
+			_, popped, err := s.Pop(v3)
+			errIsNil(t, err)
+
+			hash, ok = s.ContentHash(popped)
+			expectedHash, _ = newSliceOfValues([]int{1, 2, 3, 4, 3}).ContentHash(0)
+			isTrue(t, ok && hash == expectedHash)
+
+			isTrue(t, slices.Equal(ops, []OpKind{OpPrepend, OpPrepend, OpConcat, OpTruncate, OpConcat, OpTruncate, OpPopBack, OpPopBack, OpPopBack}))
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package go_persistent_ds
+
+import (
+	"slices"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// Stack is a persistent LIFO stack.
+// While working with stack you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Each version keeps immutable list of values with the top of Stack at its head,
+// so versions share structure and every operation takes O(1).
+//
+// Stack can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Stack, the good idea is to use Compact method, that creates new Stack
+// with special version as the initial one.
+//
+// Note that Stack is not thread safe.
+type Stack[T any] struct {
+	versionHistory[stackVersionInfo[T]]
+}
+
+type stackVersionInfo[T any] struct {
+	values internal.RandomAccessList[T]
+	hash   sequenceHash
+}
+
+// NewStack creates empty Stack.
+func NewStack[T any]() (*Stack[T], uint64) {
+	return &Stack[T]{
+		versionHistory: newVersionHistory(internal.NewVersionTree[stackVersionInfo[T]]()),
+	}, 0
+}
+
+// NewStackWithAnyValues creates a Stack, that can store values of any type.
+func NewStackWithAnyValues() (*Stack[any], uint64) {
+	return NewStack[any]()
+}
+
+// Push adds the value to the top of Stack of given version. Returns Stack's new version.
+//
+// Complexity: O(1).
+func (s *Stack[T]) Push(version uint64, val T) (uint64, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.commit(version, stackVersionInfo[T]{
+		values: info.values.Cons(val),
+		hash:   info.hash.pushBack(val),
+	}, ChangeEvent{Op: OpPush, Index: info.values.Len(), NewValue: val})
+}
+
+// Pop removes the value from the top of Stack of given version.
+// Returns removed value and Stack's new version.
+// If Stack is empty ErrEmpty is returned.
+//
+// Complexity: O(1).
+func (s *Stack[T]) Pop(version uint64) (T, uint64, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	val, ok := info.values.Head()
+	if !ok {
+		return *new(T), 0, ErrEmpty
+	}
+
+	newVersion, err := s.commit(version, stackVersionInfo[T]{
+		values: info.values.Tail(),
+		hash:   info.hash.popBack(val),
+	}, ChangeEvent{Op: OpPop, Index: info.values.Len() - 1, OldValue: val})
+	if err != nil {
+		return *new(T), 0, err
+	}
+
+	return val, newVersion, nil
+}
+
+// Peek returns the value from the top of Stack of given version.
+// If Stack is empty ErrEmpty is returned.
+//
+// Complexity: O(1).
+func (s *Stack[T]) Peek(version uint64) (T, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(T), err
+	}
+
+	val, ok := info.values.Head()
+	if !ok {
+		return *new(T), ErrEmpty
+	}
+
+	return val, nil
+}
+
+// Len returns the len of Stack.
+//
+// Complexity: O(1).
+func (s *Stack[T]) Len(version uint64) (int, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.values.Len(), nil
+}
+
+// ToGoSlice converts persistent Stack for specified version into go slice.
+// The bottom of Stack becomes the first element and the top becomes the last one,
+// so pushing to Stack corresponds to appending to the slice.
+//
+// Complexity: O(n).
+func (s *Stack[T]) ToGoSlice(version uint64) ([]T, error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	values := info.values.Values()
+	slices.Reverse(values)
+
+	return values, nil
+}
+
+// Equal reports whether versions v1 and v2 of Stack contain equal values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n).
+func (s *Stack[T]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := s.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := s.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.values.Len() != info2.values.Len() || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalSequences(s.ToGoSlice, v1, v2)
+}
+
+// ContentHash returns hash of values of Stack of given version, that is maintained on each modification.
+// Versions with equal values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (s *Stack[T]) ContentHash(version uint64) (uint64, bool) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new Stack, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (s *Stack[T]) Compact(version uint64, opts ...CompactOption) (*Stack[T], error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewStack[T]()
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = s.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new Stack, which initial version shares content of given version without copying it.
+// Modifications of the new Stack don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (s *Stack[T]) Fork(version uint64) (*Stack[T], error) {
+	info, err := s.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewStack[T]()
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+func (s *Stack[T]) commit(version uint64, info stackVersionInfo[T], event ChangeEvent) (uint64, error) {
+	newVersion, err := s.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = s.versionTree.SetVersionInfo(newVersion, info)
+
+	s.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
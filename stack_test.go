@@ -0,0 +1,70 @@
+package go_persistent_ds
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStack_PushPopPeek(t *testing.T) {
+	s, initialVersion := NewStack[string]()
+	versionShouldBe(t, initialVersion, 0)
+
+	_, _, err := s.Pop(0)
+	errShouldBe(t, err, ErrEmpty)
+	_, err = s.Peek(0)
+	errShouldBe(t, err, ErrEmpty)
+
+	v, err := s.Push(0, "a")
+	errIsNil(t, err)
+	versionShouldBe(t, v, 1)
+
+	v, err = s.Push(1, "b")
+	errIsNil(t, err)
+	versionShouldBe(t, v, 2)
+
+	v, err = s.Push(1, "c")
+	errIsNil(t, err)
+	versionShouldBe(t, v, 3)
+
+	top, err := s.Peek(2)
+	errIsNil(t, err)
+	isTrue(t, top == "b")
+
+	top, err = s.Peek(3)
+	errIsNil(t, err)
+	isTrue(t, top == "c")
+
+	val, v, err := s.Pop(3)
+	errIsNil(t, err)
+	versionShouldBe(t, v, 4)
+	isTrue(t, val == "c")
+
+	size, err := s.Len(4)
+	errIsNil(t, err)
+	isTrue(t, size == 1)
+
+	slice, err := s.ToGoSlice(2)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []string{"a", "b"}))
+
+	slice, err = s.ToGoSlice(4)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(slice, []string{"a"}))
+}
+
+func TestStackWithAnyValues(t *testing.T) {
+	s, version := NewStackWithAnyValues()
+
+	version, err := s.Push(version, 1)
+	errIsNil(t, err)
+	version, err = s.Push(version, "a")
+	errIsNil(t, err)
+
+	val, version, err := s.Pop(version)
+	errIsNil(t, err)
+	isTrue(t, val == "a")
+
+	val, err = s.Peek(version)
+	errIsNil(t, err)
+	isTrue(t, val == 1)
+}
@@ -0,0 +1,137 @@
+package go_persistent_ds
+
+import (
+	"slices"
+)
+
+// Transformable is an Indexed structure, which elements can be replaced by a single new version:
+// Slice and DoubleLinkedList.
+type Transformable[T any] interface {
+	Indexed[T]
+	// replaceValues creates new version, which has only given values, and notifies subscribers with OpReplace event.
+	replaceValues(version uint64, values []T) (uint64, error)
+}
+
+var (
+	_ Transformable[int] = (*Slice[int])(nil)
+	_ Transformable[int] = (*DoubleLinkedList[int])(nil)
+)
+
+// MapValues replaces each value of Map of given version with the result of fn. All values are replaced
+// by a single new version, which is returned.
+//
+// Complexity: O(n + k), where n - size of Map and k - complexity of ToGoMap.
+func MapValues[K comparable, V any](m *Map[K, V], version uint64, fn func(key K, val V) V) (uint64, error) {
+	values, err := m.ToGoMap(version)
+	if err != nil {
+		return 0, err
+	}
+
+	for key, val := range values {
+		values[key] = fn(key, val)
+	}
+
+	return m.replaceValues(version, values)
+}
+
+// FilterKeyed deletes keys of Map of given version, for which pred returns false. All keys are deleted
+// by a single new version, which is returned.
+//
+// Complexity: O(n + k), where n - size of Map and k - complexity of ToGoMap.
+func FilterKeyed[K comparable, V any](m *Map[K, V], version uint64, pred func(key K, val V) bool) (uint64, error) {
+	values, err := m.ToGoMap(version)
+	if err != nil {
+		return 0, err
+	}
+
+	for key, val := range values {
+		if !pred(key, val) {
+			delete(values, key)
+		}
+	}
+
+	return m.replaceValues(version, values)
+}
+
+// Filter removes elements of Slice or DoubleLinkedList of given version, for which pred returns false.
+// Order of kept elements is preserved. All elements are removed by a single new version, which is returned.
+//
+// Complexity: O(n + k), where n - size of structure and k - complexity of ToGoSlice.
+func Filter[T any](s Transformable[T], version uint64, pred func(val T) bool) (uint64, error) {
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.replaceValues(version, slices.DeleteFunc(values, func(val T) bool {
+		return !pred(val)
+	}))
+}
+
+// SortSlice sorts elements of Slice or DoubleLinkedList of given version by cmp, equal elements keep their order.
+// All elements are moved by a single new version, which is returned.
+//
+// Complexity: O(n * log(n) + k), where n - size of structure and k - complexity of ToGoSlice.
+func SortSlice[T any](s Transformable[T], version uint64, cmp func(a, b T) int) (uint64, error) {
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return 0, err
+	}
+
+	slices.SortStableFunc(values, cmp)
+
+	return s.replaceValues(version, values)
+}
+
+// SliceMap creates new Slice, which initial version has results of fn for elements of s of given version
+// in the same order. The new Slice is configured by opts.
+//
+// Complexity: O(n + k), where n - size of s and k - complexity of ToGoSlice.
+func SliceMap[T, U any](s Indexed[T], version uint64, fn func(val T) U, opts ...SliceOption) (*Slice[U], error) {
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return nil, err
+	}
+
+	mapped := make([]U, len(values))
+	for i, val := range values {
+		mapped[i] = fn(val)
+	}
+
+	return newSliceOfValues(mapped, opts...), nil
+}
+
+// Reduce folds elements of s of given version in order into accumulator, which starts from init.
+//
+// Complexity: O(n + k), where n - size of s and k - complexity of ToGoSlice.
+func Reduce[T, A any](s Indexed[T], version uint64, init A, fn func(acc A, val T) A) (A, error) {
+	values, err := s.ToGoSlice(version)
+	if err != nil {
+		return init, err
+	}
+
+	acc := init
+	for _, val := range values {
+		acc = fn(acc, val)
+	}
+
+	return acc, nil
+}
+
+// ReduceKeyed folds keys and values of m of given version into accumulator, which starts from init.
+// Keys are visited in unspecified order.
+//
+// Complexity: O(n + k), where n - size of m and k - complexity of ToGoMap.
+func ReduceKeyed[K comparable, V, A any](m Keyed[K, V], version uint64, init A, fn func(acc A, key K, val V) A) (A, error) {
+	values, err := m.ToGoMap(version)
+	if err != nil {
+		return init, err
+	}
+
+	acc := init
+	for key, val := range values {
+		acc = fn(acc, key, val)
+	}
+
+	return acc, nil
+}
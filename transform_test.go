@@ -0,0 +1,211 @@
+package go_persistent_ds
+
+import (
+	"bytes"
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestMapValues(t *testing.T) {
+	for name, opts := range map[string][]MapOption{"Cells": nil, "HAMT": {WithHAMT()}} {
+		t.Run(name, func(t *testing.T) {
+			m, version := NewMap[string, int](opts...)
+
+			var err error
+			for i := 0; i < 5; i++ {
+				version, err = m.Set(version, strconv.Itoa(i), i)
+				errIsNil(t, err)
+			}
+
+			var events []ChangeEvent
+			m.Subscribe(func(ev ChangeEvent) {
+				events = append(events, ev)
+			})
+
+			doubled, err := MapValues(m, version, func(_ string, val int) int { return val * 2 })
+			errIsNil(t, err)
+			versionShouldBe(t, doubled, version+1)
+			isTrue(t, len(events) == 1 && events[0].Op == OpReplace && events[0].Count == 5)
+
+			filtered, err := FilterKeyed(m, doubled, func(_ string, val int) bool { return val%4 == 0 })
+			errIsNil(t, err)
+			versionShouldBe(t, filtered, doubled+1)
+
+			values, err := m.ToGoMap(filtered)
+			errIsNil(t, err)
+			isTrue(t, maps.Equal(values, map[string]int{"0": 0, "2": 4, "4": 8}))
+
+			original, err := m.ToGoMap(version)
+			errIsNil(t, err)
+			isTrue(t, maps.Equal(original, map[string]int{"0": 0, "1": 1, "2": 2, "3": 3, "4": 4}))
+
+			size, err := m.Len(filtered)
+			errIsNil(t, err)
+			isTrue(t, size == 3)
+
+			sum, err := ReduceKeyed[string, int](m, doubled, 0, func(acc int, _ string, val int) int { return acc + val })
+			errIsNil(t, err)
+			isTrue(t, sum == 20)
+
+			rebuilt, rebuiltVersion := NewMap[string, int](opts...)
+			for key, val := range values {
+				rebuiltVersion, err = rebuilt.Set(rebuiltVersion, key, val)
+				errIsNil(t, err)
+			}
+			hash, ok := m.ContentHash(filtered)
+			isTrue(t, ok)
+			rebuiltHash, ok := rebuilt.ContentHash(rebuiltVersion)
+			isTrue(t, ok && hash == rebuiltHash)
+
+			mapping := make(map[uint64]uint64)
+			compacted, err := m.Compact(version, WithVersionMapping(mapping))
+			errIsNil(t, err)
+			equal, err := EqualKeyed[string, int](m, filtered, compacted, mapping[filtered])
+			errIsNil(t, err)
+			isTrue(t, equal)
+
+			_, err = MapValues(m, filtered+1, func(_ string, val int) int { return val })
+			errShouldBe(t, err, ErrVersionNotFound)
+		})
+	}
+}
+
+func TestMapValues_Replication(t *testing.T) {
+	primary, version := NewMap[string, int]()
+	follower, _ := NewMap[string, int]()
+
+	var err error
+	for i := 0; i < 3; i++ {
+		version, err = primary.Set(version, strconv.Itoa(i), i)
+		errIsNil(t, err)
+	}
+	_, err = FilterKeyed(primary, version, func(key string, _ int) bool { return key != "1" })
+	errIsNil(t, err)
+	_, err = FilterKeyed(primary, version, func(string, int) bool { return false })
+	errIsNil(t, err)
+
+	var ops bytes.Buffer
+	errIsNil(t, primary.ExportOps(&ops, 0))
+	batch := ops.Bytes()
+
+	_, err = follower.ApplyOps(bytes.NewReader(batch))
+	errIsNil(t, err)
+	_, err = follower.ApplyOps(bytes.NewReader(batch))
+	errIsNil(t, err)
+
+	mapsShouldBeReplicas(t, primary, follower)
+}
+
+func TestFilter_SortSlice(t *testing.T) {
+	for name, create := range transformables() {
+		t.Run(name, func(t *testing.T) {
+			s, push := create()
+
+			var (
+				version uint64
+				err     error
+			)
+			for _, val := range []int{5, 2, 8, 1, 9, 4} {
+				version, err = push(version, val)
+				errIsNil(t, err)
+			}
+
+			var events []ChangeEvent
+			s.(interface {
+				Subscribe(handler func(ChangeEvent)) func()
+			}).Subscribe(func(ev ChangeEvent) {
+				events = append(events, ev)
+			})
+
+			sorted, err := SortSlice(s, version, cmp.Compare[int])
+			errIsNil(t, err)
+			versionShouldBe(t, sorted, version+1)
+
+			filtered, err := Filter(s, sorted, func(val int) bool { return val%2 == 0 })
+			errIsNil(t, err)
+			versionShouldBe(t, filtered, sorted+1)
+
+			values, err := s.ToGoSlice(filtered)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []int{2, 4, 8}))
+
+			values, err = s.ToGoSlice(version)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(values, []int{5, 2, 8, 1, 9, 4}))
+
+			isTrue(t, len(events) == 2 && events[0].Op == OpReplace && events[1].Count == 3)
+
+			empty, err := Filter(s, filtered, func(int) bool { return false })
+			errIsNil(t, err)
+			size, err := s.Len(empty)
+			errIsNil(t, err)
+			isTrue(t, size == 0)
+
+			val, err := s.Get(filtered, 2)
+			errIsNil(t, err)
+			isTrue(t, val == 8)
+
+			equal, err := Equal[int](s, filtered, s, sorted)
+			errIsNil(t, err)
+			isTrue(t, !equal)
+
+			sum, err := Reduce[int](s, version, 0, func(acc, val int) int { return acc + val })
+			errIsNil(t, err)
+			isTrue(t, sum == 29)
+
+			strs, err := SliceMap(s, filtered, strconv.Itoa, WithRRBTree())
+			errIsNil(t, err)
+			versionShouldBe(t, strs.LastVersion(), 0)
+			strValues, err := strs.ToGoSlice(0)
+			errIsNil(t, err)
+			isTrue(t, slices.Equal(strValues, []string{"2", "4", "8"}))
+
+			_, err = SortSlice(s, empty+1, cmp.Compare[int])
+			errShouldBe(t, err, ErrVersionNotFound)
+		})
+	}
+}
+
+// transformables returns constructors of all Transformable structures with the function, that adds value to the end.
+func transformables() map[string]func() (Transformable[int], func(v uint64, val int) (uint64, error)) {
+	return map[string]func() (Transformable[int], func(v uint64, val int) (uint64, error)){
+		"Slice with Cells": func() (Transformable[int], func(v uint64, val int) (uint64, error)) {
+			s, _ := NewSlice[int]()
+			return s, s.Append
+		},
+		"Slice with RRB-tree": func() (Transformable[int], func(v uint64, val int) (uint64, error)) {
+			s, _ := NewSlice[int](WithRRBTree())
+			return s, s.Append
+		},
+		"DoubleLinkedList": func() (Transformable[int], func(v uint64, val int) (uint64, error)) {
+			l, _ := NewDoubleLinkedList[int]()
+			return l, l.PushBack
+		},
+	}
+}
+
+func TestSliceMap_Cells(t *testing.T) {
+	l, version := NewDoubleLinkedList[int]()
+	version, err := l.PushBack(version, 1)
+	errIsNil(t, err)
+
+	s, err := SliceMap(l, version, func(val int) float64 { return float64(val) / 2 })
+	errIsNil(t, err)
+
+	version, err = s.Append(0, 1)
+	errIsNil(t, err)
+	values, err := s.ToGoSlice(version)
+	errIsNil(t, err)
+	isTrue(t, slices.Equal(values, []float64{0.5, 1}))
+
+	hash, ok := s.ContentHash(0)
+	isTrue(t, ok)
+	expected, _ := NewSlice[float64]()
+	expectedVersion, err := expected.Append(0, 0.5)
+	errIsNil(t, err)
+	expectedHash, ok := expected.ContentHash(expectedVersion)
+	isTrue(t, ok && hash == expectedHash)
+}
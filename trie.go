@@ -0,0 +1,477 @@
+package go_persistent_ds
+
+import (
+	"iter"
+	"strings"
+
+	"github.com/AleksandrMatsko/go-persistent-ds/internal"
+)
+
+// Trie is a persistent radix tree with string keys.
+// While working with trie you can access and/or modify each previous version.
+// Note that modifying version creates new one.
+//
+// Each version keeps the root of immutable radix tree. Modification copies only the nodes on the path
+// to the changed key, other nodes are shared between versions, so scanning keys by prefix
+// is equally cheap for any version.
+//
+// Trie can create total of 2^64-1 versions, and returns ErrVersionsExhausted on attempt to modify it further.
+// Quotas on amount and depth of versions can be enforced with SetLimits.
+// If you need to continue editing Trie, the good idea is to use Compact method, that creates new Trie
+// with special version as the initial one.
+//
+// Note that Trie is not thread safe.
+type Trie[V any] struct {
+	versionHistory[trieVersionInfo[V]]
+}
+
+type trieVersionInfo[V any] struct {
+	root *trieNode[V]
+	size int
+	hash unorderedHash
+}
+
+// trieNode is a node of radix tree. Nodes are never changed after creation.
+type trieNode[V any] struct {
+	// prefix is a label of the edge leading to the node.
+	prefix   string
+	value    V
+	hasValue bool
+	// children are sorted by the first byte of their prefixes, that are unique among children.
+	children []*trieNode[V]
+}
+
+// NewTrie creates empty Trie.
+func NewTrie[V any]() (*Trie[V], uint64) {
+	t := &Trie[V]{
+		versionHistory: newVersionHistory(internal.NewVersionTree[trieVersionInfo[V]]()),
+	}
+
+	_ = t.versionTree.SetVersionInfo(0, trieVersionInfo[V]{
+		root: &trieNode[V]{},
+	})
+
+	return t, 0
+}
+
+// NewTrieWithAnyValues creates a Trie, that can store values of any type.
+func NewTrieWithAnyValues() (*Trie[any], uint64) {
+	return NewTrie[any]()
+}
+
+// Get returns a pair of value and error for provided version and key.
+// If error is nil then the value for such key and version exists.
+//
+// Complexity: O(l), where l - length of the key.
+func (t *Trie[V]) Get(version uint64, key string) (V, error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return *new(V), err
+	}
+
+	node, rest := info.root, key
+	for rest != "" {
+		child, _, found := node.child(rest[0])
+		if !found || !strings.HasPrefix(rest, child.prefix) {
+			return *new(V), newKeyError(version, key)
+		}
+
+		rest = rest[len(child.prefix):]
+		node = child
+	}
+
+	if !node.hasValue {
+		return *new(V), newKeyError(version, key)
+	}
+
+	return node.value, nil
+}
+
+// Set value for given key and version in Trie. Returns Trie's new version.
+//
+// Complexity: O(l), where l - length of the key.
+func (t *Trie[V]) Set(version uint64, key string, val V) (uint64, error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	newInfo := trieVersionInfo[V]{
+		size: info.size,
+		hash: info.hash.add(key, val),
+	}
+	event := ChangeEvent{Op: OpSet, Key: key, NewValue: val}
+	if oldVal, getErr := t.Get(version, key); getErr == nil {
+		newInfo.hash = newInfo.hash.remove(key, oldVal)
+		event.OldValue = oldVal
+	}
+
+	newRoot, added := info.root.set(key, val)
+	newInfo.root = newRoot
+	if added {
+		newInfo.size++
+	}
+
+	return t.commit(version, newInfo, event)
+}
+
+// Delete the value from Trie for given key for given version. Returns Trie's new version.
+// If there is no such key ErrNotFound is returned.
+//
+// Complexity: O(l), where l - length of the key.
+func (t *Trie[V]) Delete(version uint64, key string) (uint64, error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	oldVal, err := t.Get(version, key)
+	if err != nil {
+		return 0, err
+	}
+
+	newRoot, _ := info.root.delete(key, true)
+
+	return t.commit(version, trieVersionInfo[V]{
+		root: newRoot,
+		size: info.size - 1,
+		hash: info.hash.remove(key, oldVal),
+	}, ChangeEvent{Op: OpDelete, Key: key, OldValue: oldVal})
+}
+
+// Len returns the amount of keys in Trie.
+//
+// Complexity: O(1).
+func (t *Trie[V]) Len(version uint64) (int, error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.size, nil
+}
+
+// LongestPrefix finds the longest key in Trie, that is a prefix of given key.
+// Returns found key and its value. If there is no such key ErrNotFound is returned.
+//
+// Complexity: O(l), where l - length of the key.
+func (t *Trie[V]) LongestPrefix(version uint64, key string) (string, V, error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return "", *new(V), err
+	}
+
+	var (
+		found      bool
+		foundLen   int
+		foundValue V
+	)
+
+	node, consumed := info.root, 0
+	for {
+		if node.hasValue {
+			found, foundLen, foundValue = true, consumed, node.value
+		}
+
+		rest := key[consumed:]
+		if rest == "" {
+			break
+		}
+
+		child, _, ok := node.child(rest[0])
+		if !ok || !strings.HasPrefix(rest, child.prefix) {
+			break
+		}
+
+		node, consumed = child, consumed+len(child.prefix)
+	}
+
+	if !found {
+		return "", *new(V), newKeyError(version, key)
+	}
+
+	return key[:foundLen], foundValue, nil
+}
+
+// WalkPrefix returns iterator over all keys of given version, that start with prefix, and their values.
+// Keys are iterated in lexicographical order. Empty prefix means iterating over all keys.
+//
+// Complexity: O(l + p), where l - length of the prefix and p - size of the iterated part of Trie.
+func (t *Trie[V]) WalkPrefix(version uint64, prefix string) (iter.Seq2[string, V], error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	node, nodeKey := info.root, ""
+	for rest := prefix; rest != "" && node != nil; {
+		child, _, found := node.child(rest[0])
+		switch {
+		case !found:
+			node = nil
+		case strings.HasPrefix(rest, child.prefix):
+			node, nodeKey, rest = child, nodeKey+child.prefix, rest[len(child.prefix):]
+		case strings.HasPrefix(child.prefix, rest):
+			node, nodeKey, rest = child, nodeKey+child.prefix, ""
+		default:
+			node = nil
+		}
+	}
+
+	return func(yield func(string, V) bool) {
+		if node != nil {
+			node.walk(nodeKey, yield)
+		}
+	}, nil
+}
+
+// ToGoMap converts persistent Trie for specified version into go map.
+//
+// Complexity: O(n).
+func (t *Trie[V]) ToGoMap(version uint64) (map[string]V, error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	resMap := make(map[string]V, info.size)
+	info.root.walk("", func(key string, val V) bool {
+		resMap[key] = val
+		return true
+	})
+
+	return resMap, nil
+}
+
+// Equal reports whether versions v1 and v2 of Trie contain equal keys and values.
+// Comparable values are compared with ==, other values are compared with reflect.DeepEqual.
+//
+// Complexity: O(1) if sizes or content hashes of versions differ, otherwise O(n).
+func (t *Trie[V]) Equal(v1, v2 uint64) (bool, error) {
+	info1, err := t.versionTree.GetVersionInfo(v1)
+	if err != nil {
+		return false, err
+	}
+
+	info2, err := t.versionTree.GetVersionInfo(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if info1.size != info2.size || hashesDiffer(info1.hash, info2.hash) {
+		return false, nil
+	}
+
+	if v1 == v2 {
+		return true, nil
+	}
+
+	return equalMaps(t.ToGoMap, v1, v2)
+}
+
+// ContentHash returns hash of keys and values of Trie of given version, that is maintained on each modification.
+// Versions with equal keys and values have equal hashes. Hash is unknown, if some value is not comparable.
+// If version doesn't exist or hash is unknown, false is returned.
+//
+// Complexity: O(1).
+func (t *Trie[V]) ContentHash(version uint64) (uint64, bool) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return 0, false
+	}
+
+	return info.hash.contentHash()
+}
+
+// Compact creates new Trie, which initial version has content of given version and shares it with that version.
+// With WithVersionMapping option descendants of version are retained as well.
+//
+// Complexity: O(1), with WithVersionMapping O(k), where k - amount of versions created after given one.
+func (t *Trie[V]) Compact(version uint64, opts ...CompactOption) (*Trie[V], error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	compacted, _ := NewTrie[V]()
+	_ = compacted.versionTree.SetVersionInfo(0, *info)
+
+	if err = t.retainDescendants(compacted.versionHistory, version, opts); err != nil {
+		return nil, err
+	}
+
+	return compacted, nil
+}
+
+// Fork creates new Trie, which initial version shares content of given version without copying it.
+// Modifications of the new Trie don't create versions of the original one, so after Fork returns
+// both of them can be modified concurrently.
+//
+// Complexity: O(1).
+func (t *Trie[V]) Fork(version uint64) (*Trie[V], error) {
+	info, err := t.versionTree.GetVersionInfo(version)
+	if err != nil {
+		return nil, err
+	}
+
+	forked, _ := NewTrie[V]()
+	_ = forked.versionTree.SetVersionInfo(0, *info)
+
+	return forked, nil
+}
+
+func (t *Trie[V]) commit(version uint64, info trieVersionInfo[V], event ChangeEvent) (uint64, error) {
+	newVersion, err := t.versionTree.Update(version)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = t.versionTree.SetVersionInfo(newVersion, info)
+
+	t.notify(version, newVersion, event)
+
+	return newVersion, nil
+}
+
+// child finds child, which prefix starts with given byte. Returns the child and its position.
+// If there is no such child, the returned position is the place to insert it.
+func (n *trieNode[V]) child(b byte) (*trieNode[V], int, bool) {
+	left, right := 0, len(n.children)
+	for left < right {
+		mid := left + (right-left)/2
+		if n.children[mid].prefix[0] < b {
+			left = mid + 1
+		} else {
+			right = mid
+		}
+	}
+
+	if left < len(n.children) && n.children[left].prefix[0] == b {
+		return n.children[left], left, true
+	}
+
+	return nil, left, false
+}
+
+func (n *trieNode[V]) clone() *trieNode[V] {
+	c := *n
+	c.children = append([]*trieNode[V](nil), n.children...)
+	return &c
+}
+
+// set returns copy of the node with the value for the key, that is relative to the node.
+// Also reports if the key was added.
+func (n *trieNode[V]) set(key string, val V) (*trieNode[V], bool) {
+	if key == "" {
+		c := n.clone()
+		c.value, c.hasValue = val, true
+		return c, !n.hasValue
+	}
+
+	child, pos, found := n.child(key[0])
+	c := n.clone()
+
+	if !found {
+		leaf := &trieNode[V]{prefix: key, value: val, hasValue: true}
+		c.children = append(c.children[:pos], append([]*trieNode[V]{leaf}, c.children[pos:]...)...)
+		return c, true
+	}
+
+	common := commonPrefixLen(key, child.prefix)
+	if common == len(child.prefix) {
+		newChild, added := child.set(key[common:], val)
+		c.children[pos] = newChild
+		return c, added
+	}
+
+	// the key diverges in the middle of the child's prefix, so the edge is split
+	movedChild := child.clone()
+	movedChild.prefix = child.prefix[common:]
+
+	split := &trieNode[V]{prefix: child.prefix[:common]}
+	if common == len(key) {
+		split.value, split.hasValue = val, true
+		split.children = []*trieNode[V]{movedChild}
+	} else {
+		leaf := &trieNode[V]{prefix: key[common:], value: val, hasValue: true}
+		split.children = []*trieNode[V]{movedChild, leaf}
+		if leaf.prefix[0] < movedChild.prefix[0] {
+			split.children[0], split.children[1] = leaf, movedChild
+		}
+	}
+
+	c.children[pos] = split
+	return c, true
+}
+
+// delete returns copy of the node without the key, that is relative to the node.
+// Also reports if the key was removed. Nodes, that are not root, are compacted, so nil can be returned.
+func (n *trieNode[V]) delete(key string, isRoot bool) (*trieNode[V], bool) {
+	var c *trieNode[V]
+
+	if key == "" {
+		if !n.hasValue {
+			return n, false
+		}
+
+		c = n.clone()
+		c.value, c.hasValue = *new(V), false
+	} else {
+		child, pos, found := n.child(key[0])
+		if !found || !strings.HasPrefix(key, child.prefix) {
+			return n, false
+		}
+
+		newChild, removed := child.delete(key[len(child.prefix):], false)
+		if !removed {
+			return n, false
+		}
+
+		c = n.clone()
+		if newChild == nil {
+			c.children = append(c.children[:pos], c.children[pos+1:]...)
+		} else {
+			c.children[pos] = newChild
+		}
+	}
+
+	if isRoot || c.hasValue {
+		return c, true
+	}
+
+	switch len(c.children) {
+	case 0:
+		return nil, true
+	case 1:
+		merged := c.children[0].clone()
+		merged.prefix = c.prefix + merged.prefix
+		return merged, true
+	default:
+		return c, true
+	}
+}
+
+// walk calls yield for each key in the subtree of the node in lexicographical order.
+// Returns false if yield asked to stop.
+func (n *trieNode[V]) walk(key string, yield func(string, V) bool) bool {
+	if n.hasValue && !yield(key, n.value) {
+		return false
+	}
+
+	for _, child := range n.children {
+		if !child.walk(key+child.prefix, yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
@@ -0,0 +1,124 @@
+package go_persistent_ds
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func getTrie(t *testing.T, keys ...string) (*Trie[int], uint64) {
+	trie, version := NewTrie[int]()
+
+	var err error
+	for i, key := range keys {
+		version, err = trie.Set(version, key, i)
+		errIsNil(t, err)
+	}
+
+	return trie, version
+}
+
+func walkedKeys(t *testing.T, trie *Trie[int], version uint64, prefix string) []string {
+	seq, err := trie.WalkPrefix(version, prefix)
+	errIsNil(t, err)
+
+	var keys []string
+	for key := range seq {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func TestTrie_GetSetDelete(t *testing.T) {
+	trie, version := getTrie(t, "tenant/app/a", "tenant/app/b", "tenant/other", "tenant", "te")
+
+	size, err := trie.Len(version)
+	errIsNil(t, err)
+	isTrue(t, size == 5)
+
+	for i, key := range []string{"tenant/app/a", "tenant/app/b", "tenant/other", "tenant", "te"} {
+		val, err := trie.Get(version, key)
+		errIsNil(t, err)
+		isTrue(t, val == i)
+	}
+
+	for _, key := range []string{"", "t", "tenant/", "tenant/app", "tenant/app/c", "tenant/app/a/b"} {
+		_, err = trie.Get(version, key)
+		errShouldBe(t, err, ErrNotFound)
+	}
+
+	updated, err := trie.Set(version, "tenant", 10)
+	errIsNil(t, err)
+	size, err = trie.Len(updated)
+	errIsNil(t, err)
+	isTrue(t, size == 5)
+
+	deleted, err := trie.Delete(updated, "tenant/app/a")
+	errIsNil(t, err)
+	deleted, err = trie.Delete(deleted, "te")
+	errIsNil(t, err)
+
+	_, err = trie.Delete(deleted, "te")
+	errShouldBe(t, err, ErrNotFound)
+	_, err = trie.Delete(deleted, "tenant/app")
+	errShouldBe(t, err, ErrNotFound)
+
+	goMap, err := trie.ToGoMap(deleted)
+	errIsNil(t, err)
+	isTrue(t, maps.Equal(goMap, map[string]int{"tenant/app/b": 1, "tenant/other": 2, "tenant": 10}))
+
+	goMap, err = trie.ToGoMap(version)
+	errIsNil(t, err)
+	isTrue(t, maps.Equal(goMap, map[string]int{
+		"tenant/app/a": 0, "tenant/app/b": 1, "tenant/other": 2, "tenant": 3, "te": 4,
+	}))
+
+	_, err = trie.Get(100, "te")
+	isTrue(t, err != nil)
+}
+
+func TestTrie_LongestPrefix(t *testing.T) {
+	trie, version := getTrie(t, "tenant", "tenant/app", "tenant/app/setting")
+
+	key, val, err := trie.LongestPrefix(version, "tenant/app/set")
+	errIsNil(t, err)
+	isTrue(t, key == "tenant/app")
+	isTrue(t, val == 1)
+
+	key, _, err = trie.LongestPrefix(version, "tenant/app/setting/nested")
+	errIsNil(t, err)
+	isTrue(t, key == "tenant/app/setting")
+
+	key, _, err = trie.LongestPrefix(version, "tenant/ap")
+	errIsNil(t, err)
+	isTrue(t, key == "tenant")
+
+	_, _, err = trie.LongestPrefix(version, "ten")
+	errShouldBe(t, err, ErrNotFound)
+}
+
+func TestTrie_WalkPrefix(t *testing.T) {
+	trie, version := getTrie(t, "b/x", "a/z", "a/y/1", "a/y", "c", "a/yy")
+
+	isTrue(t, slices.Equal(walkedKeys(t, trie, version, ""), []string{"a/y", "a/y/1", "a/yy", "a/z", "b/x", "c"}))
+	isTrue(t, slices.Equal(walkedKeys(t, trie, version, "a/"), []string{"a/y", "a/y/1", "a/yy", "a/z"}))
+	isTrue(t, slices.Equal(walkedKeys(t, trie, version, "a/y"), []string{"a/y", "a/y/1", "a/yy"}))
+	isTrue(t, slices.Equal(walkedKeys(t, trie, version, "a/y/"), []string{"a/y/1"}))
+	isTrue(t, len(walkedKeys(t, trie, version, "d")) == 0)
+	isTrue(t, len(walkedKeys(t, trie, version, "a/yx")) == 0)
+
+	// historical versions are scanned as they were
+	isTrue(t, slices.Equal(walkedKeys(t, trie, 3, "a"), []string{"a/y/1", "a/z"}))
+
+	seq, err := trie.WalkPrefix(version, "a")
+	errIsNil(t, err)
+	count := 0
+	for range seq {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	isTrue(t, count == 2)
+}